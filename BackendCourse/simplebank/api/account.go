@@ -6,13 +6,16 @@ import (
 
 	"github.com/lib/pq"
 	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
 
 	"github.com/gin-gonic/gin"
 )
 
+// An empty type opens a checking account
 type CreateAccountRequest struct {
 	Owner    string `json:"owner" binding:"required"`
 	Currency string `json:"currency" binding:"required"`
+	Type     string `json:"type" binding:"omitempty,oneof=checking savings"`
 }
 
 // This is one API handler function that handles the creation of a new account.
@@ -39,10 +42,17 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		Owner:    req.Owner,
 		Currency: req.Currency,
 		Balance:  0,
+		Type:     req.Type,
+	}
+	if arg.Type == "" {
+		arg.Type = util.CheckingAccount
 	}
 
-	// Call the store to create the account in the database
-	account, err := server.store.CreateAccount(ctx, arg)
+	// Call the store to create the account in the database, with its audit event
+	result, err := server.store.CreateAccountTx(ctx, db.CreateAccountTxParams{
+		CreateAccountParams: arg,
+		Audit:               auditMeta(ctx),
+	})
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
@@ -55,7 +65,13 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, result.Account)
+}
+
+// AccountResponse is an account with its total balance, which includes the balances of its pockets.
+type AccountResponse struct {
+	db.Accounts
+	TotalBalance int64 `json:"total_balance"`
 }
 
 // define the uri parameter
@@ -97,7 +113,17 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	// the money set aside in pockets still belongs to the account
+	pocketsBalance, err := server.store.GetPocketsBalance(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, AccountResponse{
+		Accounts:     account,
+		TotalBalance: account.Balance + pocketsBalance,
+	})
 }
 
 type ListAccountRequest struct {
@@ -138,3 +164,186 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, accounts)
 }
+
+type UpdateAccountOverdraftLimitRequest struct {
+	// a pointer so that an explicit 0 (no overdraft) passes the required check
+	OverdraftLimit *int64 `json:"overdraft_limit" binding:"required,min=0"`
+}
+
+// This is one API handler function that handles setting the overdraft limit of an account.
+// It is called when a PATCH request is made to the /accounts/:id/overdraft_limit endpoint.
+// Only bankers may call it, which is enforced by the role middleware of its route.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.PATCH("/accounts/:id/overdraft_limit", server.updateAccountOverdraftLimit)
+func (server *Server) updateAccountOverdraftLimit(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateAccountOverdraftLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateAccountOverdraftLimitTxParams{
+		UpdateAccountOverdraftLimitParams: db.UpdateAccountOverdraftLimitParams{
+			ID:             uri.ID,
+			OverdraftLimit: *req.OverdraftLimit,
+		},
+		Audit: auditMeta(ctx),
+	}
+
+	account, err := server.store.UpdateAccountOverdraftLimitTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+type UpdateAccountApprovalThresholdRequest struct {
+	// null clears the threshold, so no transfer from the account needs approval
+	ApprovalThreshold *int64 `json:"approval_threshold" binding:"omitempty,min=0"`
+}
+
+// This is one API handler function that handles setting the approval threshold of an account.
+// Transfers from the account above the threshold must be approved by one of its approvers.
+// It is called when a PATCH request is made to the /accounts/:id/approval_threshold endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.PATCH("/accounts/:id/approval_threshold", server.updateAccountApprovalThreshold)
+func (server *Server) updateAccountApprovalThreshold(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateAccountApprovalThresholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateAccountApprovalThresholdTxParams{
+		UpdateAccountApprovalThresholdParams: db.UpdateAccountApprovalThresholdParams{
+			ID:                uri.ID,
+			ApprovalThreshold: nullInt64(req.ApprovalThreshold),
+		},
+		Audit: auditMeta(ctx),
+	}
+
+	account, err := server.store.UpdateAccountApprovalThresholdTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+// This is one API handler function that handles listing the approvers of an account.
+// It is called when a GET request is made to the /accounts/:id/approvers endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/accounts/:id/approvers", server.listAccountApprovers)
+func (server *Server) listAccountApprovers(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	approvers, err := server.store.ListAccountApprovers(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approvers)
+}
+
+type AddAccountApproverRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+}
+
+// This is one API handler function that handles designating a user as an approver of an account.
+// It is called when a POST request is made to the /accounts/:id/approvers endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/accounts/:id/approvers", server.addAccountApprover)
+func (server *Server) addAccountApprover(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req AddAccountApproverRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	approver, err := server.store.AddAccountApproverTx(ctx, db.AccountApproverTxParams{
+		AccountID: uri.ID,
+		Username:  req.Username,
+		Audit:     auditMeta(ctx),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation", "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approver)
+}
+
+type RemoveAccountApproverRequest struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// This is one API handler function that handles removing an approver of an account.
+// Requests already waiting for approval stay pending, but the removed user can't decide on them anymore.
+// It is called when a DELETE request is made to the /accounts/:id/approvers/:username endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.DELETE("/accounts/:id/approvers/:username", server.removeAccountApprover)
+func (server *Server) removeAccountApprover(ctx *gin.Context) {
+	var uri RemoveAccountApproverRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := server.store.RemoveAccountApproverTx(ctx, db.AccountApproverTxParams{
+		AccountID: uri.ID,
+		Username:  uri.Username,
+		Audit:     auditMeta(ctx),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+// auditActorAnonymous is the actor of the changes made by requests without an access token.
+const auditActorAnonymous = "anonymous"
+
+// auditMeta returns who made the request and where it came from, for the audit events of its changes.
+// The actor is the authenticated user, or anonymous for the routes that don't require a token.
+func auditMeta(ctx *gin.Context) db.AuditMeta {
+	actor := auditActorAnonymous
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		actor = payload.(*token.Payload).Username
+	}
+
+	return db.AuditMeta{
+		Actor:     actor,
+		RequestID: ctx.GetString(requestIDKey),
+		ClientIP:  ctx.ClientIP(),
+	}
+}
+
+// All the filters are optional. The range is of whole UTC days, both included.
+type ListAuditEventsRequest struct {
+	PageID     int32  `form:"page_id" binding:"required,min=1"`
+	PageSize   int32  `form:"page_size" binding:"required,min=5,max=100"`
+	Actor      string `form:"actor" binding:"omitempty,max=64"`
+	Action     string `form:"action" binding:"omitempty,max=64"`
+	TargetType string `form:"target_type" binding:"omitempty,max=64"`
+	TargetID   string `form:"target_id" binding:"omitempty,max=64"`
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// This is one API handler function that handles searching the audit log, newest events first.
+// It is called when a GET request is made to the /audit_events endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/audit_events", server.listAuditEvents)
+func (server *Server) listAuditEvents(ctx *gin.Context) {
+	var req ListAuditEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListAuditEventsParams{
+		Actor:      nullString(req.Actor),
+		Action:     nullString(req.Action),
+		TargetType: nullString(req.TargetType),
+		TargetID:   nullString(req.TargetID),
+		MaxRows:    req.PageSize,
+		SkipRows:   (req.PageID - 1) * req.PageSize,
+	}
+
+	// both are valid dates, the binding checked them
+	if req.From != "" {
+		from, _ := time.Parse(time.DateOnly, req.From)
+		arg.FromTime = sql.NullTime{Time: from, Valid: true}
+	}
+	if req.To != "" {
+		to, _ := time.Parse(time.DateOnly, req.To)
+		arg.ToTime = sql.NullTime{Time: to.AddDate(0, 0, 1), Valid: true}
+	}
+	if arg.FromTime.Valid && arg.ToTime.Valid && !arg.ToTime.Time.After(arg.FromTime.Time) {
+		err := errors.New("to must not be before from")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+// This is one API handler function that handles checking that the audit log wasn't tampered with:
+// the hash of every event is computed again and must chain to the event before it.
+// It is called when a GET request is made to the /audit_events/verify endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/audit_events/verify", server.verifyAuditChain)
+func (server *Server) verifyAuditChain(ctx *gin.Context) {
+	report, err := server.store.VerifyAuditChain(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,376 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// testRequestID is sent as the request ID by the tests that check the audit metadata of a change.
+const testRequestID = "test-request"
+
+// testAuditMeta is the audit metadata of a test request made by the actor.
+// Test requests have no remote address, so no client IP.
+func testAuditMeta(actor string) db.AuditMeta {
+	return db.AuditMeta{Actor: actor, RequestID: testRequestID}
+}
+
+func TestCreateAccountAuditAPI(t *testing.T) {
+	account := randomAccount()
+	account.Type = util.CheckingAccount
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	arg := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			Owner:    account.Owner,
+			Currency: account.Currency,
+			Type:     util.CheckingAccount,
+		},
+		Audit: testAuditMeta(auditActorAnonymous),
+	}
+	store.EXPECT().
+		CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.CreateAccountTxResult{Account: account}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"owner": account.Owner, "currency": account.Currency})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeaderKey, testRequestID)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchAccount(t, recorder.Body, account)
+}
+
+func TestCreateUserAuditAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		CreateUserTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+			require.Equal(t, username, arg.Username)
+			require.NoError(t, util.CheckPassword("secret123", arg.HashedPassword))
+			require.Equal(t, testAuditMeta(username), arg.Audit)
+			return db.CreateUserTxResult{User: db.Users{Username: username}}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"username":  username,
+		"password":  "secret123",
+		"full_name": "Test User",
+		"email":     util.RandomEmail(),
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeaderKey, testRequestID)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestLoginUserAuditAPI(t *testing.T) {
+	password := util.RandomString(8)
+	hashedPassword, err := util.HashedPassword(password)
+	require.NoError(t, err)
+	user := db.Users{
+		Username:       util.RandomOwner(),
+		HashedPassword: hashedPassword,
+		Role:           util.DepositorRole,
+	}
+
+	testCases := []struct {
+		name          string
+		password      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				arg := db.AuditTxParams{
+					AuditMeta:  testAuditMeta(user.Username),
+					Action:     db.AuditActionUserLogin,
+					TargetType: db.AuditTargetUser,
+					TargetID:   user.Username,
+				}
+				store.EXPECT().AuditTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "WrongPassword",
+			password: "wrong_password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				arg := db.AuditTxParams{
+					AuditMeta:  testAuditMeta(user.Username),
+					Action:     db.AuditActionUserLoginFailed,
+					TargetType: db.AuditTargetUser,
+					TargetID:   user.Username,
+				}
+				store.EXPECT().AuditTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AuditError",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().AuditTx(gomock.Any(), gomock.Any()).Times(1).Return(db.AuditEvents{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "access_token")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"username": user.Username, "password": tc.password})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAuditEventsAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "page_id=2&page_size=10&actor=alice&target_type=account&from=2026-03-01&to=2026-03-31",
+			role:  util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAuditEventsParams{
+					Actor:      sql.NullString{String: "alice", Valid: true},
+					TargetType: sql.NullString{String: "account", Valid: true},
+					FromTime:   sql.NullTime{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					ToTime:     sql.NullTime{Time: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					MaxRows:    10,
+					SkipRows:   10,
+				}
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.AuditEvents{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "NotBanker",
+			query: "page_id=1&page_size=10",
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "ToBeforeFrom",
+			query: "page_id=1&page_size=10&from=2026-03-02&to=2026-03-01",
+			role:  util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/audit_events?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVerifyAuditChainAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	report := db.AuditChainReport{EventCount: 3, Valid: false, BrokenAt: 2}
+	store.EXPECT().VerifyAuditChain(gomock.Any()).Times(1).Return(report, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/audit_events/verify", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got db.AuditChainReport
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.False(t, got.Valid)
+	require.Equal(t, int64(2), got.BrokenAt)
+}
+
+func TestAuditClientIP(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		clientIP       string
+	}{
+		{
+			name:       "NoForwardedFor",
+			remoteAddr: "192.0.2.1:4321",
+			clientIP:   "192.0.2.1",
+		},
+		{
+			name:         "SpoofedForwardedFor",
+			remoteAddr:   "192.0.2.1:4321",
+			forwardedFor: "203.0.113.7",
+			clientIP:     "192.0.2.1",
+		},
+		{
+			name:           "UntrustedProxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "192.0.2.1:4321",
+			forwardedFor:   "203.0.113.7",
+			clientIP:       "192.0.2.1",
+		},
+		{
+			name:           "TrustedProxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.1.2.3:4321",
+			forwardedFor:   "203.0.113.7",
+			clientIP:       "203.0.113.7",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			account := randomAccount()
+			account.Type = util.CheckingAccount
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				CreateAccountTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ interface{}, arg db.CreateAccountTxParams) (db.CreateAccountTxResult, error) {
+					require.Equal(t, tc.clientIP, arg.Audit.ClientIP)
+					return db.CreateAccountTxResult{Account: account}, nil
+				})
+
+			config := util.Config{
+				TokenSymmetricKey:   util.RandomString(32),
+				AccessTokenDuration: time.Minute,
+				TrustedProxies:      tc.trustedProxies,
+			}
+			server, err := NewServer(config, store, notify.NewLogNotifier(), events.NewHub())
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"owner": account.Owner, "currency": account.Currency})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = tc.remoteAddr
+			if tc.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestNewServerInvalidTrustedProxy(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		TrustedProxies:    []string{"not-an-ip"},
+	}
+	_, err := NewServer(config, mockdb.NewMockStore(gomock.NewController(t)), notify.NewLogNotifier(), events.NewHub())
+	require.Error(t, err)
+}
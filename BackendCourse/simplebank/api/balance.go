@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// maxBalanceHistoryDays is the longest range of days a balance history may cover.
+const maxBalanceHistoryDays = 366
+
+// The time is given as RFC 3339, e.g. 2026-03-31T23:59:59Z.
+type GetAccountBalanceRequest struct {
+	At time.Time `form:"at" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type AccountBalanceResponse struct {
+	AccountID int64     `json:"account_id"`
+	Currency  string    `json:"currency"`
+	At        time.Time `json:"at"`
+	Balance   int64     `json:"balance"`
+}
+
+// This is one API handler function that handles the balance an account had at a point in time.
+// It is called when a GET request is made to the /accounts/:id/balance endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/accounts/:id/balance", server.getAccountBalance)
+func (server *Server) getAccountBalance(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req GetAccountBalanceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.At.After(time.Now()) {
+		err := errors.New("at must not be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.userAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	balance, err := server.store.BalanceAt(ctx, account.ID, req.At)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, AccountBalanceResponse{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		At:        req.At,
+		Balance:   balance,
+	})
+}
+
+type DailyBalance struct {
+	Date    string `json:"date"`
+	Balance int64  `json:"balance"`
+}
+
+// This is one API handler function that handles the end of day balances of an account,
+// for every UTC day from the from date to the to date, e.g. for charting.
+// It is called when a GET request is made to the /accounts/:id/balance_history endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/accounts/:id/balance_history", server.listBalanceHistory)
+func (server *Server) listBalanceHistory(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	req, from, to, ok := bindReportRange(ctx)
+	if !ok {
+		return
+	}
+
+	if to.Sub(from) > maxBalanceHistoryDays*24*time.Hour {
+		err := errors.New("the range must not be longer than a year")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.userAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	// the balance at the start of the range, then each day's entries on top of it
+	balance, err := server.store.BalanceAt(ctx, account.ID, from)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totals, err := server.store.ListDailyEntryTotals(ctx, db.ListDailyEntryTotalsParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	dayTotals := make(map[string]int64, len(totals))
+	for _, total := range totals {
+		dayTotals[total.Day.Format(time.DateOnly)] = total.Total
+	}
+
+	history := []DailyBalance{}
+	records := [][]string{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		balance += dayTotals[date]
+		history = append(history, DailyBalance{Date: date, Balance: balance})
+		records = append(records, []string{date, strconv.FormatInt(balance, 10)})
+	}
+
+	sendReport(ctx, req.Format, "balance_history", history, []string{"date", "balance"}, records)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountBalanceAPI(t *testing.T) {
+	account := randomAccount()
+	at := time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		at            string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			at:       at.Format(time.RFC3339),
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().BalanceAt(gomock.Any(), gomock.Eq(account.ID), gomock.Eq(at)).Times(1).Return(int64(420), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got AccountBalanceResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, int64(420), got.Balance)
+				require.True(t, at.Equal(got.At))
+			},
+		},
+		{
+			name:     "NotOwner",
+			at:       at.Format(time.RFC3339),
+			username: "someone_else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().BalanceAt(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Future",
+			at:       time.Now().Add(time.Hour).Format(time.RFC3339),
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BalanceAt(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidTime",
+			at:       "2026-03-31",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BalanceAt(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			u := fmt.Sprintf("/accounts/%d/balance?at=%s", account.ID, url.QueryEscape(tc.at))
+			request, err := http.NewRequest(http.MethodGet, u, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListBalanceHistoryAPI(t *testing.T) {
+	account := randomAccount()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().BalanceAt(gomock.Any(), gomock.Eq(account.ID), gomock.Eq(from)).Times(1).Return(int64(100), nil)
+	store.EXPECT().
+		ListDailyEntryTotals(gomock.Any(), gomock.Eq(db.ListDailyEntryTotalsParams{
+			AccountID: account.ID,
+			FromTime:  from,
+			ToTime:    from.AddDate(0, 0, 3),
+		})).
+		Times(1).
+		Return([]db.ListDailyEntryTotalsRow{
+			{Day: from, Total: 50},
+			{Day: from.AddDate(0, 0, 2), Total: -30},
+		}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	u := fmt.Sprintf("/accounts/%d/balance_history?from=2026-03-01&to=2026-03-03", account.ID)
+	request, err := http.NewRequest(http.MethodGet, u, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []DailyBalance
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, []DailyBalance{
+		{Date: "2026-03-01", Balance: 150},
+		{Date: "2026-03-02", Balance: 150},
+		{Date: "2026-03-03", Balance: 120},
+	}, got)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// An empty currency or account type makes the rule apply to all of them.
+type CreateFeeRuleRequest struct {
+	Currency      string `json:"currency" binding:"omitempty,oneof=USD EUR CAD"`
+	AccountType   string `json:"account_type" binding:"omitempty,oneof=checking savings"`
+	FlatFee       int64  `json:"flat_fee" binding:"min=0"`
+	PercentageBps int64  `json:"percentage_bps" binding:"min=0,max=10000"`
+	MinFee        int64  `json:"min_fee" binding:"min=0"`
+	MaxFee        *int64 `json:"max_fee" binding:"omitempty,min=0"`
+}
+
+// This is one API handler function that handles the creation of a new fee rule.
+// It is called when a POST request is made to the /fee_rules endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/fee_rules", server.createFeeRule)
+func (server *Server) createFeeRule(ctx *gin.Context) {
+	var req CreateFeeRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.MaxFee != nil && *req.MaxFee < req.MinFee {
+		err := errors.New("max_fee must not be lower than min_fee")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateFeeRuleParams{
+		Currency:      sql.NullString{String: req.Currency, Valid: req.Currency != ""},
+		AccountType:   sql.NullString{String: req.AccountType, Valid: req.AccountType != ""},
+		FlatFee:       req.FlatFee,
+		PercentageBps: req.PercentageBps,
+		MinFee:        req.MinFee,
+	}
+	if req.MaxFee != nil {
+		arg.MaxFee = sql.NullInt64{Int64: *req.MaxFee, Valid: true}
+	}
+
+	rule, err := server.store.CreateFeeRuleTx(ctx, db.CreateFeeRuleTxParams{
+		CreateFeeRuleParams: arg,
+		Audit:               auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+type ListFeeRulesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles the retrieval of the fee rules.
+// It is called when a GET request is made to the /fee_rules endpoint, by bankers only.
+func (server *Server) listFeeRules(ctx *gin.Context) {
+	var req ListFeeRulesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListFeeRulesParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	rules, err := server.store.ListFeeRules(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+type FeeRuleRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the deletion of a fee rule.
+// It is called when a DELETE request is made to the /fee_rules/:id endpoint, by bankers only.
+// Fees already charged under the rule are kept.
+func (server *Server) deleteFeeRule(ctx *gin.Context) {
+	var req FeeRuleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteFeeRuleTx(ctx, db.DeleteFeeRuleTxParams{
+		ID:    req.ID,
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
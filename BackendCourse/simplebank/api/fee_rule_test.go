@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFeeRuleAPI(t *testing.T) {
+	rule := db.FeeRules{
+		ID:            util.RandomInt(1, 1000),
+		Currency:      sql.NullString{String: util.USD, Valid: true},
+		FlatFee:       25,
+		PercentageBps: 100,
+		MaxFee:        sql.NullInt64{Int64: 500, Valid: true},
+	}
+	banker := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"currency":       util.USD,
+				"flat_fee":       25,
+				"percentage_bps": 100,
+				"max_fee":        500,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateFeeRuleTxParams{
+					CreateFeeRuleParams: db.CreateFeeRuleParams{
+						Currency:      rule.Currency,
+						FlatFee:       rule.FlatFee,
+						PercentageBps: rule.PercentageBps,
+						MaxFee:        rule.MaxFee,
+					},
+					Audit: testAuditMeta(banker),
+				}
+				store.EXPECT().
+					CreateFeeRuleTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rule, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.FeeRules
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, rule, got)
+			},
+		},
+		{
+			name: "MaxBelowMin",
+			body: gin.H{
+				"min_fee": 100,
+				"max_fee": 50,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRuleTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCurrency",
+			body: gin.H{
+				"currency": "XYZ",
+				"flat_fee": 25,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRuleTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{
+				"flat_fee": 25,
+			},
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRuleTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/fee_rules", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, banker, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
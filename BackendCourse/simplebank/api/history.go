@@ -0,0 +1,117 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+// Both filters are optional: Search matches the description or the reference, ignoring case,
+// and Category must match exactly.
+type SearchAccountHistoryRequest struct {
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=20"`
+	Search   string `form:"q" binding:"omitempty,max=140,memo"`
+	Category string `form:"category" binding:"omitempty,max=32,category"`
+}
+
+// This is one API handler function that handles listing the transfers into or out of an account.
+// It is called when a GET request is made to the /accounts/:id/transfers endpoint, by the owner of the account.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/accounts/:id/transfers", server.listAccountTransfers)
+func (server *Server) listAccountTransfers(ctx *gin.Context) {
+	account, req, ok := server.bindAccountHistory(ctx)
+	if !ok {
+		return
+	}
+
+	transfers, err := server.store.SearchTransfers(ctx, db.SearchTransfersParams{
+		AccountID: account.ID,
+		Category:  nullString(req.Category),
+		Search:    nullString(escapeLike(req.Search)),
+		MaxRows:   req.PageSize,
+		SkipRows:  (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// This is one API handler function that handles listing the entries of an account.
+// It is called when a GET request is made to the /accounts/:id/entries endpoint, by the owner of the account.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/accounts/:id/entries", server.listAccountEntries)
+func (server *Server) listAccountEntries(ctx *gin.Context) {
+	account, req, ok := server.bindAccountHistory(ctx)
+	if !ok {
+		return
+	}
+
+	entries, err := server.store.SearchEntries(ctx, db.SearchEntriesParams{
+		AccountID: account.ID,
+		Category:  nullString(req.Category),
+		Search:    nullString(escapeLike(req.Search)),
+		MaxRows:   req.PageSize,
+		SkipRows:  (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// bindAccountHistory binds the account uri and the search query of a history listing and checks that the
+// account belongs to the authenticated user. If anything fails it writes the error response and returns false.
+func (server *Server) bindAccountHistory(ctx *gin.Context) (db.Accounts, SearchAccountHistoryRequest, bool) {
+	var uri GetAccountRequest
+	var req SearchAccountHistoryRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Accounts{}, req, false
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Accounts{}, req, false
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, req, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, req, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return account, req, false
+	}
+
+	return account, req, true
+}
+
+// likeEscaper escapes the characters that have a meaning in an ILIKE pattern, so the search text matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+// nullString maps an empty string, e.g. a filter left out, to NULL.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// The rate applies to the accounts of the type and currency from the effective day, given as YYYY-MM-DD.
+type CreateInterestRateRequest struct {
+	AccountType   string `json:"account_type" binding:"required,oneof=checking savings"`
+	Currency      string `json:"currency" binding:"required,oneof=USD EUR CAD"`
+	AnnualRateBps int64  `json:"annual_rate_bps" binding:"min=0,max=10000"`
+	EffectiveFrom string `json:"effective_from" binding:"required,datetime=2006-01-02"`
+}
+
+// This is one API handler function that handles setting a new interest rate for a product.
+// Rates can only take effect from today on, so interest that was accrued is never changed.
+// It is called when a POST request is made to the /interest_rates endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/interest_rates", server.createInterestRate)
+func (server *Server) createInterestRate(ctx *gin.Context) {
+	var req CreateInterestRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// accrual dates are UTC days
+	effectiveFrom, _ := time.Parse(time.DateOnly, req.EffectiveFrom)
+	now := time.Now().UTC()
+	if effectiveFrom.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+		err := errors.New("effective_from must not be in the past")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rate, err := server.store.CreateInterestRateTx(ctx, db.CreateInterestRateTxParams{
+		CreateInterestRateParams: db.CreateInterestRateParams{
+			AccountType:   req.AccountType,
+			Currency:      req.Currency,
+			AnnualRateBps: req.AnnualRateBps,
+			EffectiveFrom: effectiveFrom,
+		},
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rate)
+}
+
+type ListInterestRatesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the interest rates of all products, latest first.
+// It is called when a GET request is made to the /interest_rates endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/interest_rates", server.listInterestRates)
+func (server *Server) listInterestRates(ctx *gin.Context) {
+	var req ListInterestRatesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rates, err := server.store.ListInterestRates(ctx, db.ListInterestRatesParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
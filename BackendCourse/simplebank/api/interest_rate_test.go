@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInterestRateAPI(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	effectiveFrom := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	banker := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_type":    util.SavingsAccount,
+				"currency":        util.USD,
+				"annual_rate_bps": 350,
+				"effective_from":  effectiveFrom.Format(time.DateOnly),
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateInterestRateTxParams{
+					CreateInterestRateParams: db.CreateInterestRateParams{
+						AccountType:   util.SavingsAccount,
+						Currency:      util.USD,
+						AnnualRateBps: 350,
+						EffectiveFrom: effectiveFrom,
+					},
+					Audit: testAuditMeta(banker),
+				}
+				store.EXPECT().
+					CreateInterestRateTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.InterestRates{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InThePast",
+			body: gin.H{
+				"account_type":    util.SavingsAccount,
+				"currency":        util.USD,
+				"annual_rate_bps": 350,
+				"effective_from":  yesterday.Format(time.DateOnly),
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SameDayTwice",
+			body: gin.H{
+				"account_type":    util.SavingsAccount,
+				"currency":        util.USD,
+				"annual_rate_bps": 400,
+				"effective_from":  effectiveFrom.Format(time.DateOnly),
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInterestRateTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.InterestRates{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDate",
+			body: gin.H{
+				"account_type":   util.SavingsAccount,
+				"currency":       util.USD,
+				"effective_from": effectiveFrom.Format(time.RFC3339),
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SystemAccounts",
+			body: gin.H{
+				"account_type":   util.SystemAccount,
+				"currency":       util.USD,
+				"effective_from": effectiveFrom.Format(time.DateOnly),
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{
+				"account_type":   util.SavingsAccount,
+				"currency":       util.USD,
+				"effective_from": effectiveFrom.Format(time.DateOnly),
+			},
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/interest_rates", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, banker, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+type CreateDepositRequest struct {
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Currency    string `json:"currency" binding:"required,oneof=USD EUR CAD"`
+	Description string `json:"description" binding:"omitempty,max=140,memo"`
+}
+
+// This is one API handler function that handles cash paid in at the bank to an account.
+// The deposit is booked as a journal debiting cash and crediting the customer's deposits.
+// It is called when a POST request is made to the /accounts/:id/deposits endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/accounts/:id/deposits", server.createDeposit)
+func (server *Server) createDeposit(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req CreateDepositRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.validAccount(ctx, uri.ID, req.Currency)
+	if !valid {
+		return
+	}
+
+	if account.Type == util.SystemAccount {
+		err := errors.New("deposits can't be made to the bank's own accounts")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.DepositTx(ctx, db.DepositTxParams{
+		AccountID:   account.ID,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Audit:       auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// This is one API handler function that handles listing the chart of accounts of the general ledger.
+// It is called when a GET request is made to the /gl_accounts endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/gl_accounts", server.listGLAccounts)
+func (server *Server) listGLAccounts(ctx *gin.Context) {
+	accounts, err := server.store.ListGLAccounts(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accounts)
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// The loan is paid into the account and repaid from it in monthly installments.
+type CreateLoanRequest struct {
+	AccountID     int64 `json:"account_id" binding:"required,min=1"`
+	Principal     int64 `json:"principal" binding:"required,gt=0"`
+	AnnualRateBps int64 `json:"annual_rate_bps" binding:"min=0,max=10000"`
+	TermMonths    int32 `json:"term_months" binding:"required,min=1,max=360"`
+}
+
+// This is one API handler function that handles granting a loan to the owner of an account.
+// The principal is paid into the account right away and the schedule starts today.
+// It is called when a POST request is made to the /loans endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/loans", server.createLoan)
+func (server *Server) createLoan(ctx *gin.Context) {
+	var req CreateLoanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if account.Type == util.SystemAccount {
+		err := errors.New("loans can't be paid into the bank's own accounts")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if account.Type == util.PocketAccount {
+		err := errors.New("loans can only be paid into a checking or savings account")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// due dates are UTC days
+	now := time.Now().UTC()
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.CreateLoanTx(ctx, db.CreateLoanTxParams{
+		AccountID:     account.ID,
+		Principal:     req.Principal,
+		AnnualRateBps: req.AnnualRateBps,
+		TermMonths:    req.TermMonths,
+		StartDate:     time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		CreatedBy:     authPayload.Username,
+		Audit:         auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type ListLoansRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the loans of the authenticated user, latest first.
+// It is called when a GET request is made to the /loans endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/loans", server.listLoans)
+func (server *Server) listLoans(ctx *gin.Context) {
+	var req ListLoansRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	loans, err := server.store.ListLoans(ctx, db.ListLoansParams{
+		Borrower: authPayload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loans)
+}
+
+type LoanURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// The loan holds the outstanding principal; the schedule lists every installment, paid or not.
+type LoanResponse struct {
+	Loan     db.Loans              `json:"loan"`
+	Schedule []db.LoanInstallments `json:"schedule"`
+}
+
+// This is one API handler function that handles the retrieval of a loan with its amortization schedule.
+// It is called when a GET request is made to the /loans/:id endpoint, by the borrower or a banker.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/loans/:id", server.getLoan)
+func (server *Server) getLoan(ctx *gin.Context) {
+	var uri LoanURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	loan, err := server.store.GetLoan(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if loan.Borrower != authPayload.Username && authPayload.Role != util.BankerRole {
+		err := errors.New("loan doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	schedule, err := server.store.ListLoanInstallments(ctx, loan.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, LoanResponse{Loan: loan, Schedule: schedule})
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
@@ -17,7 +19,7 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 		AccessTokenDuration: time.Minute,
 	}
 
-	server, err := NewServer(config, store)
+	server, err := NewServer(config, store, notify.NewLogNotifier(), events.NewHub())
 	require.NoError(t, err)
 
 	return server
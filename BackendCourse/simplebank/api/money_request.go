@@ -0,0 +1,288 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+type CreateMoneyRequestRequest struct {
+	Payer       string `json:"payer" binding:"required,alphanum"`
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Currency    string `json:"currency" binding:"required,oneof=USD EUR CAD"`
+	Description string `json:"description" binding:"omitempty,max=140,memo"`
+}
+
+// This is one API handler function that handles asking another user for money.
+// The request is paid into the authenticated user's account in its currency, once the payer accepts it.
+// It is called when a POST request is made to the /money_requests endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/money_requests", server.createMoneyRequest)
+func (server *Server) createMoneyRequest(ctx *gin.Context) {
+	var req CreateMoneyRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.Payer == authPayload.Username {
+		err := errors.New("can't request money from yourself")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	toAccount, err := server.store.GetAccountByOwnerCurrency(ctx, db.GetAccountByOwnerCurrencyParams{
+		Owner:    authPayload.Username,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := fmt.Errorf("authenticated user has no %s account to be paid into", req.Currency)
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.GetUser(ctx, req.Payer)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	request, err := server.store.CreateMoneyRequest(ctx, db.CreateMoneyRequestParams{
+		Requester:   authPayload.Username,
+		Payer:       req.Payer,
+		ToAccountID: toAccount.ID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: req.Description,
+		ExpiresAt:   time.Now().Add(server.config.MoneyRequestDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notify(ctx, notify.Notification{
+		Username: request.Payer,
+		Kind:     notify.KindMoneyRequestCreated,
+		Message:  fmt.Sprintf("%s requests %d %s from you", request.Requester, request.Amount, request.Currency),
+		Data:     request,
+	})
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// Incoming requests are the ones the authenticated user is asked to pay, outgoing ones the ones they sent.
+type ListMoneyRequestsRequest struct {
+	Direction string `form:"direction" binding:"required,oneof=incoming outgoing"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending accepted declined expired"`
+	PageID    int32  `form:"page_id" binding:"required,min=1"`
+	PageSize  int32  `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the money requests of the authenticated user, latest first.
+// Only pending requests are listed unless another status is asked for.
+// It is called when a GET request is made to the /money_requests endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/money_requests", server.listMoneyRequests)
+func (server *Server) listMoneyRequests(ctx *gin.Context) {
+	var req ListMoneyRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Status == "" {
+		req.Status = db.MoneyRequestPending
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	var requests []db.MoneyRequests
+	var err error
+	if req.Direction == "incoming" {
+		requests, err = server.store.ListIncomingMoneyRequests(ctx, db.ListIncomingMoneyRequestsParams{
+			Payer:  authPayload.Username,
+			Status: req.Status,
+			Limit:  req.PageSize,
+			Offset: (req.PageID - 1) * req.PageSize,
+		})
+	} else {
+		requests, err = server.store.ListOutgoingMoneyRequests(ctx, db.ListOutgoingMoneyRequestsParams{
+			Requester: authPayload.Username,
+			Status:    req.Status,
+			Limit:     req.PageSize,
+			Offset:    (req.PageID - 1) * req.PageSize,
+		})
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+type MoneyRequestURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the retrieval of a money request, by its requester or its payer.
+// It is called when a GET request is made to the /money_requests/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/money_requests/:id", server.getMoneyRequest)
+func (server *Server) getMoneyRequest(ctx *gin.Context) {
+	request, ok := server.partyMoneyRequest(ctx, false)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// AcceptMoneyRequestResponse is a paid money request, with its transfer as the payer's side sees it.
+type AcceptMoneyRequestResponse struct {
+	Request  db.MoneyRequests `json:"request"`
+	Transfer TransferResponse `json:"transfer"`
+}
+
+// This is one API handler function that handles paying a money request, from the payer's account in its currency.
+// It is called when a POST request is made to the /money_requests/:id/accept endpoint, by the payer only.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/money_requests/:id/accept", server.acceptMoneyRequest)
+func (server *Server) acceptMoneyRequest(ctx *gin.Context) {
+	request, ok := server.partyMoneyRequest(ctx, true)
+	if !ok {
+		return
+	}
+
+	fromAccount, err := server.store.GetAccountByOwnerCurrency(ctx, db.GetAccountByOwnerCurrencyParams{
+		Owner:    request.Payer,
+		Currency: request.Currency,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := fmt.Errorf("authenticated user has no %s account to pay from", request.Currency)
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if db.NeedsApproval(fromAccount, request.Amount) {
+		err := errors.New("money requests above the approval threshold must be paid by a transfer")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AcceptMoneyRequestTx(ctx, db.AcceptMoneyRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: fromAccount.ID,
+		Audit:         auditMeta(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrMoneyRequestNotPending) || errors.Is(err, db.ErrMoneyRequestExpired) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		transferErrorResponse(ctx, err)
+		return
+	}
+
+	server.notify(ctx, notify.Notification{
+		Username: request.Requester,
+		Kind:     notify.KindMoneyRequestAccepted,
+		Message:  fmt.Sprintf("%s paid your request of %d %s", request.Payer, request.Amount, request.Currency),
+		Data:     result.Request,
+	})
+
+	ctx.JSON(http.StatusOK, AcceptMoneyRequestResponse{
+		Request:  result.Request,
+		Transfer: fromSideResponse(result.Transfer),
+	})
+}
+
+// This is one API handler function that handles declining a money request; nothing is paid.
+// It is called when a POST request is made to the /money_requests/:id/decline endpoint, by the payer only.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/money_requests/:id/decline", server.declineMoneyRequest)
+func (server *Server) declineMoneyRequest(ctx *gin.Context) {
+	request, ok := server.partyMoneyRequest(ctx, true)
+	if !ok {
+		return
+	}
+
+	request, err := server.store.DecideMoneyRequest(ctx, db.DecideMoneyRequestParams{
+		ID:     request.ID,
+		Status: db.MoneyRequestDeclined,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusForbidden, errorResponse(db.ErrMoneyRequestNotPending))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notify(ctx, notify.Notification{
+		Username: request.Requester,
+		Kind:     notify.KindMoneyRequestDeclined,
+		Message:  fmt.Sprintf("%s declined your request of %d %s", request.Payer, request.Amount, request.Currency),
+		Data:     request,
+	})
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// partyMoneyRequest binds the money request ID from the uri and returns the request if the authenticated user
+// is its payer, or, unless payerOnly, its requester. If anything fails it writes the error response and returns false.
+func (server *Server) partyMoneyRequest(ctx *gin.Context, payerOnly bool) (db.MoneyRequests, bool) {
+	var uri MoneyRequestURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.MoneyRequests{}, false
+	}
+
+	request, err := server.store.GetMoneyRequest(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return request, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return request, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	isParty := request.Payer == authPayload.Username || (!payerOnly && request.Requester == authPayload.Username)
+	if !isParty {
+		err := errors.New("money request isn't addressed to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return request, false
+	}
+
+	return request, true
+}
+
+// notify sends a notification without failing the request if it can't be delivered.
+func (server *Server) notify(ctx *gin.Context, notification notify.Notification) {
+	if err := server.notifier.Notify(ctx, notification); err != nil {
+		log.Printf("cannot notify %s of %s: %v", notification.Username, notification.Kind, err)
+	}
+}
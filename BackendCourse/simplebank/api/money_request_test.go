@@ -0,0 +1,386 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
+	mocknotify "github.com/ofer-sin/Courses/BackendCourse/simplebank/notify/mock"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMoneyRequestAPI(t *testing.T) {
+	account := randomAccount()
+	payer := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"payer": payer, "amount": 25, "currency": account.Currency, "description": "Pizza"},
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				arg := db.GetAccountByOwnerCurrencyParams{Owner: account.Owner, Currency: account.Currency}
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer)).Times(1).Return(db.Users{Username: payer}, nil)
+				store.EXPECT().
+					CreateMoneyRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateMoneyRequestParams) (db.MoneyRequests, error) {
+						require.Equal(t, account.Owner, arg.Requester)
+						require.Equal(t, payer, arg.Payer)
+						require.Equal(t, account.ID, arg.ToAccountID)
+						require.Equal(t, int64(25), arg.Amount)
+						require.Equal(t, "Pizza", arg.Description)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+						return db.MoneyRequests{ID: 1, Requester: arg.Requester, Payer: arg.Payer}, nil
+					})
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, notification notify.Notification) error {
+						require.Equal(t, payer, notification.Username)
+						require.Equal(t, notify.KindMoneyRequestCreated, notification.Kind)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotifyFails",
+			body: gin.H{"payer": payer, "amount": 25, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().CreateMoneyRequest(gomock.Any(), gomock.Any()).Times(1)
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(1).Return(fmt.Errorf("unreachable"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "FromSelf",
+			body: gin.H{"payer": account.Owner, "amount": 25, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().CreateMoneyRequest(gomock.Any(), gomock.Any()).Times(0)
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAccountInCurrency",
+			body: gin.H{"payer": payer, "amount": 25, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().
+					GetAccountByOwnerCurrency(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Accounts{}, sql.ErrNoRows)
+				store.EXPECT().CreateMoneyRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "PayerNotFound",
+			body: gin.H{"payer": payer, "amount": 25, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.Users{}, sql.ErrNoRows)
+				store.EXPECT().CreateMoneyRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			body: gin.H{"payer": payer, "amount": -1, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().CreateMoneyRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			notifier := mocknotify.NewMockNotifier(ctrl)
+			tc.buildStubs(store, notifier)
+
+			server := newTestServer(t, store)
+			server.notifier = notifier
+			server.config.MoneyRequestDuration = time.Hour
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/money_requests", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAcceptMoneyRequestAPI(t *testing.T) {
+	payerAccount := randomAccount()
+	moneyRequest := db.MoneyRequests{
+		ID:          7,
+		Requester:   util.RandomOwner(),
+		Payer:       payerAccount.Owner,
+		ToAccountID: payerAccount.ID + 1,
+		Amount:      40,
+		Currency:    payerAccount.Currency,
+		Status:      db.MoneyRequestPending,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	limitedAccount := payerAccount
+	limitedAccount.ApprovalThreshold = sql.NullInt64{Int64: 10, Valid: true}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: moneyRequest.Payer,
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Eq(moneyRequest.ID)).Times(1).Return(moneyRequest, nil)
+				arg := db.GetAccountByOwnerCurrencyParams{Owner: moneyRequest.Payer, Currency: moneyRequest.Currency}
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(payerAccount, nil)
+				txArg := db.AcceptMoneyRequestTxParams{
+					RequestID:     moneyRequest.ID,
+					FromAccountID: payerAccount.ID,
+					Audit:         testAuditMeta(moneyRequest.Payer),
+				}
+				store.EXPECT().AcceptMoneyRequestTx(gomock.Any(), gomock.Eq(txArg)).Times(1).
+					Return(db.AcceptMoneyRequestTxResult{
+						Request:  moneyRequest,
+						Transfer: db.TransferTxResult{FromAccount: payerAccount, ToAccount: randomAccount()},
+					}, nil)
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, notification notify.Notification) error {
+						require.Equal(t, moneyRequest.Requester, notification.Username)
+						require.Equal(t, notify.KindMoneyRequestAccepted, notification.Kind)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got struct {
+					Transfer map[string]json.RawMessage `json:"transfer"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Contains(t, got.Transfer, "from_account")
+				require.NotContains(t, got.Transfer, "to_account")
+			},
+		},
+		{
+			name:     "Requester",
+			username: moneyRequest.Requester,
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Eq(moneyRequest.ID)).Times(1).Return(moneyRequest, nil)
+				store.EXPECT().AcceptMoneyRequestTx(gomock.Any(), gomock.Any()).Times(0)
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotPending",
+			username: moneyRequest.Payer,
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Any()).Times(1).Return(moneyRequest, nil)
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Any()).Times(1).Return(payerAccount, nil)
+				store.EXPECT().
+					AcceptMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AcceptMoneyRequestTxResult{}, db.ErrMoneyRequestNotPending)
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InsufficientFunds",
+			username: moneyRequest.Payer,
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Any()).Times(1).Return(moneyRequest, nil)
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Any()).Times(1).Return(payerAccount, nil)
+				store.EXPECT().
+					AcceptMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AcceptMoneyRequestTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "AboveApprovalThreshold",
+			username: moneyRequest.Payer,
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Any()).Times(1).Return(moneyRequest, nil)
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Any()).Times(1).Return(limitedAccount, nil)
+				store.EXPECT().AcceptMoneyRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: moneyRequest.Payer,
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().
+					GetMoneyRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MoneyRequests{}, sql.ErrNoRows)
+				store.EXPECT().AcceptMoneyRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			notifier := mocknotify.NewMockNotifier(ctrl)
+			tc.buildStubs(store, notifier)
+
+			server := newTestServer(t, store)
+			server.notifier = notifier
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/money_requests/%d/accept", moneyRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeclineMoneyRequestAPI(t *testing.T) {
+	moneyRequest := db.MoneyRequests{
+		ID:        7,
+		Requester: util.RandomOwner(),
+		Payer:     util.RandomOwner(),
+		Amount:    40,
+		Currency:  util.RandomCurrency(),
+		Status:    db.MoneyRequestPending,
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Eq(moneyRequest.ID)).Times(1).Return(moneyRequest, nil)
+				declined := moneyRequest
+				declined.Status = db.MoneyRequestDeclined
+				arg := db.DecideMoneyRequestParams{ID: moneyRequest.ID, Status: db.MoneyRequestDeclined}
+				store.EXPECT().DecideMoneyRequest(gomock.Any(), gomock.Eq(arg)).Times(1).Return(declined, nil)
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, notification notify.Notification) error {
+						require.Equal(t, moneyRequest.Requester, notification.Username)
+						require.Equal(t, notify.KindMoneyRequestDeclined, notification.Kind)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotPending",
+			buildStubs: func(store *mockdb.MockStore, notifier *mocknotify.MockNotifier) {
+				store.EXPECT().GetMoneyRequest(gomock.Any(), gomock.Any()).Times(1).Return(moneyRequest, nil)
+				store.EXPECT().
+					DecideMoneyRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MoneyRequests{}, sql.ErrNoRows)
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			notifier := mocknotify.NewMockNotifier(ctrl)
+			tc.buildStubs(store, notifier)
+
+			server := newTestServer(t, store)
+			server.notifier = notifier
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/money_requests/%d/decline", moneyRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, moneyRequest.Payer, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+// The payee's account is given like the to account of a transfer, by its ID or an alias of its owner.
+type CreatePayeeRequest struct {
+	Recipient
+	Nickname string `json:"nickname" binding:"required,max=64,memo"`
+	Currency string `json:"currency" binding:"required,oneof=USD EUR CAD"`
+}
+
+// This is one API handler function that handles saving a payee of the authenticated user.
+// The payee's account is resolved once, when it is saved.
+// Large amounts can only be paid to a new payee after its cooling-off period, see PayeeCoolingOffPeriod.
+// It is called when a POST request is made to the /payees endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/payees", server.createPayee)
+func (server *Server) createPayee(ctx *gin.Context) {
+	var req CreatePayeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.resolveRecipient(ctx, req.Recipient, req.Currency)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	payee, err := server.store.CreatePayeeTx(ctx, db.CreatePayeeTxParams{
+		CreatePayeeParams: db.CreatePayeeParams{
+			Owner:               authPayload.Username,
+			Nickname:            req.Nickname,
+			ToAccountID:         account.ID,
+			Currency:            req.Currency,
+			CoolingOffUntil:     time.Now().Add(server.config.PayeeCoolingOffPeriod),
+			CoolingOffMaxAmount: server.config.PayeeCoolingOffMaxAmount,
+		},
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation", "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payee)
+}
+
+type ListPayeesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the payees of the authenticated user, by nickname.
+// It is called when a GET request is made to the /payees endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/payees", server.listPayees)
+func (server *Server) listPayees(ctx *gin.Context) {
+	var req ListPayeesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	payees, err := server.store.ListPayees(ctx, db.ListPayeesParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payees)
+}
+
+type PayeeURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the retrieval of a payee.
+// It is called when a GET request is made to the /payees/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/payees/:id", server.getPayee)
+func (server *Server) getPayee(ctx *gin.Context) {
+	payee, ok := server.ownedPayee(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payee)
+}
+
+type UpdatePayeeRequest struct {
+	Nickname string `json:"nickname" binding:"required,max=64,memo"`
+}
+
+// This is one API handler function that handles renaming a payee.
+// Its account can't be changed, a payee for another account must be added instead.
+// It is called when a PATCH request is made to the /payees/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.PATCH("/payees/:id", server.updatePayee)
+func (server *Server) updatePayee(ctx *gin.Context) {
+	payee, ok := server.ownedPayee(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdatePayeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payee, err := server.store.UpdatePayeeNicknameTx(ctx, db.UpdatePayeeNicknameTxParams{
+		UpdatePayeeNicknameParams: db.UpdatePayeeNicknameParams{
+			ID:       payee.ID,
+			Nickname: req.Nickname,
+		},
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payee)
+}
+
+// This is one API handler function that handles the deletion of a payee.
+// It is called when a DELETE request is made to the /payees/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.DELETE("/payees/:id", server.deletePayee)
+func (server *Server) deletePayee(ctx *gin.Context) {
+	payee, ok := server.ownedPayee(ctx)
+	if !ok {
+		return
+	}
+
+	err := server.store.DeletePayeeTx(ctx, db.DeletePayeeTxParams{
+		ID:    payee.ID,
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// ownedPayee binds the payee ID from the uri and returns the payee if it belongs to the authenticated user.
+// If anything fails it writes the error response and returns false.
+func (server *Server) ownedPayee(ctx *gin.Context) (db.Payees, bool) {
+	var uri PayeeURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Payees{}, false
+	}
+
+	return server.userPayee(ctx, uri.ID)
+}
+
+// userPayee returns the payee if it belongs to the authenticated user.
+// If it doesn't, or it doesn't exist, it writes the error response and returns false.
+func (server *Server) userPayee(ctx *gin.Context, payeeID int64) (db.Payees, bool) {
+	payee, err := server.store.GetPayee(ctx, payeeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return payee, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return payee, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payee.Owner != authPayload.Username {
+		err := errors.New("payee doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return payee, false
+	}
+
+	return payee, true
+}
+
+// resolvePayee returns the to account of a transfer in currency addressed to a payee.
+// The payee's cooling-off period is applied by the store to every transfer to its account, however it is addressed.
+// If the payee can't be paid it writes the error response and returns false.
+func (server *Server) resolvePayee(ctx *gin.Context, payeeID int64, currency string) (db.Accounts, bool) {
+	payee, ok := server.userPayee(ctx, payeeID)
+	if !ok {
+		return db.Accounts{}, false
+	}
+
+	if payee.Currency != currency {
+		err := errors.New("payee currency doesn't match the transfer currency")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Accounts{}, false
+	}
+
+	return server.validAccount(ctx, payee.ToAccountID, currency)
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePayeeAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ByUsername",
+			body: gin.H{"nickname": "Landlord", "to_username": account.Owner, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetAccountByOwnerCurrencyParams{Owner: account.Owner, Currency: account.Currency}
+				store.EXPECT().GetAccountByOwnerCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+				store.EXPECT().
+					CreatePayeeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePayeeTxParams) (db.Payees, error) {
+						require.Equal(t, testAuditMeta(owner), arg.Audit)
+						require.Equal(t, owner, arg.Owner)
+						require.Equal(t, "Landlord", arg.Nickname)
+						require.Equal(t, account.ID, arg.ToAccountID)
+						require.Equal(t, account.Currency, arg.Currency)
+						require.WithinDuration(t, time.Now().Add(24*time.Hour), arg.CoolingOffUntil, time.Second)
+						require.Equal(t, int64(100), arg.CoolingOffMaxAmount)
+						return db.Payees{ID: 1}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateNickname",
+			body: gin.H{"nickname": "Landlord", "to_account_id": account.ID, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					CreatePayeeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Payees{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "MissingNickname",
+			body: gin.H{"to_account_id": account.ID, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePayeeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.PayeeCoolingOffPeriod = 24 * time.Hour
+			server.config.PayeeCoolingOffMaxAmount = 100
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/payees", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferToPayeeAPI(t *testing.T) {
+	fromAccount := randomAccount()
+	toAccount := randomAccount()
+	toAccount.ID = fromAccount.ID + 1
+	toAccount.Currency = fromAccount.Currency
+
+	payee := db.Payees{
+		ID:              9,
+		Owner:           fromAccount.Owner,
+		Nickname:        "Landlord",
+		ToAccountID:     toAccount.ID,
+		Currency:        toAccount.Currency,
+		CoolingOffUntil: time.Now().Add(-time.Hour),
+	}
+	newPayee := payee
+	newPayee.CoolingOffUntil = time.Now().Add(time.Hour)
+	othersPayee := payee
+	othersPayee.Owner = "someone_else"
+
+	testCases := []struct {
+		name          string
+		payee         db.Payees
+		amount        int64
+		extra         gin.H
+		buildStubs    func(store *mockdb.MockStore, payee db.Payees)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			payee:  payee,
+			amount: 500,
+			buildStubs: func(store *mockdb.MockStore, payee db.Payees) {
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        500,
+					Audit:         testAuditMeta(fromAccount.Owner),
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "CoolingOffSmallAmount",
+			payee:  newPayee,
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore, payee db.Payees) {
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "CoolingOffLargeAmount",
+			payee:  newPayee,
+			amount: 101,
+			buildStubs: func(store *mockdb.MockStore, payee db.Payees) {
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, &db.PayeeCoolingOffError{CoolingOffUntil: payee.CoolingOffUntil, MaxAmount: 100})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "OthersPayee",
+			payee:  othersPayee,
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore, payee db.Payees) {
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "PayeeAndAccountID",
+			payee:  payee,
+			amount: 10,
+			extra:  gin.H{"to_account_id": toAccount.ID},
+			buildStubs: func(store *mockdb.MockStore, payee db.Payees) {
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			tc.buildStubs(store, tc.payee)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := gin.H{
+				"from_account_id": fromAccount.ID,
+				"payee_id":        tc.payee.ID,
+				"amount":          tc.amount,
+				"currency":        fromAccount.Currency,
+			}
+			for key, value := range tc.extra {
+				body[key] = value
+			}
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, fromAccount.Owner, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferToCoolingOffAccountAPI(t *testing.T) {
+	fromAccount := randomAccount()
+	toAccount := randomAccount()
+	toAccount.ID = fromAccount.ID + 1
+	toAccount.Currency = fromAccount.Currency
+	coolingOffUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TransferTxResult{}, &db.PayeeCoolingOffError{CoolingOffUntil: coolingOffUntil, MaxAmount: 100})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          101,
+		"currency":        fromAccount.Currency,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, fromAccount.Owner, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	var response struct {
+		Code            string    `json:"code"`
+		CoolingOffUntil time.Time `json:"cooling_off_until"`
+		MaxAmount       int64     `json:"max_amount"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, "payee_cooling_off", response.Code)
+	require.True(t, coolingOffUntil.Equal(response.CoolingOffUntil))
+	require.Equal(t, int64(100), response.MaxAmount)
+}
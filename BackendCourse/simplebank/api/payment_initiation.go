@@ -0,0 +1,192 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/iso20022"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// maxPaymentInitiationSize is the largest pain.001 file that may be uploaded, in bytes.
+const maxPaymentInitiationSize = 4 << 20
+
+// maxPaymentInstructions is the most credit transfers a pain.001 file may have, as many as a transfer batch.
+const maxPaymentInstructions = 1000
+
+// Statuses of the instructions of a payment initiation.
+const (
+	paymentStatusAccepted = "accepted"
+	paymentStatusRejected = "rejected"
+)
+
+// PaymentInstruction is a credit transfer of a pain.001 file, validated like a TransferRequest.
+// The end to end ID becomes the transfer's reference and the remittance information its description.
+type PaymentInstruction struct {
+	DebtorAccountID   int64  `binding:"required,min=1"`
+	CreditorAccountID int64  `binding:"required,min=1"`
+	Amount            int64  `binding:"required,gt=0"`
+	Currency          string `binding:"required,oneof=USD EUR CAD"`
+	Description       string `binding:"omitempty,max=140,memo"`
+	Reference         string `binding:"omitempty,max=35,reference"`
+}
+
+type PaymentInstructionResult struct {
+	PaymentInformationID string        `json:"payment_information_id"`
+	InstructionID        string        `json:"instruction_id"`
+	EndToEndID           string        `json:"end_to_end_id"`
+	Status               string        `json:"status"`
+	Error                string        `json:"error,omitempty"`
+	Transfer             *db.Transfers `json:"transfer,omitempty"`
+}
+
+type PaymentInitiationResponse struct {
+	MessageID     string                     `json:"message_id"`
+	AcceptedCount int                        `json:"accepted_count"`
+	RejectedCount int                        `json:"rejected_count"`
+	Instructions  []PaymentInstructionResult `json:"instructions"`
+}
+
+// This is one API handler function that handles importing an ISO 20022 pain.001 customer credit transfer
+// initiation file, given as the request body. Every credit transfer of the file is validated and made
+// on its own, from accounts of the authenticated user. Transfers that are rejected don't fail the request,
+// they are reported in the instructions of the response, in the order of the file.
+// It is called when a POST request is made to the /payment_initiations endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/payment_initiations", server.importPaymentInitiation)
+func (server *Server) importPaymentInitiation(ctx *gin.Context) {
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxPaymentInitiationSize)
+	initiation, err := iso20022.ParsePain001(body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if len(initiation.Transfers) > maxPaymentInstructions {
+		err := fmt.Errorf("the file must not have more than %d credit transfers", maxPaymentInstructions)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	// the approval threshold applies to what the file pays from each account in all
+	debtorTotals := map[int64]int64{}
+	for _, transfer := range initiation.Transfers {
+		if transfer.Err == nil {
+			debtorTotals[transfer.DebtorAccountID] += transfer.Amount
+		}
+	}
+
+	response := PaymentInitiationResponse{
+		MessageID:    initiation.MessageID,
+		Instructions: make([]PaymentInstructionResult, len(initiation.Transfers)),
+	}
+	for i, transfer := range initiation.Transfers {
+		result := PaymentInstructionResult{
+			PaymentInformationID: transfer.PaymentInformationID,
+			InstructionID:        transfer.InstructionID,
+			EndToEndID:           transfer.EndToEndID,
+			Status:               paymentStatusAccepted,
+		}
+
+		transferResult, err := server.payInstruction(ctx, authPayload.Username, transfer, debtorTotals[transfer.DebtorAccountID])
+		if err != nil {
+			result.Status = paymentStatusRejected
+			result.Error = err.Error()
+			response.RejectedCount++
+		} else {
+			result.Transfer = &transferResult.Transfer
+			response.AcceptedCount++
+		}
+		response.Instructions[i] = result
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// payInstruction makes the credit transfer if it is valid and from an account of the user,
+// otherwise it returns why the transfer can't be made. The debtor total is what the whole file
+// pays from the transfer's debtor account.
+func (server *Server) payInstruction(ctx *gin.Context, username string, transfer iso20022.CreditTransfer, debtorTotal int64) (db.TransferTxResult, error) {
+	var result db.TransferTxResult
+	if transfer.Err != nil {
+		return result, transfer.Err
+	}
+
+	instruction := PaymentInstruction{
+		DebtorAccountID:   transfer.DebtorAccountID,
+		CreditorAccountID: transfer.CreditorAccountID,
+		Amount:            transfer.Amount,
+		Currency:          transfer.Currency,
+		Description:       transfer.RemittanceInfo,
+		Reference:         transfer.EndToEndID,
+	}
+	if err := binding.Validator.ValidateStruct(instruction); err != nil {
+		return result, err
+	}
+
+	if instruction.CreditorAccountID == instruction.DebtorAccountID {
+		return result, errors.New("can't transfer to the debtor account")
+	}
+
+	fromAccount, err := server.instructionAccount(ctx, instruction.DebtorAccountID, instruction.Currency)
+	if err != nil {
+		return result, fmt.Errorf("debtor account: %w", err)
+	}
+	if fromAccount.Owner != username {
+		return result, errors.New("debtor account doesn't belong to the authenticated user")
+	}
+
+	toAccount, err := server.instructionAccount(ctx, instruction.CreditorAccountID, instruction.Currency)
+	if err != nil {
+		return result, fmt.Errorf("creditor account: %w", err)
+	}
+	// paying the bank's own accounts would skip the limits and fees, as for a single transfer
+	if toAccount.Type == util.SystemAccount {
+		return result, errSystemAccountTransfer
+	}
+
+	// files aren't approved, so they can't be used to get around the approval threshold,
+	// which a large payment split into small credit transfers would otherwise do
+	if db.NeedsApproval(fromAccount, debtorTotal) {
+		return result, errors.New("the file's total from the debtor account is above the approval threshold, its payments must be sent as single transfers")
+	}
+
+	return server.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: instruction.DebtorAccountID,
+		ToAccountID:   instruction.CreditorAccountID,
+		Amount:        instruction.Amount,
+		Description:   instruction.Description,
+		Reference:     instruction.Reference,
+		Audit:         auditMeta(ctx),
+	})
+}
+
+// instructionAccount returns the account if it can send or receive a transfer in the currency,
+// the same checks validAccount makes.
+func (server *Server) instructionAccount(ctx *gin.Context, accountID int64, currency string) (db.Accounts, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, errors.New("account not found")
+		}
+		return account, err
+	}
+
+	if account.Currency != currency {
+		return account, fmt.Errorf("currency mismatch: %s vs %s", account.Currency, currency)
+	}
+
+	if account.Type == util.PocketAccount {
+		return account, errPocketTransfer
+	}
+
+	return account, nil
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// pain001 returns a pain.001 file paying each of the amounts from the debtor account to the creditor account.
+func pain001(debtorAccountID int64, creditorAccountID int64, currency string, amounts ...string) string {
+	var transactions strings.Builder
+	for i, amount := range amounts {
+		fmt.Fprintf(&transactions, `
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>INV-%d</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="%s">%s</InstdAmt></Amt>
+        <CdtrAcct><Id><Othr><Id>%d</Id></Othr></Id></CdtrAcct>
+        <RmtInf><Ustrd>Invoice %d</Ustrd></RmtInf>
+      </CdtTrfTxInf>`, i+1, currency, amount, creditorAccountID, i+1)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">
+  <CstmrCdtTrfInitn>
+    <GrpHdr><MsgId>MSG-1</MsgId><NbOfTxs>%d</NbOfTxs></GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <DbtrAcct><Id><Othr><Id>%d</Id></Othr></Id></DbtrAcct>%s
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`, len(amounts), debtorAccountID, transactions.String())
+}
+
+func TestImportPaymentInitiationAPI(t *testing.T) {
+	fromAccount := randomAccount()
+	fromAccount.Currency = util.USD
+	toAccount := randomAccount()
+	toAccount.ID = fromAccount.ID + 1
+	toAccount.Currency = util.USD
+
+	testCases := []struct {
+		name          string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: pain001(fromAccount.ID, toAccount.ID, util.USD, "100.00", "1.505", "200"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        10000,
+					Description:   "Invoice 1",
+					Reference:     "INV-1",
+					Audit:         testAuditMeta(fromAccount.Owner),
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfers{ID: 7}}, nil)
+
+				arg.Amount, arg.Description, arg.Reference = 20000, "Invoice 3", "INV-3"
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got PaymentInitiationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, "MSG-1", got.MessageID)
+				require.Equal(t, 1, got.AcceptedCount)
+				require.Equal(t, 2, got.RejectedCount)
+				require.Len(t, got.Instructions, 3)
+
+				require.Equal(t, paymentStatusAccepted, got.Instructions[0].Status)
+				require.Equal(t, int64(7), got.Instructions[0].Transfer.ID)
+				require.Equal(t, paymentStatusRejected, got.Instructions[1].Status)
+				require.Contains(t, got.Instructions[1].Error, "decimal numbers")
+				require.Equal(t, paymentStatusRejected, got.Instructions[2].Status)
+				require.Equal(t, db.ErrInsufficientFunds.Error(), got.Instructions[2].Error)
+			},
+		},
+		{
+			name: "NotOwner",
+			body: pain001(toAccount.ID, fromAccount.ID, util.USD, "100"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got PaymentInitiationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, 1, got.RejectedCount)
+				require.Contains(t, got.Instructions[0].Error, "doesn't belong")
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: pain001(fromAccount.ID, toAccount.ID, util.EUR, "100"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got PaymentInitiationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Contains(t, got.Instructions[0].Error, "currency mismatch")
+			},
+		},
+		{
+			name: "SystemCreditor",
+			body: pain001(fromAccount.ID, toAccount.ID, util.USD, "100"),
+			buildStubs: func(store *mockdb.MockStore) {
+				systemAccount := toAccount
+				systemAccount.Type = util.SystemAccount
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(systemAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got PaymentInitiationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, 1, got.RejectedCount)
+				require.Equal(t, errSystemAccountTransfer.Error(), got.Instructions[0].Error)
+			},
+		},
+		{
+			name: "SplitAboveApprovalThreshold",
+			body: pain001(fromAccount.ID, toAccount.ID, util.USD, "100", "100"),
+			buildStubs: func(store *mockdb.MockStore) {
+				withThreshold := fromAccount
+				withThreshold.ApprovalThreshold = sql.NullInt64{Int64: 15000, Valid: true}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(withThreshold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got PaymentInitiationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, 2, got.RejectedCount)
+				require.Contains(t, got.Instructions[0].Error, "approval threshold")
+				require.Contains(t, got.Instructions[1].Error, "approval threshold")
+			},
+		},
+		{
+			name: "InvalidFile",
+			body: `{"legs": []}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/payment_initiations", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/xml")
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, fromAccount.Owner, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// pocketCategory is the category of the transfers moving money in and out of pockets.
+const pocketCategory = "pocket"
+
+var errPocketTransfer = errors.New("pockets only move money to and from their parent account")
+
+type CreatePocketRequest struct {
+	Name         string `json:"name" binding:"required,max=64,memo"`
+	TargetAmount *int64 `json:"target_amount" binding:"omitempty,gt=0"`
+}
+
+// This is one API handler function that handles adding a pocket to a checking or savings account.
+// A pocket holds money set aside inside the account, in the account's currency.
+// It is called when a POST request is made to the /accounts/:id/pockets endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/accounts/:id/pockets", server.createPocket)
+func (server *Server) createPocket(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req CreatePocketRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.userAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if account.Type != util.CheckingAccount && account.Type != util.SavingsAccount {
+		err := errors.New("pockets can only be added to a checking or savings account")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePocketParams{
+		Owner:           account.Owner,
+		Currency:        account.Currency,
+		ParentAccountID: account.ID,
+		Name:            req.Name,
+	}
+	if req.TargetAmount != nil {
+		arg.TargetAmount = sql.NullInt64{Int64: *req.TargetAmount, Valid: true}
+	}
+
+	pocket, err := server.store.CreatePocket(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pocket)
+}
+
+// This is one API handler function that handles listing the pockets of an account.
+// It is called when a GET request is made to the /accounts/:id/pockets endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/accounts/:id/pockets", server.listPockets)
+func (server *Server) listPockets(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.userAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	pockets, err := server.store.ListPockets(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pockets)
+}
+
+type UpdatePocketRequest struct {
+	Name         *string `json:"name" binding:"omitempty,min=1,max=64,memo"`
+	TargetAmount *int64  `json:"target_amount" binding:"omitempty,gt=0"`
+}
+
+// This is one API handler function that handles renaming a pocket or changing its target.
+// It is called when a PATCH request is made to the /pockets/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.PATCH("/pockets/:id", server.updatePocket)
+func (server *Server) updatePocket(ctx *gin.Context) {
+	pocket, ok := server.ownedPocket(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdatePocketRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdatePocketParams{ID: pocket.ID}
+	if req.Name != nil {
+		arg.Name = sql.NullString{String: *req.Name, Valid: true}
+	}
+	if req.TargetAmount != nil {
+		arg.TargetAmount = sql.NullInt64{Int64: *req.TargetAmount, Valid: true}
+	}
+
+	pocket, err := server.store.UpdatePocket(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pocket)
+}
+
+type MovePocketMoneyRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// This is one API handler function that handles moving money from an account into one of its pockets.
+// Moves between an account and its pockets are neither limited nor charged a fee.
+// It is called when a POST request is made to the /pockets/:id/deposit endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/pockets/:id/deposit", server.depositToPocket)
+func (server *Server) depositToPocket(ctx *gin.Context) {
+	server.movePocketMoney(ctx, true)
+}
+
+// This is one API handler function that handles moving money from a pocket back into its account.
+// It is called when a POST request is made to the /pockets/:id/withdraw endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/pockets/:id/withdraw", server.withdrawFromPocket)
+func (server *Server) withdrawFromPocket(ctx *gin.Context) {
+	server.movePocketMoney(ctx, false)
+}
+
+// movePocketMoney transfers the requested amount between the pocket and its parent account,
+// into the pocket when deposit is true.
+func (server *Server) movePocketMoney(ctx *gin.Context, deposit bool) {
+	pocket, ok := server.ownedPocket(ctx)
+	if !ok {
+		return
+	}
+
+	var req MovePocketMoneyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID: pocket.ParentAccountID.Int64,
+		ToAccountID:   pocket.ID,
+		Amount:        req.Amount,
+		Description:   "Moved to " + pocket.Name,
+		Category:      pocketCategory,
+		Audit:         auditMeta(ctx),
+	}
+	if !deposit {
+		arg.FromAccountID, arg.ToAccountID = arg.ToAccountID, arg.FromAccountID
+		arg.Description = "Moved from " + pocket.Name
+	}
+
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		transferErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type PocketURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// ownedPocket returns the pocket in the URI if it belongs to the authenticated user,
+// otherwise it writes the error response and returns false.
+func (server *Server) ownedPocket(ctx *gin.Context) (db.Accounts, bool) {
+	var uri PocketURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Accounts{}, false
+	}
+
+	pocket, ok := server.userAccount(ctx, uri.ID)
+	if !ok {
+		return pocket, false
+	}
+
+	if pocket.Type != util.PocketAccount {
+		ctx.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return pocket, false
+	}
+
+	return pocket, true
+}
+
+// userAccount returns the account if it belongs to the authenticated user,
+// otherwise it writes the error response and returns false.
+func (server *Server) userAccount(ctx *gin.Context, accountID int64) (db.Accounts, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
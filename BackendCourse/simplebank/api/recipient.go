@@ -0,0 +1,135 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// errRecipientNotFound is returned for every alias that can't be resolved, so the response
+// doesn't tell whether a username or email exists.
+var errRecipientNotFound = errors.New("recipient not found")
+
+// errSystemAccountTransfer is returned for transfers to the bank's own accounts, which would skip the limits and fees.
+var errSystemAccountTransfer = errors.New("transfers can't be made to the bank's own accounts")
+
+// Recipient addresses the to account of a transfer by exactly one of its ID, the username of its owner
+// or the verified email of its owner. Aliases resolve to the owner's account in the transfer's currency.
+type Recipient struct {
+	ToAccountID int64  `json:"to_account_id" form:"to_account_id" binding:"omitempty,min=1"`
+	ToUsername  string `json:"to_username" form:"to_username" binding:"omitempty,alphanum"`
+	ToEmail     string `json:"to_email" form:"to_email" binding:"omitempty,email"`
+}
+
+// resolveRecipient returns the to account the recipient addresses in the currency.
+// If it can't be resolved it writes the error response and returns false.
+func (server *Server) resolveRecipient(ctx *gin.Context, recipient Recipient, currency string) (db.Accounts, bool) {
+	set := 0
+	for _, ok := range []bool{recipient.ToAccountID != 0, recipient.ToUsername != "", recipient.ToEmail != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		err := errors.New("exactly one of to_account_id, to_username and to_email is required")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Accounts{}, false
+	}
+
+	if recipient.ToAccountID != 0 {
+		account, ok := server.validAccount(ctx, recipient.ToAccountID, currency)
+		// paying the bank's own accounts would skip the limits and fees
+		if ok && account.Type == util.SystemAccount {
+			ctx.JSON(http.StatusForbidden, errorResponse(errSystemAccountTransfer))
+			return account, false
+		}
+		return account, ok
+	}
+
+	owner := recipient.ToUsername
+	if recipient.ToEmail != "" {
+		user, err := server.store.GetUserByEmail(ctx, recipient.ToEmail)
+		if err != nil && err != sql.ErrNoRows {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return db.Accounts{}, false
+		}
+		if err == sql.ErrNoRows || !user.IsEmailVerified {
+			ctx.JSON(http.StatusNotFound, errorResponse(errRecipientNotFound))
+			return db.Accounts{}, false
+		}
+		owner = user.Username
+	}
+
+	account, err := server.store.GetAccountByOwnerCurrency(ctx, db.GetAccountByOwnerCurrencyParams{
+		Owner:    owner,
+		Currency: currency,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(errRecipientNotFound))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	// the bank's own accounts can't be paid by alias
+	if account.Type == util.SystemAccount {
+		ctx.JSON(http.StatusNotFound, errorResponse(errRecipientNotFound))
+		return account, false
+	}
+
+	return account, true
+}
+
+type PreviewRecipientRequest struct {
+	Recipient
+	Currency string `form:"currency" binding:"required,oneof=USD EUR CAD"`
+}
+
+// RecipientPreview is what a payer sees of the recipient before confirming a transfer.
+type RecipientPreview struct {
+	MaskedName string `json:"masked_name"`
+	Currency   string `json:"currency"`
+}
+
+// This is one API handler function that handles previewing the recipient of a transfer, so the payer
+// can check who they are paying before confirming. Only the masked name of the owner is returned.
+// Recipients are only previewed by alias: account IDs are sequential, so previewing them one after the
+// other would map every account to its owner's name.
+// It is called when a GET request is made to the /recipients endpoint, with the to_username or to_email of a transfer.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/recipients", server.previewRecipient)
+func (server *Server) previewRecipient(ctx *gin.Context) {
+	var req PreviewRecipientRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ToAccountID != 0 {
+		err := errors.New("recipients can only be previewed by to_username or to_email")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.resolveRecipient(ctx, req.Recipient, req.Currency)
+	if !ok {
+		return
+	}
+
+	owner, err := server.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RecipientPreview{
+		MaskedName: util.MaskName(owner.FullName),
+		Currency:   account.Currency,
+	})
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// Report formats. JSON is the default.
+const (
+	reportFormatJSON = "json"
+	reportFormatCSV  = "csv"
+)
+
+type ReportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// The range is of whole UTC days, both included.
+type ReportRangeRequest struct {
+	ReportRequest
+	From string `form:"from" binding:"required,datetime=2006-01-02"`
+	To   string `form:"to" binding:"required,datetime=2006-01-02"`
+}
+
+// bindReportRange returns the start of the from day and the end of the to day,
+// otherwise it writes the error response and returns false.
+func bindReportRange(ctx *gin.Context) (ReportRangeRequest, time.Time, time.Time, bool) {
+	var req ReportRangeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return req, time.Time{}, time.Time{}, false
+	}
+
+	from, to, ok := dayRange(ctx, req.From, req.To)
+	return req, from, to, ok
+}
+
+// dayRange returns the start of the from day and the end of the to day, of dates the binding
+// already validated, otherwise it writes the error response and returns false.
+func dayRange(ctx *gin.Context, fromDate string, toDate string) (time.Time, time.Time, bool) {
+	from, _ := time.Parse(time.DateOnly, fromDate)
+	to, _ := time.Parse(time.DateOnly, toDate)
+	if to.Before(from) {
+		err := errors.New("to must not be before from")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return from, to, false
+	}
+
+	return from, to.AddDate(0, 0, 1), true
+}
+
+// sendReport writes the rows as JSON, or as a CSV file download of the header and records.
+func sendReport(ctx *gin.Context, format string, name string, rows interface{}, header []string, records [][]string) {
+	if format != reportFormatCSV {
+		ctx.JSON(http.StatusOK, rows)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	w.Write(header)
+	w.WriteAll(records) // flushes
+}
+
+// This is one API handler function that handles the trial balance of the general ledger:
+// the balance of every GL account per currency, debits positive and credits negative.
+// It is called when a GET request is made to the /reports/trial_balance endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/reports/trial_balance", server.getTrialBalanceReport)
+func (server *Server) getTrialBalanceReport(ctx *gin.Context) {
+	var req ReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.GetTrialBalance(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{row.Currency, row.Code, row.Name, row.Type, strconv.FormatInt(row.Balance, 10)}
+	}
+	sendReport(ctx, req.Format, "trial_balance", rows,
+		[]string{"currency", "code", "name", "type", "balance"}, records)
+}
+
+// This is one API handler function that handles the total deposits per currency,
+// i.e. the balances of all the customers' accounts and pockets.
+// It is called when a GET request is made to the /reports/deposits endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/reports/deposits", server.getDepositsReport)
+func (server *Server) getDepositsReport(ctx *gin.Context) {
+	var req ReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.GetDepositTotals(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{row.Currency, strconv.FormatInt(row.AccountCount, 10), strconv.FormatInt(row.TotalBalance, 10)}
+	}
+	sendReport(ctx, req.Format, "deposits", rows,
+		[]string{"currency", "account_count", "total_balance"}, records)
+}
+
+// This is one API handler function that handles the number of settled transfers and the amount
+// they moved, per day and currency, between the from and to dates.
+// It is called when a GET request is made to the /reports/transfer_volume endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/reports/transfer_volume", server.getTransferVolumeReport)
+func (server *Server) getTransferVolumeReport(ctx *gin.Context) {
+	req, from, to, ok := bindReportRange(ctx)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.GetDailyTransferVolume(ctx, db.GetDailyTransferVolumeParams{
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{row.Day.Format(time.DateOnly), row.Currency,
+			strconv.FormatInt(row.TransferCount, 10), strconv.FormatInt(row.Volume, 10)}
+	}
+	sendReport(ctx, req.Format, "transfer_volume", rows,
+		[]string{"day", "currency", "transfer_count", "volume"}, records)
+}
+
+type TopAccountsReportRequest struct {
+	ReportRequest
+	Currency string `form:"currency" binding:"required,oneof=USD EUR CAD"`
+	Limit    int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// This is one API handler function that handles the customers' accounts with the highest balances in a currency.
+// It is called when a GET request is made to the /reports/top_accounts endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/reports/top_accounts", server.getTopAccountsReport)
+func (server *Server) getTopAccountsReport(ctx *gin.Context) {
+	var req TopAccountsReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	rows, err := server.store.ListTopAccountsByBalance(ctx, db.ListTopAccountsByBalanceParams{
+		Currency: req.Currency,
+		MaxRows:  req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{strconv.FormatInt(row.ID, 10), row.Owner, row.Type, row.Currency, strconv.FormatInt(row.Balance, 10)}
+	}
+	sendReport(ctx, req.Format, "top_accounts", rows,
+		[]string{"id", "owner", "type", "currency", "balance"}, records)
+}
+
+// This is one API handler function that handles the money that came into and left the customers'
+// accounts between the from and to dates, per currency and category.
+// It is called when a GET request is made to the /reports/net_flows endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.GET("/reports/net_flows", server.getNetFlowsReport)
+func (server *Server) getNetFlowsReport(ctx *gin.Context) {
+	req, from, to, ok := bindReportRange(ctx)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.GetNetFlows(ctx, db.GetNetFlowsParams{
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{row.Currency, row.Category,
+			strconv.FormatInt(row.Inflow, 10), strconv.FormatInt(row.Outflow, 10), strconv.FormatInt(row.NetFlow, 10)}
+	}
+	sendReport(ctx, req.Format, "net_flows", rows,
+		[]string{"currency", "category", "inflow", "outflow", "net_flow"}, records)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportsAPI(t *testing.T) {
+	trialBalance := []db.GetTrialBalanceRow{
+		{Code: "1000", Name: "Cash", Type: "asset", Currency: util.USD, Balance: 500},
+		{Code: "2000", Name: "Customer deposits", Type: "liability", Currency: util.USD, Balance: -500},
+	}
+
+	testCases := []struct {
+		name          string
+		url           string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "TrialBalanceJSON",
+			url:  "/reports/trial_balance",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTrialBalance(gomock.Any()).Times(1).Return(trialBalance, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+			},
+		},
+		{
+			name: "TrialBalanceCSV",
+			url:  "/reports/trial_balance?format=csv",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTrialBalance(gomock.Any()).Times(1).Return(trialBalance, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+				require.Equal(t, "currency,code,name,type,balance\n"+
+					"USD,1000,Cash,asset,500\n"+
+					"USD,2000,Customer deposits,liability,-500\n", recorder.Body.String())
+			},
+		},
+		{
+			name: "NotBanker",
+			url:  "/reports/trial_balance",
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTrialBalance(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "TransferVolume",
+			url:  "/reports/transfer_volume?from=2026-03-01&to=2026-03-31",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetDailyTransferVolumeParams{
+					FromTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+					ToTime:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+				}
+				store.EXPECT().GetDailyTransferVolume(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.GetDailyTransferVolumeRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ToBeforeFrom",
+			url:  "/reports/net_flows?from=2026-03-31&to=2026-03-01",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNetFlows(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TopAccountsDefaultLimit",
+			url:  "/reports/top_accounts?currency=EUR",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListTopAccountsByBalanceParams{Currency: util.EUR, MaxRows: 10}
+				store.EXPECT().ListTopAccountsByBalance(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.ListTopAccountsByBalanceRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidFormat",
+			url:  "/reports/deposits?format=xml",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDepositTotals(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "banker", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
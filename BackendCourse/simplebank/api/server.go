@@ -4,10 +4,14 @@ import (
 	"fmt"
 
 	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 // Server serves HTTP requests for our banking service.
@@ -17,26 +21,47 @@ type Server struct {
 	// The store is an interface that defines methods for interacting with the database.
 	store      db.Store
 	tokenMaker token.Maker
-	router     *gin.Engine
+	// The notifier tells users about things that concern them, e.g. a money request addressed to them.
+	notifier notify.Notifier
+	// The hub wakes up the account event streams when their accounts have new events.
+	hub    *events.Hub
+	router *gin.Engine
 }
 
 // NewServer creates a new HTTP server and sets up routing
-// with the provided store.
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+// with the provided store, notifier and event hub.
+func NewServer(config util.Config, store db.Store, notifier notify.Notifier, hub *events.Hub) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
-	server := &Server{config: config, store: store, tokenMaker: tokenMaker}
-	server.setupROuter()
+	server := &Server{config: config, store: store, tokenMaker: tokenMaker, notifier: notifier, hub: hub}
+
+	// Register the custom validators used in the binding tags of the requests
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("memo", validMemo)
+		v.RegisterValidation("reference", validReference)
+		v.RegisterValidation("category", validCategory)
+		v.RegisterValidation("webhook_url", validWebhookURL)
+	}
+
+	if err := server.setupROuter(); err != nil {
+		return nil, fmt.Errorf("cannot set up router: %w", err)
+	}
 	return server, nil
 }
 
-func (server *Server) setupROuter() {
+func (server *Server) setupROuter() error {
 	// Create a new Gin router instance
 	// The router is responsible for routing incoming HTTP requests to the appropriate handler functions
 	// and managing the server's routes.
 	router := gin.Default()
+	// Only the configured proxies may set the client IP with X-Forwarded-For,
+	// otherwise any client could forge the client IP recorded in the audit log
+	if err := router.SetTrustedProxies(server.config.TrustedProxies); err != nil {
+		return err
+	}
+	router.Use(requestIDMiddleware())
 
 	// Set up routes
 	// When a request is made, the indicated handler method of the server is called
@@ -47,9 +72,101 @@ func (server *Server) setupROuter() {
 	router.GET("/accounts/:id", server.getAccount) // the ':' indicates a uri (path) parameter
 	router.GET("/accounts", server.listAccounts)
 
-	router.POST("/transfers", server.createTransfer)
+	// Routes below require a valid access token
+	authRoutes := router.Group("/", authMiddleware(server.tokenMaker))
+	authRoutes.GET("/accounts/:id/transfers", server.listAccountTransfers)
+	authRoutes.GET("/accounts/:id/entries", server.listAccountEntries)
+	authRoutes.GET("/accounts/:id/balance", server.getAccountBalance)
+	authRoutes.GET("/accounts/:id/balance_history", server.listBalanceHistory)
+	authRoutes.GET("/accounts/:id/statements", server.getAccountStatement)
+	authRoutes.GET("/account_events", server.streamAccountEvents)
+	authRoutes.POST("/accounts/:id/pockets", server.createPocket)
+	authRoutes.GET("/accounts/:id/pockets", server.listPockets)
+	authRoutes.PATCH("/pockets/:id", server.updatePocket)
+	authRoutes.POST("/pockets/:id/deposit", server.depositToPocket)
+	authRoutes.POST("/pockets/:id/withdraw", server.withdrawFromPocket)
+
+	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.POST("/transfers/:id/capture", server.captureTransfer)
+	authRoutes.POST("/transfers/:id/void", server.voidTransfer)
+	authRoutes.GET("/recipients", server.previewRecipient)
+
+	authRoutes.POST("/money_requests", server.createMoneyRequest)
+	authRoutes.GET("/money_requests", server.listMoneyRequests)
+	authRoutes.GET("/money_requests/:id", server.getMoneyRequest)
+	authRoutes.POST("/money_requests/:id/accept", server.acceptMoneyRequest)
+	authRoutes.POST("/money_requests/:id/decline", server.declineMoneyRequest)
+
+	authRoutes.POST("/payees", server.createPayee)
+	authRoutes.GET("/payees", server.listPayees)
+	authRoutes.GET("/payees/:id", server.getPayee)
+	authRoutes.PATCH("/payees/:id", server.updatePayee)
+	authRoutes.DELETE("/payees/:id", server.deletePayee)
+	authRoutes.GET("/transfer_requests", server.listApprovableTransferRequests)
+	authRoutes.GET("/transfer_requests/:id", server.getTransferRequest)
+	authRoutes.POST("/transfer_requests/:id/approve", server.approveTransferRequest)
+	authRoutes.POST("/transfer_requests/:id/reject", server.rejectTransferRequest)
+
+	authRoutes.POST("/transfer_batches", server.createTransferBatch)
+	authRoutes.GET("/transfer_batches/:id", server.getTransferBatch)
+
+	authRoutes.POST("/payment_initiations", server.importPaymentInitiation)
+
+	authRoutes.POST("/standing_orders", server.createStandingOrder)
+	authRoutes.GET("/standing_orders", server.listStandingOrders)
+	authRoutes.GET("/standing_orders/:id", server.getStandingOrder)
+	authRoutes.PATCH("/standing_orders/:id", server.updateStandingOrder)
+	authRoutes.DELETE("/standing_orders/:id", server.cancelStandingOrder)
+	authRoutes.GET("/standing_orders/:id/executions", server.listStandingOrderExecutions)
+
+	authRoutes.GET("/loans", server.listLoans)
+	authRoutes.GET("/loans/:id", server.getLoan)
+
+	authRoutes.POST("/webhook_endpoints", server.createWebhookEndpoint)
+	authRoutes.GET("/webhook_endpoints", server.listWebhookEndpoints)
+	authRoutes.DELETE("/webhook_endpoints/:id", server.deleteWebhookEndpoint)
+	authRoutes.GET("/webhook_endpoints/:id/deliveries", server.listWebhookDeliveries)
+	authRoutes.GET("/webhook_deliveries/:id", server.getWebhookDelivery)
+	authRoutes.POST("/webhook_deliveries/:id/replay", server.replayWebhookDelivery)
+
+	// Routes below are restricted to bank staff
+	bankerRoutes := authRoutes.Group("/", roleMiddleware(util.BankerRole))
+	bankerRoutes.PATCH("/accounts/:id/overdraft_limit", server.updateAccountOverdraftLimit)
+	bankerRoutes.PATCH("/accounts/:id/approval_threshold", server.updateAccountApprovalThreshold)
+	bankerRoutes.GET("/accounts/:id/approvers", server.listAccountApprovers)
+	bankerRoutes.POST("/accounts/:id/approvers", server.addAccountApprover)
+	bankerRoutes.DELETE("/accounts/:id/approvers/:username", server.removeAccountApprover)
+	bankerRoutes.POST("/transfers/:id/reverse", server.reverseTransfer)
+	bankerRoutes.POST("/accounts/:id/deposits", server.createDeposit)
+
+	bankerRoutes.GET("/gl_accounts", server.listGLAccounts)
+
+	bankerRoutes.GET("/reports/trial_balance", server.getTrialBalanceReport)
+	bankerRoutes.GET("/reports/deposits", server.getDepositsReport)
+	bankerRoutes.GET("/reports/transfer_volume", server.getTransferVolumeReport)
+	bankerRoutes.GET("/reports/top_accounts", server.getTopAccountsReport)
+	bankerRoutes.GET("/reports/net_flows", server.getNetFlowsReport)
+
+	bankerRoutes.POST("/fee_rules", server.createFeeRule)
+	bankerRoutes.GET("/fee_rules", server.listFeeRules)
+	bankerRoutes.DELETE("/fee_rules/:id", server.deleteFeeRule)
+
+	bankerRoutes.POST("/interest_rates", server.createInterestRate)
+	bankerRoutes.GET("/interest_rates", server.listInterestRates)
+
+	bankerRoutes.POST("/loans", server.createLoan)
+
+	bankerRoutes.POST("/transfer_limits", server.createTransferLimit)
+	bankerRoutes.GET("/transfer_limits", server.listTransferLimits)
+	bankerRoutes.DELETE("/transfer_limits/:id", server.deleteTransferLimit)
+	bankerRoutes.PATCH("/users/:username/tier", server.updateUserTier)
+	bankerRoutes.POST("/users/:username/verify_email", server.verifyUserEmail)
+
+	bankerRoutes.GET("/audit_events", server.listAuditEvents)
+	bankerRoutes.GET("/audit_events/verify", server.verifyAuditChain)
 
 	server.router = router
+	return nil
 }
 
 func (server *Server) Start(address string) error {
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+// An empty interval count runs the order every period of its frequency.
+// An empty end date runs the order until it is cancelled.
+type CreateStandingOrderRequest struct {
+	FromAccountID int64      `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64      `json:"to_account_id" binding:"required,min=1"`
+	Amount        int64      `json:"amount" binding:"required,gt=0"`
+	Currency      string     `json:"currency" binding:"required,oneof=USD EUR CAD"`
+	Frequency     string     `json:"frequency" binding:"required,oneof=daily weekly monthly"`
+	IntervalCount int32      `json:"interval_count" binding:"omitempty,min=1,max=366"`
+	StartAt       time.Time  `json:"start_at" binding:"required"`
+	EndAt         *time.Time `json:"end_at"`
+}
+
+// This is one API handler function that handles the creation of a new standing order.
+// It is called when a POST request is made to the /standing_orders endpoint.
+// Only the owner of the from account may create a standing order paying from it.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/standing_orders", server.createStandingOrder)
+func (server *Server) createStandingOrder(ctx *gin.Context) {
+	var req CreateStandingOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.EndAt != nil && req.EndAt.Before(req.StartAt) {
+		err := errors.New("end_at must not be before start_at")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ToAccountID == req.FromAccountID {
+		err := errors.New("can't transfer to the from account")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	// the to account is checked like the to account of a single transfer, which also rejects the bank's own accounts
+	if _, valid := server.resolveRecipient(ctx, Recipient{ToAccountID: req.ToAccountID}, req.Currency); !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if db.NeedsApproval(fromAccount, req.Amount) {
+		err := errors.New("standing orders above the approval threshold aren't allowed")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateStandingOrderParams{
+		Owner:         authPayload.Username,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		Frequency:     req.Frequency,
+		IntervalCount: req.IntervalCount,
+		StartAt:       req.StartAt,
+	}
+	if arg.IntervalCount == 0 {
+		arg.IntervalCount = 1
+	}
+	if req.EndAt != nil {
+		arg.EndAt = sql.NullTime{Time: *req.EndAt, Valid: true}
+	}
+
+	order, err := server.store.CreateStandingOrderTx(ctx, db.CreateStandingOrderTxParams{
+		CreateStandingOrderParams: arg,
+		Audit:                     auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+type StandingOrderURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the retrieval of a standing order.
+// It is called when a GET request is made to the /standing_orders/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/standing_orders/:id", server.getStandingOrder)
+func (server *Server) getStandingOrder(ctx *gin.Context) {
+	order, ok := server.ownedStandingOrder(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+type ListStandingOrdersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the standing orders of the authenticated user.
+// It is called when a GET request is made to the /standing_orders endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/standing_orders", server.listStandingOrders)
+func (server *Server) listStandingOrders(ctx *gin.Context) {
+	var req ListStandingOrdersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	orders, err := server.store.ListStandingOrders(ctx, db.ListStandingOrdersParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orders)
+}
+
+// Fields left out are not changed
+type UpdateStandingOrderRequest struct {
+	Amount *int64     `json:"amount" binding:"omitempty,gt=0"`
+	EndAt  *time.Time `json:"end_at"`
+}
+
+// This is one API handler function that handles changing the amount or the end of an active standing order.
+// Like a new order, the amount can't be raised above the from account's approval threshold.
+// It is called when a PATCH request is made to the /standing_orders/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.PATCH("/standing_orders/:id", server.updateStandingOrder)
+func (server *Server) updateStandingOrder(ctx *gin.Context) {
+	order, ok := server.ownedStandingOrder(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateStandingOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.EndAt != nil && req.EndAt.Before(order.StartAt) {
+		err := errors.New("end_at must not be before start_at")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateStandingOrderParams{ID: order.ID}
+	if req.Amount != nil {
+		fromAccount, err := server.store.GetAccount(ctx, order.FromAccountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if db.NeedsApproval(fromAccount, *req.Amount) {
+			err := errors.New("standing orders above the approval threshold aren't allowed")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		arg.Amount = sql.NullInt64{Int64: *req.Amount, Valid: true}
+	}
+	if req.EndAt != nil {
+		arg.EndAt = sql.NullTime{Time: *req.EndAt, Valid: true}
+	}
+
+	order, err := server.store.UpdateStandingOrderTx(ctx, db.UpdateStandingOrderTxParams{
+		UpdateStandingOrderParams: arg,
+		Audit:                     auditMeta(ctx),
+	})
+	if err != nil {
+		server.inactiveStandingOrderResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+// This is one API handler function that handles cancelling a standing order.
+// It is called when a DELETE request is made to the /standing_orders/:id endpoint.
+// The order is kept, with its executions, but it won't run any more.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.DELETE("/standing_orders/:id", server.cancelStandingOrder)
+func (server *Server) cancelStandingOrder(ctx *gin.Context) {
+	order, ok := server.ownedStandingOrder(ctx)
+	if !ok {
+		return
+	}
+
+	order, err := server.store.CancelStandingOrderTx(ctx, db.CancelStandingOrderTxParams{
+		ID:    order.ID,
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		server.inactiveStandingOrderResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+type ListStandingOrderExecutionsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the executions of a standing order, latest first.
+// It is called when a GET request is made to the /standing_orders/:id/executions endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/standing_orders/:id/executions", server.listStandingOrderExecutions)
+func (server *Server) listStandingOrderExecutions(ctx *gin.Context) {
+	order, ok := server.ownedStandingOrder(ctx)
+	if !ok {
+		return
+	}
+
+	var req ListStandingOrderExecutionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	executions, err := server.store.ListStandingOrderExecutions(ctx, db.ListStandingOrderExecutionsParams{
+		StandingOrderID: order.ID,
+		Limit:           req.PageSize,
+		Offset:          (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, executions)
+}
+
+// ownedStandingOrder returns the standing order in the uri if it belongs to the authenticated user,
+// otherwise it writes the error response and returns false.
+func (server *Server) ownedStandingOrder(ctx *gin.Context) (db.StandingOrders, bool) {
+	var uri StandingOrderURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.StandingOrders{}, false
+	}
+
+	order, err := server.store.GetStandingOrder(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return order, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return order, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if order.Owner != authPayload.Username {
+		err := errors.New("standing order doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return order, false
+	}
+
+	return order, true
+}
+
+// inactiveStandingOrderResponse maps the errors of changing a standing order to a response.
+// The updates only match active orders, so no rows means the order was cancelled or completed.
+func (server *Server) inactiveStandingOrderResponse(ctx *gin.Context, err error) {
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("standing order is not active")))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+}
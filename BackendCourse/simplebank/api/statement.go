@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/iso20022"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/statement"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// Statement formats besides the report ones.
+const (
+	statementFormatPDF     = "pdf"
+	statementFormatOFX     = "ofx"
+	statementFormatCamt053 = "camt053"
+)
+
+// The range is of whole UTC days, both included.
+type StatementRequest struct {
+	From   string `form:"from" binding:"required,datetime=2006-01-02"`
+	To     string `form:"to" binding:"required,datetime=2006-01-02"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv pdf ofx camt053"`
+}
+
+// This is one API handler function that handles the statement of an account between the from and to dates:
+// the opening balance, every entry with the balance after it, and the closing balance.
+// The statement is JSON, or a CSV, PDF, OFX or ISO 20022 camt.053 file download.
+// Bankers may get the statement of any account.
+// It is called when a GET request is made to the /accounts/:id/statements endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/accounts/:id/statements", server.getAccountStatement)
+func (server *Server) getAccountStatement(ctx *gin.Context) {
+	var uri GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req StatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	from, to, ok := dayRange(ctx, req.From, req.To)
+	if !ok {
+		return
+	}
+
+	if to.Sub(from) > maxBalanceHistoryDays*24*time.Hour {
+		err := errors.New("the range must not be longer than a year")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username && authPayload.Role != util.BankerRole {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	openingBalance, err := server.store.BalanceAt(ctx, account.ID, from)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	entries, err := server.store.ListEntriesBetween(ctx, db.ListEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result := statement.New(account, from, to, openingBalance, entries)
+
+	var contentType, extension string
+	var write func(io.Writer) error
+	switch req.Format {
+	case reportFormatCSV:
+		contentType, extension, write = "text/csv", "csv", result.WriteCSV
+	case statementFormatPDF:
+		contentType, extension, write = "application/pdf", "pdf", result.WritePDF
+	case statementFormatOFX:
+		contentType, extension, write = "application/x-ofx", "ofx", result.WriteOFX
+	case statementFormatCamt053:
+		contentType, extension = "application/xml", "xml"
+		write = func(w io.Writer) error { return iso20022.WriteCamt053(w, result) }
+	default:
+		ctx.JSON(http.StatusOK, result)
+		return
+	}
+
+	// rendered in full first, so a failure can still be answered with an error
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filename := fmt.Sprintf("statement_%d_%s_%s.%s", account.ID, req.From, req.To, extension)
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/statement"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountStatementAPI(t *testing.T) {
+	account := randomAccount()
+	account.Type = util.CheckingAccount
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	entries := []db.Entries{
+		{ID: 1, AccountID: account.ID, Amount: 50, CreatedAt: from.Add(time.Hour), Description: "Salary"},
+		{ID: 2, AccountID: account.ID, Amount: -30, CreatedAt: from.AddDate(0, 0, 2), Description: "Rent"},
+	}
+
+	buildStatementStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+		store.EXPECT().BalanceAt(gomock.Any(), gomock.Eq(account.ID), gomock.Eq(from)).Times(1).Return(int64(100), nil)
+		store.EXPECT().
+			ListEntriesBetween(gomock.Any(), gomock.Eq(db.ListEntriesBetweenParams{
+				AccountID: account.ID,
+				FromTime:  from,
+				ToTime:    to,
+			})).
+			Times(1).
+			Return(entries, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "JSON",
+			query:      "from=2026-03-01&to=2026-03-31",
+			username:   account.Owner,
+			role:       util.DepositorRole,
+			buildStubs: buildStatementStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got statement.Statement
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, int64(100), got.OpeningBalance)
+				require.Equal(t, int64(120), got.ClosingBalance)
+				require.Len(t, got.Lines, 2)
+				require.Equal(t, int64(150), got.Lines[0].Balance)
+			},
+		},
+		{
+			name:       "CSV",
+			query:      "from=2026-03-01&to=2026-03-31&format=csv",
+			username:   account.Owner,
+			role:       util.DepositorRole,
+			buildStubs: buildStatementStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "statement_")
+				require.Len(t, strings.Split(strings.TrimSpace(recorder.Body.String()), "\n"), 5)
+			},
+		},
+		{
+			name:       "PDF",
+			query:      "from=2026-03-01&to=2026-03-31&format=pdf",
+			username:   account.Owner,
+			role:       util.DepositorRole,
+			buildStubs: buildStatementStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+				require.True(t, strings.HasPrefix(recorder.Body.String(), "%PDF-"))
+			},
+		},
+		{
+			name:       "OFX",
+			query:      "from=2026-03-01&to=2026-03-31&format=ofx",
+			username:   account.Owner,
+			role:       util.DepositorRole,
+			buildStubs: buildStatementStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/x-ofx", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), "<FITID>2</FITID>")
+			},
+		},
+		{
+			name:       "Camt053",
+			query:      "from=2026-03-01&to=2026-03-31&format=camt053",
+			username:   account.Owner,
+			role:       util.DepositorRole,
+			buildStubs: buildStatementStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/xml", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".xml")
+				require.Contains(t, recorder.Body.String(), "<Cd>CLBD</Cd>")
+			},
+		},
+		{
+			name:       "Banker",
+			query:      "from=2026-03-01&to=2026-03-31",
+			username:   "banker",
+			role:       util.BankerRole,
+			buildStubs: buildStatementStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			query:    "from=2026-03-01&to=2026-03-31",
+			username: "someone_else",
+			role:     util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntriesBetween(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidFormat",
+			query:    "from=2026-03-01&to=2026-03-31&format=xls",
+			username: account.Owner,
+			role:     util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "RangeTooLong",
+			query:    "from=2025-01-01&to=2026-03-31",
+			username: account.Owner,
+			role:     util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			u := fmt.Sprintf("/accounts/%d/statements?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, u, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+const (
+	// streamPageSize is how many events of an account are read from the outbox at a time
+	streamPageSize = 100
+	// streamHeartbeatInterval is how often an idle stream sends a comment, so proxies don't close it
+	streamHeartbeatInterval = 15 * time.Second
+	// lastEventIDHeader is sent by browsers' EventSource when they reconnect to a stream
+	lastEventIDHeader = "Last-Event-ID"
+	// accountsStreamEvent is the first event of a new stream, the caller's accounts as they are when it starts
+	accountsStreamEvent = "accounts"
+)
+
+var errInvalidStreamCursor = errors.New("invalid last event ID")
+
+// The last event ID is the ID of the last event received on an earlier stream, to resume it from there.
+// It can also be given in the Last-Event-ID header, which wins.
+type StreamAccountEventsRequest struct {
+	LastEventID string `form:"last_event_id"`
+}
+
+// streamCursor is the ID of the last event streamed for every account. The outbox IDs of an account's
+// events are in the order they were committed, since every event is written while the account is locked,
+// but the IDs of different accounts' events aren't, so a stream resumes from a cursor per account.
+type streamCursor map[int64]int64
+
+// String returns the cursor as an event ID, e.g. "12:340,13:338".
+func (cursor streamCursor) String() string {
+	accountIDs := make([]int64, 0, len(cursor))
+	for accountID := range cursor {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
+
+	parts := make([]string, len(accountIDs))
+	for i, accountID := range accountIDs {
+		parts[i] = strconv.FormatInt(accountID, 10) + ":" + strconv.FormatInt(cursor[accountID], 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseStreamCursor(s string) (streamCursor, error) {
+	cursor := streamCursor{}
+	for _, part := range strings.Split(s, ",") {
+		account, event, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, errInvalidStreamCursor
+		}
+		accountID, err := strconv.ParseInt(account, 10, 64)
+		if err != nil {
+			return nil, errInvalidStreamCursor
+		}
+		eventID, err := strconv.ParseInt(event, 10, 64)
+		if err != nil || eventID < 0 {
+			return nil, errInvalidStreamCursor
+		}
+		cursor[accountID] = eventID
+	}
+	return cursor, nil
+}
+
+// This is one API handler function that handles streaming the events of the authenticated user's accounts
+// as Server-Sent Events: every transfer to and from them, with the entry it made and the balance after it,
+// and every other entry on them, such as deposits, interest and fees, as soon as it is committed. A new stream starts with the accounts as they are; a resumed one sends
+// the events after the last event ID first. Accounts opened after the stream started are in the next one.
+// It is called when a GET request is made to the /account_events endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/account_events", server.streamAccountEvents)
+func (server *Server) streamAccountEvents(ctx *gin.Context) {
+	var req StreamAccountEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if lastEventID := ctx.GetHeader(lastEventIDHeader); lastEventID != "" {
+		req.LastEventID = lastEventID
+	}
+
+	var resumed streamCursor
+	if req.LastEventID != "" {
+		var err error
+		resumed, err = parseStreamCursor(req.LastEventID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	accounts, err := server.store.ListOwnerAccounts(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// subscribe before reading the cursor, so no event is committed in between unnoticed
+	keys := make([]string, len(accounts))
+	for i, account := range accounts {
+		keys[i] = events.AggregateKey(db.OutboxAggregateAccount, strconv.FormatInt(account.ID, 10))
+	}
+	subscription := server.hub.Subscribe(keys...)
+	defer subscription.Close()
+
+	cursor := streamCursor{}
+	for _, account := range accounts {
+		if eventID, ok := resumed[account.ID]; ok {
+			cursor[account.ID] = eventID
+			continue
+		}
+
+		cursor[account.ID], err = server.store.GetLastAggregateOutboxEventID(ctx, db.GetLastAggregateOutboxEventIDParams{
+			AggregateType: db.OutboxAggregateAccount,
+			AggregateID:   strconv.FormatInt(account.ID, 10),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	// a resumed stream starts with the events committed since the earlier one
+	if resumed == nil {
+		data, err := json.Marshal(accounts)
+		if err != nil || writeStreamEvent(ctx, cursor.String(), accountsStreamEvent, data) != nil {
+			return
+		}
+	} else if err := server.sendAccountEvents(ctx, cursor); err != nil {
+		return
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(ctx.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-subscription.C:
+			if err := server.sendAccountEvents(ctx, cursor); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// sendAccountEvents streams the events of every account in the cursor after the ones it already streamed,
+// and moves the cursor past them.
+func (server *Server) sendAccountEvents(ctx *gin.Context, cursor streamCursor) error {
+	for accountID := range cursor {
+		for {
+			outboxEvents, err := server.store.ListAggregateOutboxEvents(ctx, db.ListAggregateOutboxEventsParams{
+				AggregateType: db.OutboxAggregateAccount,
+				AggregateID:   strconv.FormatInt(accountID, 10),
+				AfterID:       cursor[accountID],
+				MaxRows:       streamPageSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, outboxEvent := range outboxEvents {
+				data, err := json.Marshal(events.Event{
+					ID:            outboxEvent.ID,
+					Type:          outboxEvent.EventType,
+					AggregateType: outboxEvent.AggregateType,
+					AggregateID:   outboxEvent.AggregateID,
+					Payload:       outboxEvent.Payload,
+					CreatedAt:     outboxEvent.CreatedAt,
+				})
+				if err != nil {
+					return err
+				}
+
+				cursor[accountID] = outboxEvent.ID
+				if err := writeStreamEvent(ctx, cursor.String(), outboxEvent.EventType, data); err != nil {
+					return err
+				}
+			}
+
+			if len(outboxEvents) < streamPageSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// writeStreamEvent writes a Server-Sent Event. The data must be on a single line, as JSON from json.Marshal is.
+func writeStreamEvent(ctx *gin.Context, id string, event string, data []byte) error {
+	_, err := fmt.Fprintf(ctx.Writer, "id: %s\nevent: %s\ndata: %s\n\n", id, event, data)
+	return err
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+type streamEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// readStreamEvent reads the next event of a Server-Sent Events stream, skipping comments.
+func readStreamEvent(t *testing.T, reader *bufio.Reader) streamEvent {
+	var event streamEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "":
+			if event.Event != "" {
+				return event
+			}
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func accountOutboxEvent(id int64, eventType string, account db.Accounts) db.OutboxEvents {
+	payload, _ := json.Marshal(db.TransferEvent{Account: account})
+	return db.OutboxEvents{
+		ID:            id,
+		EventType:     eventType,
+		AggregateType: db.OutboxAggregateAccount,
+		AggregateID:   strconv.FormatInt(account.ID, 10),
+		Payload:       payload,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestStreamAccountEventsAPI(t *testing.T) {
+	account := randomAccount()
+	key := events.AggregateKey(db.OutboxAggregateAccount, strconv.FormatInt(account.ID, 10))
+	listArg := func(afterID int64) db.ListAggregateOutboxEventsParams {
+		return db.ListAggregateOutboxEventsParams{
+			AggregateType: db.OutboxAggregateAccount,
+			AggregateID:   strconv.FormatInt(account.ID, 10),
+			AfterID:       afterID,
+			MaxRows:       streamPageSize,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		lastEventID string
+		buildStubs  func(store *mockdb.MockStore)
+		check       func(t *testing.T, reader *bufio.Reader, hub *events.Hub)
+	}{
+		{
+			name: "New",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerAccounts(gomock.Any(), gomock.Eq(account.Owner)).Times(1).Return([]db.Accounts{account}, nil)
+				store.EXPECT().GetLastAggregateOutboxEventID(gomock.Any(), gomock.Any()).Times(1).Return(int64(5), nil)
+
+				received := accountOutboxEvent(6, db.EventTypeTransferReceived, account)
+				gomock.InOrder(
+					store.EXPECT().ListAggregateOutboxEvents(gomock.Any(), gomock.Eq(listArg(5))).Times(1).Return([]db.OutboxEvents{received}, nil),
+					store.EXPECT().ListAggregateOutboxEvents(gomock.Any(), gomock.Eq(listArg(6))).AnyTimes().Return([]db.OutboxEvents{}, nil),
+				)
+			},
+			check: func(t *testing.T, reader *bufio.Reader, hub *events.Hub) {
+				// the stream starts with the accounts as they are
+				event := readStreamEvent(t, reader)
+				require.Equal(t, accountsStreamEvent, event.Event)
+				require.Equal(t, fmt.Sprintf("%d:5", account.ID), event.ID)
+
+				var accounts []db.Accounts
+				require.NoError(t, json.Unmarshal([]byte(event.Data), &accounts))
+				require.Len(t, accounts, 1)
+				require.Equal(t, account.Balance, accounts[0].Balance)
+
+				hub.Notify(key)
+				event = readStreamEvent(t, reader)
+				require.Equal(t, db.EventTypeTransferReceived, event.Event)
+				require.Equal(t, fmt.Sprintf("%d:6", account.ID), event.ID)
+
+				var received events.Event
+				require.NoError(t, json.Unmarshal([]byte(event.Data), &received))
+				require.Equal(t, int64(6), received.ID)
+				require.Equal(t, db.EventTypeTransferReceived, received.Type)
+			},
+		},
+		{
+			name:        "Resumed",
+			lastEventID: fmt.Sprintf("%d:3", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerAccounts(gomock.Any(), gomock.Eq(account.Owner)).Times(1).Return([]db.Accounts{account}, nil)
+				store.EXPECT().GetLastAggregateOutboxEventID(gomock.Any(), gomock.Any()).Times(0)
+
+				missed := []db.OutboxEvents{
+					accountOutboxEvent(4, db.EventTypeTransferSent, account),
+					accountOutboxEvent(9, db.EventTypeTransferReceived, account),
+				}
+				gomock.InOrder(
+					store.EXPECT().ListAggregateOutboxEvents(gomock.Any(), gomock.Eq(listArg(3))).Times(1).Return(missed, nil),
+					store.EXPECT().ListAggregateOutboxEvents(gomock.Any(), gomock.Eq(listArg(9))).AnyTimes().Return([]db.OutboxEvents{}, nil),
+				)
+			},
+			check: func(t *testing.T, reader *bufio.Reader, hub *events.Hub) {
+				// the events missed since the last one come first, without the accounts
+				event := readStreamEvent(t, reader)
+				require.Equal(t, db.EventTypeTransferSent, event.Event)
+				require.Equal(t, fmt.Sprintf("%d:4", account.ID), event.ID)
+
+				event = readStreamEvent(t, reader)
+				require.Equal(t, db.EventTypeTransferReceived, event.Event)
+				require.Equal(t, fmt.Sprintf("%d:9", account.ID), event.ID)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			httpServer := httptest.NewServer(server.router)
+			defer httpServer.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/account_events", nil)
+			require.NoError(t, err)
+			if tc.lastEventID != "" {
+				request.Header.Set(lastEventIDHeader, tc.lastEventID)
+			}
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+			tc.check(t, bufio.NewReader(response.Body), server.hub)
+		})
+	}
+}
+
+func TestStreamAccountEventsInvalidLastEventID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListOwnerAccounts(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/account_events?last_event_id=12", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestStreamCursor(t *testing.T) {
+	cursor := streamCursor{13: 338, 12: 340, 7: 0}
+	require.Equal(t, "7:0,12:340,13:338", cursor.String())
+
+	parsed, err := parseStreamCursor(cursor.String())
+	require.NoError(t, err)
+	require.Equal(t, cursor, parsed)
+
+	for _, s := range []string{"12", "12:", "a:1", "12:-1", "12:1,"} {
+		_, err := parseStreamCursor(s)
+		require.ErrorIs(t, err, errInvalidStreamCursor, s)
+	}
+}
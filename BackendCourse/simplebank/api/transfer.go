@@ -2,20 +2,75 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
 )
 
+// Transfer modes: an instant transfer settles immediately,
+// an authorize transfer only places a hold that is later captured or voided.
+const (
+	transferModeInstant   = "instant"
+	transferModeAuthorize = "authorize"
+)
+
+// The to account is given by its ID or by an alias of its owner, see Recipient,
+// or by one of the authenticated user's saved payees.
 type TransferRequest struct {
+	Recipient
+	PayeeID       int64  `json:"payee_id" binding:"omitempty,min=1"`
 	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
 	Amount        int64  `json:"amount" binding:"required,gt=0"`
 	Currency      string `json:"currency" binding:"required,oneof=USD EUR CAD"`
+	Mode          string `json:"mode" binding:"omitempty,oneof=instant authorize"`
+	Description   string `json:"description" binding:"omitempty,max=140,memo"`
+	Reference     string `json:"reference" binding:"omitempty,max=35,reference"`
+	Category      string `json:"category" binding:"omitempty,max=32,category"`
+}
+
+// TransferResponse is what one side of a transfer sees of it: the transfer, with that side's account and entry.
+// The other side's account, with its owner and balance, belongs to someone else and is left out.
+// The fee is only shown to the from side, which pays it.
+type TransferResponse struct {
+	Transfer    db.Transfers     `json:"transfer"`
+	FromAccount *db.Accounts     `json:"from_account,omitempty"`
+	FromEntry   *db.Entries      `json:"from_entry,omitempty"`
+	ToAccount   *db.Accounts     `json:"to_account,omitempty"`
+	ToEntry     *db.Entries      `json:"to_entry,omitempty"`
+	Fee         *db.FeeBreakdown `json:"fee,omitempty"`
 }
 
+// fromSideResponse returns the transfer as its from side sees it.
+func fromSideResponse(result db.TransferTxResult) TransferResponse {
+	return TransferResponse{
+		Transfer:    result.Transfer,
+		FromAccount: &result.FromAccount,
+		FromEntry:   &result.FromEntry,
+		Fee:         &result.Fee,
+	}
+}
+
+// toSideResponse returns the transfer as its to side sees it.
+func toSideResponse(result db.TransferTxResult) TransferResponse {
+	return TransferResponse{
+		Transfer:  result.Transfer,
+		ToAccount: &result.ToAccount,
+		ToEntry:   &result.ToEntry,
+	}
+}
+
+// This is one API handler function that handles the creation of a new transfer.
+// It is called when a POST request is made to the /transfers endpoint, by the owner of the from account.
+// A transfer above the from account's approval threshold isn't made right away: a transfer request is
+// created instead, which an approver of the account must approve.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/transfers", server.createTransfer)
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req TransferRequest
 	// Bind JSON request to the TransferRequest struct
@@ -28,10 +83,51 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	if !server.validAccount(ctx, req.FromAccountID, req.Currency) {
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	var toAccount db.Accounts
+	if req.PayeeID != 0 {
+		if req.Recipient != (Recipient{}) {
+			err := errors.New("payee_id can't be given together with another recipient")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		toAccount, valid = server.resolvePayee(ctx, req.PayeeID, req.Currency)
+	} else {
+		toAccount, valid = server.resolveRecipient(ctx, req.Recipient, req.Currency)
+	}
+	if !valid {
+		return
+	}
+	req.ToAccountID = toAccount.ID
+	if req.ToAccountID == req.FromAccountID {
+		err := errors.New("can't transfer to the from account")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	if !server.validAccount(ctx, req.ToAccountID, req.Currency) {
+	// large transfers wait for a second user's approval; a hold would let them skip it
+	if db.NeedsApproval(fromAccount, req.Amount) {
+		if req.Mode == transferModeAuthorize {
+			err := errors.New("transfers above the approval threshold can't be authorized")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		server.requestTransferApproval(ctx, req, authPayload.Username)
+		return
+	}
+
+	if req.Mode == transferModeAuthorize {
+		server.authorizeTransfer(ctx, req)
 		return
 	}
 
@@ -39,34 +135,281 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		FromAccountID: req.FromAccountID,
 		ToAccountID:   req.ToAccountID,
 		Amount:        req.Amount,
+		Description:   req.Description,
+		Reference:     req.Reference,
+		Category:      req.Category,
+		Audit:         auditMeta(ctx),
 	}
 
 	// Call the store to create the transfer in the database
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
+		transferErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fromSideResponse(result))
+}
+
+// authorizeTransfer places a hold for the transfer, which expires after the configured hold duration.
+func (server *Server) authorizeTransfer(ctx *gin.Context, req TransferRequest) {
+	arg := db.AuthorizeTransferTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		ExpiresAt:     time.Now().Add(server.config.HoldDuration),
+		Description:   req.Description,
+		Reference:     req.Reference,
+		Category:      req.Category,
+		Audit:         auditMeta(ctx),
+	}
+
+	result, err := server.store.AuthorizeTransferTx(ctx, arg)
+	if err != nil {
+		transferErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// transferErrorResponse maps the errors of moving money out of an account to a response.
+// A transfer limit that was hit is reported with its name and when it resets, and a payee that is still
+// cooling off with when it ends and the largest amount allowed until then, so clients can tell the user.
+func transferErrorResponse(ctx *gin.Context, err error) {
+	var limitErr *db.LimitExceededError
+	var coolingOffErr *db.PayeeCoolingOffError
+	switch {
+	case errors.As(err, &limitErr):
+		response := gin.H{
+			"error": err.Error(),
+			"code":  "limit_exceeded",
+			"limit": limitErr.Limit,
+			"max":   limitErr.Max,
+		}
+		if !limitErr.ResetsAt.IsZero() {
+			response["resets_at"] = limitErr.ResetsAt
+		}
+		ctx.JSON(http.StatusForbidden, response)
+	case errors.As(err, &coolingOffErr):
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":             err.Error(),
+			"code":              "payee_cooling_off",
+			"cooling_off_until": coolingOffErr.CoolingOffUntil,
+			"max_amount":        coolingOffErr.MaxAmount,
+		})
+	case errors.Is(err, db.ErrInsufficientFunds):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	default:
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+type TransferURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// An empty or zero amount captures the full authorized amount
+type CaptureTransferRequest struct {
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// This is one API handler function that handles capturing a pending transfer.
+// It is called when a POST request is made to the /transfers/:id/capture endpoint,
+// by the owner of the to account, i.e. the one the hold is paying.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/transfers/:id/capture", server.captureTransfer)
+func (server *Server) captureTransfer(ctx *gin.Context) {
+	var uri TransferURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.holdParty(ctx, uri.ID, false); !ok {
+		return
+	}
+
+	// the body is optional
+	var req CaptureTransferRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	result, err := server.store.CaptureTransferTx(ctx, db.CaptureTransferTxParams{
+		TransferID: uri.ID,
+		Amount:     req.Amount,
+		Audit:      auditMeta(ctx),
+	})
+	if err != nil {
+		server.holdErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSideResponse(result))
+}
+
+// This is one API handler function that handles voiding a pending transfer.
+// It is called when a POST request is made to the /transfers/:id/void endpoint,
+// by the owner of the from or the to account.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/transfers/:id/void", server.voidTransfer)
+func (server *Server) voidTransfer(ctx *gin.Context) {
+	var uri TransferURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, account, ok := server.holdParty(ctx, uri.ID, true)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.ReleaseTransferTx(ctx, db.ReleaseTransferTxParams{
+		TransferID: uri.ID,
+		Status:     db.TransferStatusVoided,
+		Audit:      auditMeta(ctx),
+	})
+	if err != nil {
+		server.holdErrorResponse(ctx, err)
+		return
+	}
+
+	// only the from account had money held
+	response := TransferResponse{Transfer: result.Transfer}
+	if account.ID == result.Transfer.FromAccountID {
+		response.FromAccount = &result.FromAccount
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// An empty or zero amount reverses whatever is left of the transfer
+type ReverseTransferRequest struct {
+	Amount int64  `json:"amount" binding:"min=0"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// This is one API handler function that handles reversing a settled transfer.
+// It is called when a POST request is made to the /transfers/:id/reverse endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/transfers/:id/reverse", server.reverseTransfer)
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var uri TransferURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ReverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID:  uri.ID,
+		Amount:      req.Amount,
+		InitiatedBy: authPayload.Username,
+		Reason:      req.Reason,
+		Audit:       auditMeta(ctx),
+	})
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrReversalExceedsTransfer):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrTransferNotReversible),
+			errors.Is(err, db.ErrInsufficientFunds):
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
 		return
 	}
 
 	ctx.JSON(http.StatusOK, result)
 }
 
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) bool {
+// holdParty returns the transfer and the authenticated user's account of it if they own its to account,
+// or, when fromAllowed is true, its from account, otherwise it writes the error response and returns false.
+func (server *Server) holdParty(ctx *gin.Context, transferID int64, fromAllowed bool) (db.Transfers, db.Accounts, bool) {
+	transfer, err := server.store.GetTransfer(ctx, transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return transfer, db.Accounts{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return transfer, db.Accounts{}, false
+	}
+
+	accountIDs := []int64{transfer.ToAccountID}
+	if fromAllowed {
+		accountIDs = append(accountIDs, transfer.FromAccountID)
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountID := range accountIDs {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return transfer, account, false
+		}
+		if account.Owner == authPayload.Username {
+			return transfer, account, true
+		}
+	}
+
+	err = errors.New("transfer doesn't belong to the authenticated user")
+	ctx.JSON(http.StatusForbidden, errorResponse(err))
+	return transfer, db.Accounts{}, false
+}
+
+// holdErrorResponse maps the errors of capturing or voiding a hold to a response
+func (server *Server) holdErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrCaptureExceedsHold):
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.Is(err, db.ErrTransferNotPending),
+		errors.Is(err, db.ErrHoldExpired),
+		errors.Is(err, db.ErrInsufficientFunds):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// validAccount returns the account if it exists, is in the given currency and
+// isn't a pocket, otherwise it writes the error response and returns false.
+func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Accounts, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return false
+			return account, false
 		}
 
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return false
+		return account, false
 	}
 
 	if account.Currency != currency {
 		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("currency mismatch: %s vs %s", account.Currency, currency)))
-		return false
+		return account, false
+	}
+
+	if account.Type == util.PocketAccount {
+		ctx.JSON(http.StatusForbidden, errorResponse(errPocketTransfer))
+		return account, false
 	}
 
-	return true
+	return account, true
 }
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+type TransferBatchLegRequest struct {
+	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gt=0"`
+}
+
+type CreateTransferBatchRequest struct {
+	FromAccountID int64                     `json:"from_account_id" binding:"required,min=1"`
+	Currency      string                    `json:"currency" binding:"required,oneof=USD EUR CAD"`
+	Mode          string                    `json:"mode" binding:"required,oneof=atomic best_effort"`
+	Legs          []TransferBatchLegRequest `json:"legs" binding:"required,min=1,max=1000,dive"`
+}
+
+// This is one API handler function that handles paying many accounts from one source account in one request.
+// It is called when a POST request is made to the /transfer_batches endpoint.
+// Only the owner of the from account may pay from it.
+// Legs that can't be paid don't fail the request, they are reported in the batch and its legs.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/transfer_batches", server.createTransferBatch)
+func (server *Server) createTransferBatch(ctx *gin.Context) {
+	var req CreateTransferBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	legs := make([]db.BatchLeg, len(req.Legs))
+	for i, leg := range req.Legs {
+		if leg.ToAccountID == req.FromAccountID {
+			err := fmt.Errorf("leg %d pays into the from account", i)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		legs[i] = db.BatchLeg{ToAccountID: leg.ToAccountID, Amount: leg.Amount}
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// batches aren't approved, so they can't be used to get around the approval threshold,
+	// which a large payment split into small legs would otherwise do
+	var total int64
+	for _, leg := range legs {
+		total += leg.Amount
+	}
+	if db.NeedsApproval(fromAccount, total) {
+		err := errors.New("the batch total is above the approval threshold, its payments must be sent as single transfers")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.TransferBatchTx(ctx, db.TransferBatchTxParams{
+		InitiatedBy:   authPayload.Username,
+		FromAccountID: req.FromAccountID,
+		Currency:      req.Currency,
+		Mode:          req.Mode,
+		Legs:          legs,
+		Audit:         auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type TransferBatchURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the retrieval of a transfer batch with its legs.
+// It is called when a GET request is made to the /transfer_batches/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/transfer_batches/:id", server.getTransferBatch)
+func (server *Server) getTransferBatch(ctx *gin.Context) {
+	var uri TransferBatchURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	batch, err := server.store.GetTransferBatch(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if batch.InitiatedBy != authPayload.Username {
+		err := errors.New("transfer batch doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	legs, err := server.store.ListTransferBatchLegs(ctx, batch.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, db.TransferBatchTxResult{Batch: batch, Legs: legs})
+}
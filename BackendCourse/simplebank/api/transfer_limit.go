@@ -0,0 +1,140 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// Limits are set either for one account, or for a tier and currency.
+// A limit left out is not enforced.
+type CreateTransferLimitRequest struct {
+	AccountID     int64  `json:"account_id" binding:"omitempty,min=1"`
+	Tier          string `json:"tier" binding:"omitempty,oneof=standard premium"`
+	Currency      string `json:"currency" binding:"omitempty,oneof=USD EUR CAD"`
+	SingleMax     *int64 `json:"single_max" binding:"omitempty,min=0"`
+	DailyMax      *int64 `json:"daily_max" binding:"omitempty,min=0"`
+	MonthlyMax    *int64 `json:"monthly_max" binding:"omitempty,min=0"`
+	DailyCountMax *int32 `json:"daily_count_max" binding:"omitempty,min=0"`
+}
+
+// This is one API handler function that handles the creation of transfer limits.
+// It is called when a POST request is made to the /transfer_limits endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/transfer_limits", server.createTransferLimit)
+func (server *Server) createTransferLimit(ctx *gin.Context) {
+	var req CreateTransferLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	forAccount := req.AccountID != 0
+	forTier := req.Tier != "" && req.Currency != ""
+	if forAccount == forTier || (forAccount && (req.Tier != "" || req.Currency != "")) {
+		err := errors.New("either account_id, or tier and currency, must be given")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateTransferLimitParams{
+		AccountID:     sql.NullInt64{Int64: req.AccountID, Valid: forAccount},
+		Tier:          sql.NullString{String: req.Tier, Valid: forTier},
+		Currency:      sql.NullString{String: req.Currency, Valid: forTier},
+		SingleMax:     nullInt64(req.SingleMax),
+		DailyMax:      nullInt64(req.DailyMax),
+		MonthlyMax:    nullInt64(req.MonthlyMax),
+		DailyCountMax: nullInt32(req.DailyCountMax),
+	}
+
+	limit, err := server.store.CreateTransferLimitTx(ctx, db.CreateTransferLimitTxParams{
+		CreateTransferLimitParams: arg,
+		Audit:                     auditMeta(ctx),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation", "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+type ListTransferLimitsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles the retrieval of the transfer limits.
+// It is called when a GET request is made to the /transfer_limits endpoint, by bankers only.
+func (server *Server) listTransferLimits(ctx *gin.Context) {
+	var req ListTransferLimitsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	limits, err := server.store.ListTransferLimits(ctx, db.ListTransferLimitsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limits)
+}
+
+type TransferLimitRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the deletion of transfer limits.
+// It is called when a DELETE request is made to the /transfer_limits/:id endpoint, by bankers only.
+func (server *Server) deleteTransferLimit(ctx *gin.Context) {
+	var req TransferLimitRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteTransferLimitTx(ctx, db.DeleteTransferLimitTxParams{
+		ID:    req.ID,
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func nullInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferLimitAPI(t *testing.T) {
+	banker := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Tier",
+			body: gin.H{
+				"tier":            util.StandardTier,
+				"currency":        util.USD,
+				"single_max":      1000,
+				"daily_count_max": 0,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateTransferLimitParams{
+					Tier:          sql.NullString{String: util.StandardTier, Valid: true},
+					Currency:      sql.NullString{String: util.USD, Valid: true},
+					SingleMax:     sql.NullInt64{Int64: 1000, Valid: true},
+					DailyCountMax: sql.NullInt32{Int32: 0, Valid: true},
+				}
+				store.EXPECT().CreateTransferLimitTx(gomock.Any(), gomock.Eq(db.CreateTransferLimitTxParams{
+					CreateTransferLimitParams: arg,
+					Audit:                     testAuditMeta(banker),
+				})).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Account",
+			body: gin.H{
+				"account_id": 7,
+				"daily_max":  5000,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateTransferLimitParams{
+					AccountID: sql.NullInt64{Int64: 7, Valid: true},
+					DailyMax:  sql.NullInt64{Int64: 5000, Valid: true},
+				}
+				store.EXPECT().CreateTransferLimitTx(gomock.Any(), gomock.Eq(db.CreateTransferLimitTxParams{
+					CreateTransferLimitParams: arg,
+					Audit:                     testAuditMeta(banker),
+				})).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AccountAndTier",
+			body: gin.H{
+				"account_id": 7,
+				"tier":       util.PremiumTier,
+				"currency":   util.USD,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTransferLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TierWithoutCurrency",
+			body: gin.H{
+				"tier":      util.PremiumTier,
+				"daily_max": 5000,
+			},
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTransferLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Depositor",
+			body: gin.H{
+				"account_id": 7,
+				"daily_max":  5000,
+			},
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTransferLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfer_limits", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, banker, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
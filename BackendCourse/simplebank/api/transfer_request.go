@@ -0,0 +1,229 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+)
+
+// requestTransferApproval creates a transfer request waiting for approval instead of making the transfer.
+// It responds with 202 Accepted, since no money was moved yet.
+func (server *Server) requestTransferApproval(ctx *gin.Context, req TransferRequest, initiatedBy string) {
+	request, err := server.store.CreateTransferRequestTx(ctx, db.CreateTransferRequestTxParams{
+		CreateTransferRequestParams: db.CreateTransferRequestParams{
+			FromAccountID: req.FromAccountID,
+			ToAccountID:   req.ToAccountID,
+			Amount:        req.Amount,
+			InitiatedBy:   initiatedBy,
+			Description:   req.Description,
+			Reference:     req.Reference,
+			Category:      req.Category,
+		},
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, request)
+}
+
+type ListTransferRequestsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles listing the transfer requests waiting for the authenticated user's approval.
+// It is called when a GET request is made to the /transfer_requests endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/transfer_requests", server.listApprovableTransferRequests)
+func (server *Server) listApprovableTransferRequests(ctx *gin.Context) {
+	var req ListTransferRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	requests, err := server.store.ListApprovableTransferRequests(ctx, db.ListApprovableTransferRequestsParams{
+		Approver: authPayload.Username,
+		MaxRows:  req.PageSize,
+		SkipRows: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+type TransferRequestURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type TransferRequestResponse struct {
+	Request   db.TransferRequests           `json:"request"`
+	Decisions []db.TransferRequestDecisions `json:"decisions"`
+}
+
+// This is one API handler function that handles the retrieval of a transfer request with its decisions.
+// It is called when a GET request is made to the /transfer_requests/:id endpoint,
+// by the initiator of the request or an approver of its from account.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/transfer_requests/:id", server.getTransferRequest)
+func (server *Server) getTransferRequest(ctx *gin.Context) {
+	var uri TransferRequestURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, err := server.store.GetTransferRequest(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.InitiatedBy != authPayload.Username {
+		_, err = server.store.GetAccountApprover(ctx, db.GetAccountApproverParams{
+			AccountID: request.FromAccountID,
+			Username:  authPayload.Username,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err := errors.New("transfer request doesn't belong to the authenticated user")
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	decisions, err := server.store.ListTransferRequestDecisions(ctx, request.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, TransferRequestResponse{Request: request, Decisions: decisions})
+}
+
+// The reason is optional for an approval but required for a rejection
+type DecideTransferRequestRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// DecideTransferRequestResponse is a decision on a transfer request, with the transfer it made if it was approved,
+// as the from account's side sees it.
+type DecideTransferRequestResponse struct {
+	Request  db.TransferRequests         `json:"request"`
+	Decision db.TransferRequestDecisions `json:"decision"`
+	Transfer *TransferResponse           `json:"transfer,omitempty"`
+}
+
+// This is one API handler function that handles approving a transfer request, which makes its transfer.
+// It is called when a POST request is made to the /transfer_requests/:id/approve endpoint,
+// by an approver of the from account other than the initiator.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/transfer_requests/:id/approve", server.approveTransferRequest)
+func (server *Server) approveTransferRequest(ctx *gin.Context) {
+	arg, ok := bindDecideTransferRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.ApproveTransferRequestTx(ctx, arg)
+	if err != nil {
+		decideTransferRequestErrorResponse(ctx, err)
+		return
+	}
+
+	response := DecideTransferRequestResponse{Request: result.Request, Decision: result.Decision}
+	if result.Transfer != nil {
+		transfer := fromSideResponse(*result.Transfer)
+		response.Transfer = &transfer
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// This is one API handler function that handles rejecting a transfer request.
+// It is called when a POST request is made to the /transfer_requests/:id/reject endpoint,
+// by an approver of the from account other than the initiator.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/transfer_requests/:id/reject", server.rejectTransferRequest)
+func (server *Server) rejectTransferRequest(ctx *gin.Context) {
+	arg, ok := bindDecideTransferRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if arg.Reason == "" {
+		err := errors.New("a reason is required to reject a transfer request")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.RejectTransferRequestTx(ctx, arg)
+	if err != nil {
+		decideTransferRequestErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// bindDecideTransferRequest binds the uri and the optional body of a decision on a transfer request,
+// made by the authenticated user. If binding fails it writes the error response and returns false.
+func bindDecideTransferRequest(ctx *gin.Context) (db.DecideTransferRequestTxParams, bool) {
+	var arg db.DecideTransferRequestTxParams
+
+	var uri TransferRequestURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return arg, false
+	}
+
+	var req DecideTransferRequestRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return arg, false
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg = db.DecideTransferRequestTxParams{
+		RequestID: uri.ID,
+		DecidedBy: authPayload.Username,
+		Reason:    req.Reason,
+		Audit:     auditMeta(ctx),
+	}
+	return arg, true
+}
+
+// decideTransferRequestErrorResponse maps the errors of approving or rejecting a transfer request to a response.
+func decideTransferRequestErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrTransferRequestNotPending),
+		errors.Is(err, db.ErrSelfApproval),
+		errors.Is(err, db.ErrNotApprover):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	default:
+		// the transfer of an approved request can fail like any other transfer
+		transferErrorResponse(ctx, err)
+	}
+}
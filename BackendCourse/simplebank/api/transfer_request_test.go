@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecideTransferRequestAPI(t *testing.T) {
+	requestID := int64(7)
+	approver := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		action        string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Approve",
+			action: "approve",
+			body:   nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.DecideTransferRequestTxParams{RequestID: requestID, DecidedBy: approver, Audit: testAuditMeta(approver)}
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Reject",
+			action: "reject",
+			body:   []byte(`{"reason": "unknown payee"}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.DecideTransferRequestTxParams{
+					RequestID: requestID,
+					DecidedBy: approver,
+					Reason:    "unknown payee",
+					Audit:     testAuditMeta(approver),
+				}
+				store.EXPECT().RejectTransferRequestTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "RejectWithoutReason",
+			action: "reject",
+			body:   nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RejectTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "SelfApproval",
+			action: "approve",
+			body:   nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideTransferRequestTxResult{}, db.ErrSelfApproval)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NotApprover",
+			action: "reject",
+			body:   []byte(`{"reason": "no"}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RejectTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideTransferRequestTxResult{}, db.ErrNotApprover)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "AlreadyDecided",
+			action: "approve",
+			body:   nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideTransferRequestTxResult{}, db.ErrTransferRequestNotPending)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InsufficientFunds",
+			action: "approve",
+			body:   nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideTransferRequestTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfer_requests/%d/%s", requestID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tc.body))
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeaderKey, testRequestID)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, approver, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetTransferRequestAPI(t *testing.T) {
+	transferRequest := db.TransferRequests{
+		ID:            7,
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID:   util.RandomInt(1, 1000),
+		Amount:        util.RandomMoney(),
+		InitiatedBy:   util.RandomOwner(),
+		Status:        db.TransferRequestPendingApproval,
+	}
+	approver := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Initiator",
+			username: transferRequest.InitiatedBy,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferRequest.ID)).Times(1).Return(transferRequest, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					ListTransferRequestDecisions(gomock.Any(), gomock.Eq(transferRequest.ID)).
+					Times(1).
+					Return([]db.TransferRequestDecisions{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got TransferRequestResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, transferRequest.ID, got.Request.ID)
+			},
+		},
+		{
+			name:     "Approver",
+			username: approver,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferRequest.ID)).Times(1).Return(transferRequest, nil)
+
+				arg := db.GetAccountApproverParams{AccountID: transferRequest.FromAccountID, Username: approver}
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Eq(arg)).Times(1)
+				store.EXPECT().ListTransferRequestDecisions(gomock.Any(), gomock.Eq(transferRequest.ID)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unrelated",
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferRequest.ID)).Times(1).Return(transferRequest, nil)
+				store.EXPECT().
+					GetAccountApprover(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountApprovers{}, sql.ErrNoRows)
+				store.EXPECT().ListTransferRequestDecisions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: approver,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTransferRequest(gomock.Any(), gomock.Eq(transferRequest.ID)).
+					Times(1).
+					Return(db.TransferRequests{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfer_requests/%d", transferRequest.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -22,6 +22,9 @@ type UserResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"full_name"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	Tier              string    `json:"tier"`
+	IsEmailVerified   bool      `json:"is_email_verified"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 }
@@ -31,6 +34,9 @@ func newUserResponse(user db.Users) UserResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
+		Tier:              user.Tier,
+		IsEmailVerified:   user.IsEmailVerified,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -68,8 +74,15 @@ func (server *Server) createUser(ctx *gin.Context) {
 		Email:          req.Email,
 	}
 
-	// Call the store to create the account in the database
-	user, err := server.store.CreateUser(ctx, arg)
+	// whoever signs up is the actor of their own creation
+	audit := auditMeta(ctx)
+	audit.Actor = req.Username
+
+	// Call the store to create the user in the database, with its audit event
+	result, err := server.store.CreateUserTx(ctx, db.CreateUserTxParams{
+		CreateUserParams: arg,
+		Audit:            audit,
+	})
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
@@ -83,7 +96,7 @@ func (server *Server) createUser(ctx *gin.Context) {
 	}
 
 	// Create a response object to return to the client
-	response := newUserResponse(user)
+	response := newUserResponse(result.User)
 
 	ctx.JSON(http.StatusOK, response)
 }
@@ -117,15 +130,25 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	audit := auditMeta(ctx)
+	audit.Actor = user.Username
+
 	// Verify the password
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
+		if !server.auditLogin(ctx, audit, db.AuditActionUserLoginFailed) {
+			return
+		}
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
+	if !server.auditLogin(ctx, audit, db.AuditActionUserLogin) {
+		return
+	}
+
 	// Create a new access token
-	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -138,3 +161,87 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+// auditLogin records a login attempt of the user, otherwise it writes the error response and returns false.
+func (server *Server) auditLogin(ctx *gin.Context, audit db.AuditMeta, action string) bool {
+	_, err := server.store.AuditTx(ctx, db.AuditTxParams{
+		AuditMeta:  audit,
+		Action:     action,
+		TargetType: db.AuditTargetUser,
+		TargetID:   audit.Actor,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+type UserURIRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type UpdateUserTierRequest struct {
+	Tier string `json:"tier" binding:"required,oneof=standard premium"`
+}
+
+// This is one API handler function that handles moving a user to another tier, which changes their transfer limits.
+// It is called when a PATCH request is made to the /users/:username/tier endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.PATCH("/users/:username/tier", server.updateUserTier)
+func (server *Server) updateUserTier(ctx *gin.Context) {
+	var uri UserURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateUserTierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserTierTx(ctx, db.UpdateUserTierTxParams{
+		UpdateUserTierParams: db.UpdateUserTierParams{
+			Username: uri.Username,
+			Tier:     req.Tier,
+		},
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// This is one API handler function that handles marking the email of a user as verified,
+// once the bank checked that it belongs to them. Only a verified email can be used to pay the user.
+// It is called when a POST request is made to the /users/:username/verify_email endpoint, by bankers only.
+// The handler was set by the router in the NewServer function by calling:
+// bankerRoutes.POST("/users/:username/verify_email", server.verifyUserEmail)
+func (server *Server) verifyUserEmail(ctx *gin.Context) {
+	var uri UserURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.VerifyUserEmail(ctx, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"regexp"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/webhook"
+)
+
+// referenceChars are the characters allowed in an external reference: the SWIFT character set,
+// so references can be passed on to other banks unchanged.
+var referenceChars = regexp.MustCompile(`^[A-Za-z0-9/\-?:().,'+ ]*$`)
+
+// categoryChars are the characters allowed in a category, e.g. "groceries" or "rent-2024".
+var categoryChars = regexp.MustCompile(`^[a-z0-9_-]*$`)
+
+// validMemo accepts any printable text, in any language, but no control characters such as new lines.
+var validMemo validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	memo, ok := fieldLevel.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+	for _, r := range memo {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+var validReference validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	reference, ok := fieldLevel.Field().Interface().(string)
+	return ok && referenceChars.MatchString(reference)
+}
+
+var validCategory validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	category, ok := fieldLevel.Field().Interface().(string)
+	return ok && categoryChars.MatchString(category)
+}
+
+// validWebhookURL accepts the https URLs that webhook deliveries may be sent to, see webhook.ValidateURL.
+var validWebhookURL validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	rawURL, ok := fieldLevel.Field().Interface().(string)
+	return ok && webhook.ValidateURL(rawURL) == nil
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/token"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/webhook"
+)
+
+type CreateWebhookEndpointRequest struct {
+	URL        string   `json:"url" binding:"required,webhook_url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,unique,dive,oneof=user.created account.created transfer.sent transfer.received entry.created"`
+}
+
+// WebhookEndpointResponse is a webhook endpoint without its secret,
+// which is only shown once, when the endpoint is created.
+type WebhookEndpointResponse struct {
+	ID         int64     `json:"id"`
+	Owner      string    `json:"owner"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateWebhookEndpointResponse struct {
+	WebhookEndpointResponse
+	Secret string `json:"secret"`
+}
+
+func newWebhookEndpointResponse(endpoint db.WebhookEndpoints) WebhookEndpointResponse {
+	return WebhookEndpointResponse{
+		ID:         endpoint.ID,
+		Owner:      endpoint.Owner,
+		URL:        endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		CreatedAt:  endpoint.CreatedAt,
+	}
+}
+
+// This is one API handler function that handles registering a webhook endpoint of the authenticated user.
+// The events of the given types about the user and their accounts are posted to it, signed with its secret.
+// The endpoint must be an https URL on a public address.
+// It is called when a POST request is made to the /webhook_endpoints endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/webhook_endpoints", server.createWebhookEndpoint)
+func (server *Server) createWebhookEndpoint(ctx *gin.Context) {
+	var req CreateWebhookEndpointRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoint, err := server.store.CreateWebhookEndpointTx(ctx, db.CreateWebhookEndpointTxParams{
+		CreateWebhookEndpointParams: db.CreateWebhookEndpointParams{
+			Owner:      authPayload.Username,
+			Url:        req.URL,
+			Secret:     secret,
+			EventTypes: req.EventTypes,
+		},
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, CreateWebhookEndpointResponse{
+		WebhookEndpointResponse: newWebhookEndpointResponse(endpoint),
+		Secret:                  endpoint.Secret,
+	})
+}
+
+// This is one API handler function that handles listing the webhook endpoints of the authenticated user.
+// It is called when a GET request is made to the /webhook_endpoints endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/webhook_endpoints", server.listWebhookEndpoints)
+func (server *Server) listWebhookEndpoints(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]WebhookEndpointResponse, len(endpoints))
+	for i, endpoint := range endpoints {
+		rsp[i] = newWebhookEndpointResponse(endpoint)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type WebhookURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is one API handler function that handles the deletion of a webhook endpoint, with its delivery log.
+// It is called when a DELETE request is made to the /webhook_endpoints/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.DELETE("/webhook_endpoints/:id", server.deleteWebhookEndpoint)
+func (server *Server) deleteWebhookEndpoint(ctx *gin.Context) {
+	endpoint, ok := server.ownedWebhookEndpoint(ctx)
+	if !ok {
+		return
+	}
+
+	err := server.store.DeleteWebhookEndpointTx(ctx, db.DeleteWebhookEndpointTxParams{
+		ID:    endpoint.ID,
+		Audit: auditMeta(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// This is one API handler function that handles the delivery log of a webhook endpoint, newest first,
+// with the status the endpoint answered the last attempt of every delivery.
+// It is called when a GET request is made to the /webhook_endpoints/:id/deliveries endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/webhook_endpoints/:id/deliveries", server.listWebhookDeliveries)
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	endpoint, ok := server.ownedWebhookEndpoint(ctx)
+	if !ok {
+		return
+	}
+
+	var req ListWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Status:     nullString(req.Status),
+		MaxRows:    req.PageSize,
+		SkipRows:   (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+type WebhookDeliveryResponse struct {
+	Delivery db.WebhookDeliveries         `json:"delivery"`
+	Attempts []db.WebhookDeliveryAttempts `json:"attempts"`
+}
+
+// This is one API handler function that handles the retrieval of a webhook delivery, with every attempt to send it.
+// It is called when a GET request is made to the /webhook_deliveries/:id endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.GET("/webhook_deliveries/:id", server.getWebhookDelivery)
+func (server *Server) getWebhookDelivery(ctx *gin.Context) {
+	delivery, ok := server.ownedWebhookDelivery(ctx)
+	if !ok {
+		return
+	}
+
+	attempts, err := server.store.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, WebhookDeliveryResponse{Delivery: delivery, Attempts: attempts})
+}
+
+// This is one API handler function that handles replaying a failed webhook delivery:
+// it is sent again as soon as possible, and retried like a new delivery if that fails too.
+// It is called when a POST request is made to the /webhook_deliveries/:id/replay endpoint.
+// The handler was set by the router in the NewServer function by calling:
+// authRoutes.POST("/webhook_deliveries/:id/replay", server.replayWebhookDelivery)
+func (server *Server) replayWebhookDelivery(ctx *gin.Context) {
+	delivery, ok := server.ownedWebhookDelivery(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := server.store.ReplayWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("only failed deliveries can be replayed")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// ownedWebhookEndpoint binds the endpoint ID from the uri and returns the endpoint if it belongs to the authenticated user.
+// If anything fails it writes the error response and returns false.
+func (server *Server) ownedWebhookEndpoint(ctx *gin.Context) (db.WebhookEndpoints, bool) {
+	var uri WebhookURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.WebhookEndpoints{}, false
+	}
+
+	return server.userWebhookEndpoint(ctx, uri.ID)
+}
+
+// ownedWebhookDelivery binds the delivery ID from the uri and returns the delivery if its endpoint
+// belongs to the authenticated user. If anything fails it writes the error response and returns false.
+func (server *Server) ownedWebhookDelivery(ctx *gin.Context) (db.WebhookDeliveries, bool) {
+	var uri WebhookURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.WebhookDeliveries{}, false
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return delivery, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return delivery, false
+	}
+
+	_, ok := server.userWebhookEndpoint(ctx, delivery.EndpointID)
+	return delivery, ok
+}
+
+// userWebhookEndpoint returns the webhook endpoint if it belongs to the authenticated user.
+// If it doesn't, or it doesn't exist, it writes the error response and returns false.
+func (server *Server) userWebhookEndpoint(ctx *gin.Context, endpointID int64) (db.WebhookEndpoints, bool) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, endpointID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return endpoint, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return endpoint, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if endpoint.Owner != authPayload.Username {
+		err := errors.New("webhook endpoint doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return endpoint, false
+	}
+
+	return endpoint, true
+}
@@ -0,0 +1,26 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_overdraft_limit_check" CHECK ("overdraft_limit" >= 0);
+
+CREATE TABLE "overdraft_charges" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "charge_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'the balance may go down to -overdraft_limit';
+
+COMMENT ON COLUMN "overdraft_charges"."balance" IS 'the negative balance the interest was charged on';
+
+COMMENT ON COLUMN "overdraft_charges"."amount" IS 'must be positive';
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+-- at most one interest charge per account per day
+ALTER TABLE "overdraft_charges" ADD CONSTRAINT "overdraft_charges_account_date_key" UNIQUE ("account_id", "charge_date");
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "transfer_fees";
+
+DROP TABLE IF EXISTS "fee_rules";
+
+-- the fee accounts may have entries by now, so they are kept and only unlinked
+DROP TABLE IF EXISTS "system_accounts";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "type";
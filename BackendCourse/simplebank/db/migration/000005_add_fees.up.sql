@@ -0,0 +1,71 @@
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking';
+
+-- the bank's own accounts belong to a system user that can't log in
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email", "role")
+VALUES ('simplebank', '', 'Simple Bank', 'system@simplebank.local', 'system');
+
+CREATE TABLE "system_accounts" (
+  "purpose" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint UNIQUE NOT NULL,
+  PRIMARY KEY ("purpose", "currency")
+);
+
+CREATE TABLE "fee_rules" (
+  "id" bigserial PRIMARY KEY,
+  "currency" varchar,
+  "account_type" varchar,
+  "flat_fee" bigint NOT NULL DEFAULT 0,
+  "percentage_bps" bigint NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transfer_fees" (
+  "transfer_id" bigint PRIMARY KEY,
+  "fee_rule_id" bigint NOT NULL,
+  "fee_account_id" bigint NOT NULL,
+  "from_entry_id" bigint NOT NULL,
+  "to_entry_id" bigint NOT NULL,
+  "flat_fee" bigint NOT NULL,
+  "percentage_fee" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "fee_rules"."currency" IS 'NULL matches any currency';
+
+COMMENT ON COLUMN "fee_rules"."account_type" IS 'NULL matches any account type';
+
+COMMENT ON COLUMN "fee_rules"."percentage_bps" IS 'percentage of the amount in basis points';
+
+COMMENT ON COLUMN "fee_rules"."max_fee" IS 'NULL means no cap';
+
+COMMENT ON COLUMN "transfer_fees"."fee_rule_id" IS 'not a foreign key, so rules can be deleted after they were applied';
+
+COMMENT ON COLUMN "transfer_fees"."amount" IS 'the fee actually charged, after the min and max caps';
+
+ALTER TABLE "system_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("fee_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("from_entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("to_entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "fee_rules" ADD CONSTRAINT "fee_rules_amounts_check" CHECK (
+  "flat_fee" >= 0 AND "percentage_bps" >= 0 AND "min_fee" >= 0 AND ("max_fee" IS NULL OR "max_fee" >= "min_fee")
+);
+
+-- one fee account per supported currency
+WITH "fee_accounts" AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency", "type")
+  SELECT 'simplebank', 0, "currency", 'system'
+  FROM unnest(ARRAY['USD', 'EUR', 'CAD']) AS "currency"
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'fees', "currency", "id" FROM "fee_accounts";
@@ -0,0 +1,23 @@
+ALTER TABLE "accounts" ADD COLUMN "available_balance" bigint NOT NULL DEFAULT 0;
+
+UPDATE "accounts" SET "available_balance" = "balance";
+
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'captured';
+
+ALTER TABLE "transfers" ADD COLUMN "captured_amount" bigint NOT NULL DEFAULT 0;
+
+UPDATE "transfers" SET "captured_amount" = "amount";
+
+ALTER TABLE "transfers" ADD COLUMN "expires_at" timestamptz;
+
+CREATE INDEX ON "transfers" ("expires_at") WHERE "status" = 'pending';
+
+COMMENT ON COLUMN "accounts"."available_balance" IS 'balance minus the amounts held by pending transfers';
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive, the authorized amount for holds';
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending, captured, voided or expired';
+
+COMMENT ON COLUMN "transfers"."captured_amount" IS 'the amount actually moved, at most amount';
+
+COMMENT ON COLUMN "transfers"."expires_at" IS 'when a pending hold is released if not captured';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "transfer_reversals";
+
+ALTER TABLE IF EXISTS "transfers" DROP CONSTRAINT IF EXISTS "transfers_reversed_amount_check";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reversed_amount";
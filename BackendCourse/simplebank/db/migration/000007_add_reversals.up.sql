@@ -0,0 +1,26 @@
+ALTER TABLE "transfers" ADD COLUMN "reversed_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_reversed_amount_check" CHECK (
+  "reversed_amount" >= 0 AND "reversed_amount" <= "captured_amount"
+);
+
+CREATE TABLE "transfer_reversals" (
+  "transfer_id" bigint PRIMARY KEY,
+  "original_transfer_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "initiated_by" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "transfer_reversals" ("original_transfer_id");
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'sum of the reversals of this transfer, never more than the captured amount';
+
+COMMENT ON COLUMN "transfer_reversals"."transfer_id" IS 'the compensating transfer';
+
+ALTER TABLE "transfer_reversals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_reversals" ADD FOREIGN KEY ("original_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_reversals" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
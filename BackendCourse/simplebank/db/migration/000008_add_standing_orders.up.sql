@@ -0,0 +1,58 @@
+CREATE TABLE "standing_orders" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "frequency" varchar NOT NULL,
+  "interval_count" integer NOT NULL DEFAULT 1,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "next_run_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "standing_order_executions" (
+  "id" bigserial PRIMARY KEY,
+  "standing_order_id" bigint NOT NULL,
+  "scheduled_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'running',
+  "transfer_id" bigint,
+  "error" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+CREATE INDEX ON "standing_orders" ("owner");
+
+CREATE INDEX ON "standing_orders" ("next_run_at") WHERE "status" = 'active';
+
+CREATE INDEX ON "standing_order_executions" ("standing_order_id");
+
+ALTER TABLE "standing_order_executions" ADD CONSTRAINT "standing_order_executions_order_scheduled_key" UNIQUE ("standing_order_id", "scheduled_at");
+
+ALTER TABLE "standing_orders" ADD CONSTRAINT "standing_orders_amount_check" CHECK ("amount" > 0 AND "interval_count" > 0);
+
+ALTER TABLE "standing_orders" ADD CONSTRAINT "standing_orders_frequency_check" CHECK ("frequency" IN ('daily', 'weekly', 'monthly'));
+
+COMMENT ON COLUMN "standing_orders"."frequency" IS 'daily, weekly or monthly';
+
+COMMENT ON COLUMN "standing_orders"."interval_count" IS 'run every interval_count periods of the frequency';
+
+COMMENT ON COLUMN "standing_orders"."end_at" IS 'NULL means the order runs until cancelled';
+
+COMMENT ON COLUMN "standing_orders"."status" IS 'active, cancelled or completed';
+
+COMMENT ON COLUMN "standing_order_executions"."status" IS 'running, succeeded or failed';
+
+ALTER TABLE "standing_orders" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "standing_orders" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "standing_orders" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "standing_order_executions" ADD FOREIGN KEY ("standing_order_id") REFERENCES "standing_orders" ("id");
+
+ALTER TABLE "standing_order_executions" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
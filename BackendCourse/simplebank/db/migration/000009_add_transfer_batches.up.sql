@@ -0,0 +1,45 @@
+CREATE TABLE "transfer_batches" (
+  "id" bigserial PRIMARY KEY,
+  "initiated_by" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "mode" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'processing',
+  "leg_count" integer NOT NULL,
+  "total_amount" bigint NOT NULL,
+  "succeeded_count" integer NOT NULL DEFAULT 0,
+  "error" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+CREATE TABLE "transfer_batch_legs" (
+  "id" bigserial PRIMARY KEY,
+  "batch_id" bigint NOT NULL,
+  "leg_index" integer NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar
+);
+
+CREATE INDEX ON "transfer_batches" ("initiated_by");
+
+ALTER TABLE "transfer_batch_legs" ADD CONSTRAINT "transfer_batch_legs_batch_leg_key" UNIQUE ("batch_id", "leg_index");
+
+COMMENT ON COLUMN "transfer_batches"."mode" IS 'atomic or best_effort';
+
+COMMENT ON COLUMN "transfer_batches"."status" IS 'processing, completed, partial or failed';
+
+COMMENT ON COLUMN "transfer_batch_legs"."status" IS 'succeeded or failed';
+
+COMMENT ON COLUMN "transfer_batch_legs"."to_account_id" IS 'not a foreign key, so a leg to a missing account can be recorded as failed';
+
+ALTER TABLE "transfer_batches" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_batches" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_batch_legs" ADD FOREIGN KEY ("batch_id") REFERENCES "transfer_batches" ("id");
+
+ALTER TABLE "transfer_batch_legs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,41 @@
+ALTER TABLE "users" ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "tier" varchar,
+  "currency" varchar,
+  "account_id" bigint UNIQUE,
+  "single_max" bigint,
+  "daily_max" bigint,
+  "monthly_max" bigint,
+  "daily_count_max" integer,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("tier", "currency");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+COMMENT ON COLUMN "users"."tier" IS 'selects the transfer limits of the user''s accounts';
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'limits of a single account, which replace the limits of its owner''s tier';
+
+COMMENT ON COLUMN "transfer_limits"."daily_max" IS 'total over a rolling 24 hours, NULL means no limit';
+
+COMMENT ON COLUMN "transfer_limits"."monthly_max" IS 'total over the calendar month in UTC, NULL means no limit';
+
+COMMENT ON COLUMN "transfer_limits"."daily_count_max" IS 'number of transfers over a rolling 24 hours, NULL means no limit';
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_target_check" CHECK (
+  ("account_id" IS NOT NULL AND "tier" IS NULL AND "currency" IS NULL) OR
+  ("account_id" IS NULL AND "tier" IS NOT NULL AND "currency" IS NOT NULL)
+);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_amounts_check" CHECK (
+  ("single_max" IS NULL OR "single_max" >= 0) AND
+  ("daily_max" IS NULL OR "daily_max" >= 0) AND
+  ("monthly_max" IS NULL OR "monthly_max" >= 0) AND
+  ("daily_count_max" IS NULL OR "daily_count_max" >= 0)
+);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "transfer_request_decisions";
+
+DROP TABLE IF EXISTS "transfer_requests";
+
+DROP TABLE IF EXISTS "account_approvers";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_approval_threshold_check";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "approval_threshold";
@@ -0,0 +1,60 @@
+ALTER TABLE "accounts" ADD COLUMN "approval_threshold" bigint;
+
+CREATE TABLE "account_approvers" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE TABLE "transfer_requests" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "initiated_by" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending_approval',
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transfer_request_decisions" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_request_id" bigint NOT NULL,
+  "decided_by" varchar NOT NULL,
+  "decision" varchar NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_approvers" ("username");
+
+CREATE INDEX ON "transfer_requests" ("from_account_id", "status");
+
+CREATE INDEX ON "transfer_request_decisions" ("transfer_request_id");
+
+COMMENT ON COLUMN "accounts"."approval_threshold" IS 'transfers above it need a second user''s approval, NULL means never';
+
+COMMENT ON COLUMN "transfer_requests"."status" IS 'pending_approval, approved or rejected';
+
+COMMENT ON COLUMN "transfer_requests"."transfer_id" IS 'the transfer made once the request was approved';
+
+COMMENT ON COLUMN "transfer_request_decisions"."decision" IS 'approved or rejected';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_approval_threshold_check" CHECK ("approval_threshold" >= 0);
+
+ALTER TABLE "account_approvers" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_approvers" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_request_decisions" ADD FOREIGN KEY ("transfer_request_id") REFERENCES "transfer_requests" ("id");
+
+ALTER TABLE "transfer_request_decisions" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
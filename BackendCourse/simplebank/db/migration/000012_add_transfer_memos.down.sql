@@ -0,0 +1,17 @@
+ALTER TABLE IF EXISTS "transfer_requests" DROP COLUMN IF EXISTS "category";
+
+ALTER TABLE IF EXISTS "transfer_requests" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE IF EXISTS "transfer_requests" DROP COLUMN IF EXISTS "description";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "category";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "description";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "category";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "description";
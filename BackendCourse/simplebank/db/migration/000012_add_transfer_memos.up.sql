@@ -0,0 +1,27 @@
+ALTER TABLE "transfers" ADD COLUMN "description" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "category" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "description" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "category" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_requests" ADD COLUMN "description" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_requests" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_requests" ADD COLUMN "category" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "transfers" ("reference");
+
+CREATE INDEX ON "entries" ("account_id", "category");
+
+COMMENT ON COLUMN "transfers"."description" IS 'free text memo of what the payment was for';
+
+COMMENT ON COLUMN "transfers"."reference" IS 'external reference, e.g. an invoice number';
+
+COMMENT ON COLUMN "entries"."description" IS 'copied from the transfer of the entry';
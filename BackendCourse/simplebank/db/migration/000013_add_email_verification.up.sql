@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "is_email_verified" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "users"."is_email_verified" IS 'only a verified email can be used to address transfers to the user';
@@ -0,0 +1,21 @@
+CREATE TABLE "payees" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "cooling_off_until" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "payees" ("owner");
+
+COMMENT ON COLUMN "payees"."to_account_id" IS 'resolved when the payee is added, also when it was given by an alias';
+
+COMMENT ON COLUMN "payees"."cooling_off_until" IS 'large amounts can''t be paid to the payee before then';
+
+ALTER TABLE "payees" ADD CONSTRAINT "payees_owner_nickname_key" UNIQUE ("owner", "nickname");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
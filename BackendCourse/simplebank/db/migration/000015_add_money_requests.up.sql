@@ -0,0 +1,34 @@
+CREATE TABLE "money_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "payer" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "money_requests" ("payer", "status");
+
+CREATE INDEX ON "money_requests" ("requester", "status");
+
+CREATE INDEX ON "money_requests" ("status", "expires_at");
+
+COMMENT ON COLUMN "money_requests"."to_account_id" IS 'the requester''s account in the currency, paid when the request is accepted';
+
+COMMENT ON COLUMN "money_requests"."status" IS 'pending, accepted, declined or expired';
+
+ALTER TABLE "money_requests" ADD CONSTRAINT "money_requests_amount_check" CHECK ("amount" > 0);
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+DROP TABLE IF EXISTS "interest_postings";
+
+DROP TABLE IF EXISTS "interest_rates";
+
+-- expense accounts that were used are kept and only unlinked
+WITH "expense_accounts" AS (
+  DELETE FROM "system_accounts" WHERE "purpose" = 'interest_expense'
+  RETURNING "account_id"
+)
+DELETE FROM "accounts"
+WHERE "id" IN (SELECT "account_id" FROM "expense_accounts")
+  AND NOT EXISTS (SELECT 1 FROM "entries" WHERE "entries"."account_id" = "accounts"."id");
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_type_check";
+
+DROP INDEX IF EXISTS "accounts_owner_currency_type_key";
+
+-- fails while an owner has more than one account in a currency, e.g. a savings account
+-- or a kept expense account, until they are merged or removed by hand
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "accounts_owner_currency_key" UNIQUE ("owner", "currency");
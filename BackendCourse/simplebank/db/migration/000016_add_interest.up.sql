@@ -0,0 +1,79 @@
+-- an owner may now have a checking and a savings account in the same currency,
+-- and the bank has several system accounts per currency
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_owner_currency_key";
+
+CREATE UNIQUE INDEX "accounts_owner_currency_type_key" ON "accounts" ("owner", "currency", "type") WHERE "type" <> 'system';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_type_check" CHECK ("type" IN ('checking', 'savings', 'system'));
+
+CREATE TABLE "interest_rates" (
+  "id" bigserial PRIMARY KEY,
+  "account_type" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "annual_rate_bps" bigint NOT NULL,
+  "effective_from" date NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate_bps" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "posting_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_postings" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period" date NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "expense_entry_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "interest_accruals" ("posting_id");
+
+COMMENT ON COLUMN "interest_rates"."effective_from" IS 'the rate applies from this day until the next rate of the product takes effect';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'the end-of-day balance the interest was computed on';
+
+COMMENT ON COLUMN "interest_accruals"."posting_id" IS 'NULL until the interest is credited to the account';
+
+COMMENT ON COLUMN "interest_postings"."period" IS 'the first day of the month the interest was accrued in';
+
+COMMENT ON COLUMN "interest_postings"."expense_entry_id" IS 'the entry on the bank''s interest expense account';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("posting_id") REFERENCES "interest_postings" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("expense_entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "interest_rates" ADD CONSTRAINT "interest_rates_rate_check" CHECK ("annual_rate_bps" >= 0);
+
+-- a product has at most one rate taking effect on a given day
+ALTER TABLE "interest_rates" ADD CONSTRAINT "interest_rates_product_effective_from_key" UNIQUE ("account_type", "currency", "effective_from");
+
+-- at most one accrual per account per day, and one posting per account per month
+ALTER TABLE "interest_accruals" ADD CONSTRAINT "interest_accruals_account_date_key" UNIQUE ("account_id", "accrual_date");
+
+ALTER TABLE "interest_postings" ADD CONSTRAINT "interest_postings_account_period_key" UNIQUE ("account_id", "period");
+
+-- one interest expense account per supported currency
+WITH "expense_accounts" AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency", "type")
+  SELECT 'simplebank', 0, "currency", 'system'
+  FROM unnest(ARRAY['USD', 'EUR', 'CAD']) AS "currency"
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'interest_expense', "currency", "id" FROM "expense_accounts";
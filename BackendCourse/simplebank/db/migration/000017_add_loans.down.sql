@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS "loan_installments";
+
+DROP TABLE IF EXISTS "loans";
+
+-- loan accounts that were used are kept and only unlinked
+WITH "loan_accounts" AS (
+  DELETE FROM "system_accounts" WHERE "purpose" = 'loans'
+  RETURNING "account_id"
+)
+DELETE FROM "accounts"
+WHERE "id" IN (SELECT "account_id" FROM "loan_accounts")
+  AND NOT EXISTS (SELECT 1 FROM "entries" WHERE "entries"."account_id" = "accounts"."id");
@@ -0,0 +1,69 @@
+CREATE TABLE "loans" (
+  "id" bigserial PRIMARY KEY,
+  "borrower" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "principal" bigint NOT NULL,
+  "annual_rate_bps" bigint NOT NULL,
+  "term_months" int NOT NULL,
+  "outstanding_principal" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "disbursement_transfer_id" bigint NOT NULL,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "loan_installments" (
+  "id" bigserial PRIMARY KEY,
+  "loan_id" bigint NOT NULL,
+  "number" int NOT NULL,
+  "due_date" date NOT NULL,
+  "principal" bigint NOT NULL,
+  "interest" bigint NOT NULL,
+  "late_fee" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'due',
+  "transfer_id" bigint,
+  "paid_at" timestamptz
+);
+
+CREATE INDEX ON "loans" ("borrower");
+
+CREATE INDEX ON "loan_installments" ("status", "due_date");
+
+COMMENT ON COLUMN "loans"."account_id" IS 'the borrower''s account the loan is paid into and repaid from';
+
+COMMENT ON COLUMN "loans"."status" IS 'active or repaid';
+
+COMMENT ON COLUMN "loans"."created_by" IS 'the banker who granted the loan';
+
+COMMENT ON COLUMN "loan_installments"."late_fee" IS 'added once the installment is overdue, collected with it';
+
+COMMENT ON COLUMN "loan_installments"."status" IS 'due or paid';
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("borrower") REFERENCES "users" ("username");
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("disbursement_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "loan_installments" ADD FOREIGN KEY ("loan_id") REFERENCES "loans" ("id");
+
+ALTER TABLE "loan_installments" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "loans" ADD CONSTRAINT "loans_amounts_check" CHECK (
+  "principal" > 0 AND "annual_rate_bps" >= 0 AND "term_months" > 0 AND "outstanding_principal" >= 0
+);
+
+ALTER TABLE "loan_installments" ADD CONSTRAINT "loan_installments_loan_number_key" UNIQUE ("loan_id", "number");
+
+-- one account per supported currency that loans are paid from and repaid to
+WITH "loan_accounts" AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency", "type")
+  SELECT 'simplebank', 0, "currency", 'system'
+  FROM unnest(ARRAY['USD', 'EUR', 'CAD']) AS "currency"
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'loans', "currency", "id" FROM "loan_accounts";
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS "accounts_parent_name_key";
+
+DROP INDEX IF EXISTS "accounts_owner_currency_type_key";
+
+CREATE UNIQUE INDEX "accounts_owner_currency_type_key" ON "accounts" ("owner", "currency", "type") WHERE "type" <> 'system';
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_target_amount_check";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_pocket_parent_check";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_type_check";
+
+-- fails while pockets exist, their money has to be moved back to the parents and the pockets removed first
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "accounts_type_check" CHECK ("type" IN ('checking', 'savings', 'system'));
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "target_amount";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "name";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "parent_account_id";
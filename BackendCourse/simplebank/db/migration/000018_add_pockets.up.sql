@@ -0,0 +1,29 @@
+ALTER TABLE "accounts" ADD COLUMN "parent_account_id" bigint;
+
+ALTER TABLE "accounts" ADD COLUMN "name" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "target_amount" bigint;
+
+COMMENT ON COLUMN "accounts"."parent_account_id" IS 'the account a pocket belongs to, NULL for other accounts';
+
+COMMENT ON COLUMN "accounts"."target_amount" IS 'what the owner saves up to in a pocket, NULL means no target';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("parent_account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "accounts" ("parent_account_id");
+
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_type_check";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_type_check" CHECK ("type" IN ('checking', 'savings', 'pocket', 'system'));
+
+-- pockets, and only pockets, have a parent
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_pocket_parent_check" CHECK (("type" = 'pocket') = ("parent_account_id" IS NOT NULL));
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_target_amount_check" CHECK ("target_amount" > 0);
+
+-- an owner may have any number of pockets in a currency, named differently under each parent
+DROP INDEX "accounts_owner_currency_type_key";
+
+CREATE UNIQUE INDEX "accounts_owner_currency_type_key" ON "accounts" ("owner", "currency", "type") WHERE "type" IN ('checking', 'savings');
+
+CREATE UNIQUE INDEX "accounts_parent_name_key" ON "accounts" ("parent_account_id", "name") WHERE "parent_account_id" IS NOT NULL;
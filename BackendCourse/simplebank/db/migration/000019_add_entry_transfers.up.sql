@@ -0,0 +1,63 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer that moved the money, NULL for fees, interest and other bank charges';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+-- Entries of settled transfers are created in the same transaction as the transfer,
+-- so they share its created_at. That isn't unique within a transaction, e.g. an atomic batch
+-- with several legs of the same amount from the same account, so the entries and transfers
+-- sharing an account, amount and created_at are paired one-to-one in ID order.
+WITH "transfer_legs" AS (
+  SELECT "id", "from_account_id" AS "account_id", -"captured_amount" AS "amount", "created_at",
+    row_number() OVER (PARTITION BY "from_account_id", "captured_amount", "created_at" ORDER BY "id") AS "n"
+  FROM "transfers"
+  WHERE "status" = 'captured' AND "expires_at" IS NULL
+  UNION ALL
+  SELECT "id", "to_account_id", "captured_amount", "created_at",
+    row_number() OVER (PARTITION BY "to_account_id", "captured_amount", "created_at" ORDER BY "id")
+  FROM "transfers"
+  WHERE "status" = 'captured' AND "expires_at" IS NULL
+), "entry_legs" AS (
+  SELECT "id", "account_id", "amount", "created_at",
+    row_number() OVER (PARTITION BY "account_id", "amount", "created_at" ORDER BY "id") AS "n"
+  FROM "entries"
+)
+UPDATE "entries"
+SET "transfer_id" = "transfer_legs"."id"
+FROM "entry_legs"
+JOIN "transfer_legs" USING ("account_id", "amount", "created_at", "n")
+WHERE "entries"."id" = "entry_legs"."id";
+
+-- Entries of captured holds are created when the hold is captured, not with the transfer,
+-- but both in the capture's transaction, so they are paired with each other by their created_at.
+-- The pairs are then matched one-to-one in ID order with the captured holds between the same
+-- accounts for the same amount.
+WITH "hold_legs" AS (
+  SELECT "id", "from_account_id", "to_account_id", "captured_amount",
+    row_number() OVER (PARTITION BY "from_account_id", "to_account_id", "captured_amount" ORDER BY "id") AS "n"
+  FROM "transfers"
+  WHERE "status" = 'captured' AND "expires_at" IS NOT NULL
+), "capture_entries" AS (
+  SELECT "from_entries"."id" AS "from_entry_id", "to_entries"."id" AS "to_entry_id",
+    "from_entries"."account_id" AS "from_account_id", "to_entries"."account_id" AS "to_account_id",
+    "to_entries"."amount" AS "captured_amount",
+    row_number() OVER (
+      PARTITION BY "from_entries"."account_id", "to_entries"."account_id", "to_entries"."amount"
+      ORDER BY "from_entries"."id"
+    ) AS "n"
+  FROM "entries" AS "from_entries"
+  JOIN "entries" AS "to_entries"
+    ON "to_entries"."created_at" = "from_entries"."created_at"
+    AND "to_entries"."amount" = -"from_entries"."amount"
+  WHERE "from_entries"."transfer_id" IS NULL
+    AND "to_entries"."transfer_id" IS NULL
+    AND "from_entries"."amount" < 0
+)
+UPDATE "entries"
+SET "transfer_id" = "hold_legs"."id"
+FROM "hold_legs"
+JOIN "capture_entries" USING ("from_account_id", "to_account_id", "captured_amount", "n")
+WHERE "entries"."id" IN ("capture_entries"."from_entry_id", "capture_entries"."to_entry_id");
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS "postings_journal_balance" ON "postings";
+
+DROP FUNCTION IF EXISTS "check_journal_balance"();
+
+DROP TABLE IF EXISTS "postings";
+
+DROP TABLE IF EXISTS "journals";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "gl_account_code";
+
+DROP TABLE IF EXISTS "gl_accounts";
@@ -0,0 +1,114 @@
+-- the chart of accounts of the bank's own books
+CREATE TABLE "gl_accounts" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "gl_accounts_type_check" CHECK ("type" IN ('asset', 'liability', 'equity', 'income', 'expense'))
+);
+
+INSERT INTO "gl_accounts" ("code", "name", "type") VALUES
+  ('1000', 'Cash', 'asset'),
+  ('1100', 'Loans receivable', 'asset'),
+  ('2000', 'Customer deposits', 'liability'),
+  ('2900', 'Suspense', 'liability'),
+  ('4000', 'Fee income', 'income'),
+  ('4100', 'Interest income', 'income'),
+  ('5000', 'Interest expense', 'expense');
+
+ALTER TABLE "accounts" ADD COLUMN "gl_account_code" varchar NOT NULL DEFAULT '2000';
+
+COMMENT ON COLUMN "accounts"."gl_account_code" IS 'the GL account the account is a sub-ledger of';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("gl_account_code") REFERENCES "gl_accounts" ("code");
+
+UPDATE "accounts"
+SET "gl_account_code" = CASE "system_accounts"."purpose"
+  WHEN 'fees' THEN '4000'
+  WHEN 'interest_expense' THEN '5000'
+  WHEN 'loans' THEN '1100'
+END
+FROM "system_accounts"
+WHERE "system_accounts"."account_id" = "accounts"."id";
+
+CREATE TABLE "journals" (
+  "id" bigserial PRIMARY KEY,
+  "category" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "currency" varchar NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "postings" (
+  "id" bigserial PRIMARY KEY,
+  "journal_id" bigint NOT NULL,
+  "gl_account_code" varchar NOT NULL,
+  "account_id" bigint,
+  "entry_id" bigint,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "postings_amount_check" CHECK ("amount" <> 0)
+);
+
+CREATE INDEX ON "journals" ("transfer_id");
+
+CREATE INDEX ON "postings" ("journal_id");
+
+CREATE INDEX ON "postings" ("gl_account_code");
+
+CREATE INDEX ON "postings" ("account_id");
+
+COMMENT ON COLUMN "journals"."category" IS 'what moved the money, e.g. transfer, fee or deposit';
+
+COMMENT ON COLUMN "journals"."transfer_id" IS 'the transfer the journal belongs to, if any';
+
+COMMENT ON COLUMN "postings"."account_id" IS 'the bank account of the posting, NULL for postings straight to a GL account';
+
+COMMENT ON COLUMN "postings"."entry_id" IS 'the entry the posting records on the account';
+
+COMMENT ON COLUMN "postings"."amount" IS 'debits are positive, credits negative; the postings of a journal sum to zero';
+
+ALTER TABLE "journals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("journal_id") REFERENCES "journals" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("gl_account_code") REFERENCES "gl_accounts" ("code");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+-- the balances from before the ledger are carried over as one opening journal per currency,
+-- balanced against suspense until they are cleared
+INSERT INTO "journals" ("category", "description", "currency")
+SELECT DISTINCT 'opening_balance', 'Balances carried over to the general ledger', "currency"
+FROM "accounts"
+WHERE "balance" <> 0;
+
+INSERT INTO "postings" ("journal_id", "gl_account_code", "account_id", "amount")
+SELECT "journals"."id", "accounts"."gl_account_code", "accounts"."id", -"accounts"."balance"
+FROM "accounts"
+JOIN "journals" ON "journals"."category" = 'opening_balance' AND "journals"."currency" = "accounts"."currency"
+WHERE "accounts"."balance" <> 0;
+
+INSERT INTO "postings" ("journal_id", "gl_account_code", "amount")
+SELECT "journal_id", '2900', -SUM("amount")
+FROM "postings"
+GROUP BY "journal_id"
+HAVING SUM("amount") <> 0;
+
+-- checked at commit, once all the postings of the journal are in
+CREATE FUNCTION "check_journal_balance"() RETURNS trigger AS $$
+BEGIN
+  IF (SELECT SUM("amount") FROM "postings" WHERE "journal_id" = NEW."journal_id") <> 0 THEN
+    RAISE EXCEPTION 'journal % does not balance', NEW."journal_id";
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER "postings_journal_balance"
+AFTER INSERT OR UPDATE ON "postings"
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION "check_journal_balance"();
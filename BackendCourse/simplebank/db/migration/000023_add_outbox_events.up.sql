@@ -0,0 +1,21 @@
+CREATE TABLE "outbox_events" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "payload" json NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "claimed_until" timestamptz,
+  "published_at" timestamptz
+);
+
+CREATE INDEX ON "outbox_events" ("aggregate_type", "aggregate_id", "id") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON "outbox_events" ("next_attempt_at") WHERE "published_at" IS NULL;
+
+COMMENT ON COLUMN "outbox_events"."aggregate_id" IS 'the events of the same aggregate, e.g. of one account, are published in id order';
+
+COMMENT ON COLUMN "outbox_events"."claimed_until" IS 'set while a relay is publishing the event, so no other relay publishes it at the same time';
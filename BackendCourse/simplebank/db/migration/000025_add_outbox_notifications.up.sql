@@ -0,0 +1,12 @@
+CREATE FUNCTION "notify_outbox_event"() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('outbox_events', NEW.aggregate_type || ':' || NEW.aggregate_id);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION "notify_outbox_event"() IS 'tells the listeners which aggregate has a new event; Postgres sends it when the transaction commits';
+
+CREATE TRIGGER "outbox_events_notify"
+AFTER INSERT ON "outbox_events"
+FOR EACH ROW EXECUTE FUNCTION "notify_outbox_event"();
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "standing_order_executions" DROP COLUMN IF EXISTS "transfer_request_id";
+
+COMMENT ON COLUMN "standing_order_executions"."status" IS 'running, succeeded or failed';
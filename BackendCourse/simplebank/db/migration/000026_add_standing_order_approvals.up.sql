@@ -0,0 +1,7 @@
+ALTER TABLE "standing_order_executions" ADD COLUMN "transfer_request_id" bigint;
+
+COMMENT ON COLUMN "standing_order_executions"."status" IS 'running, succeeded, failed or pending_approval';
+
+COMMENT ON COLUMN "standing_order_executions"."transfer_request_id" IS 'the transfer request made instead of the transfer, when the amount was above the approval threshold';
+
+ALTER TABLE "standing_order_executions" ADD FOREIGN KEY ("transfer_request_id") REFERENCES "transfer_requests" ("id");
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS "payees" DROP COLUMN IF EXISTS "cooling_off_max_amount";
@@ -0,0 +1,3 @@
+ALTER TABLE "payees" ADD COLUMN "cooling_off_max_amount" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "payees"."cooling_off_max_amount" IS 'the largest amount that can be paid to the payee''s account before cooling_off_until, however it is addressed';
@@ -2,9 +2,11 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type,
+  available_balance
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $2
 ) RETURNING *;
 
 -- name: GetAccount :one
@@ -24,15 +26,23 @@ ORDER BY id
 LIMIT $1
 OFFSET $2;
 
+-- name: ListOwnerAccounts :many
+-- Lists all the accounts of the owner, pockets included.
+SELECT * FROM accounts
+WHERE owner = $1
+ORDER BY id;
+
 -- name: UpdateAccount :one
 UPDATE accounts
-SET balance = $2
+SET balance = $2,
+    available_balance = available_balance + ($2 - balance)
 WHERE id = $1
 RETURNING *;
 
 -- name: AddAccountBalance :one
 UPDATE accounts
-SET balance = balance + sqlc.arg(ammount)
+SET balance = balance + sqlc.arg(ammount),
+    available_balance = available_balance + sqlc.arg(ammount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
@@ -40,3 +50,84 @@ RETURNING *;
 DELETE FROM accounts
 WHERE id = $1;
 
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListOverdrawnAccounts :many
+SELECT * FROM accounts
+WHERE balance < 0
+ORDER BY id;
+
+-- name: AddAccountAvailableBalance :one
+-- Places (negative amount) or releases (positive amount) a hold without touching the ledger balance.
+UPDATE accounts
+SET available_balance = available_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountApprovalThreshold :one
+UPDATE accounts
+SET approval_threshold = sqlc.narg(approval_threshold)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetAccountByOwnerCurrency :one
+-- Returns the owner's checking account in the currency, which is the one paid when the owner is paid by an alias.
+-- An owner has at most one account of each type per currency, see the accounts_owner_currency_type_key index.
+SELECT * FROM accounts
+WHERE owner = $1 AND currency = $2 AND type = 'checking'
+LIMIT 1;
+
+-- name: ListInterestBearingAccounts :many
+-- Lists the accounts of the products that had an interest rate in effect on the day.
+SELECT * FROM accounts
+WHERE EXISTS (
+  SELECT 1 FROM interest_rates
+  WHERE interest_rates.account_type = accounts.type
+    AND interest_rates.currency = accounts.currency
+    AND interest_rates.effective_from <= sqlc.arg(on_date)::date
+)
+ORDER BY id;
+
+-- name: GetAccountBalanceAt :one
+-- Returns the balance the account had at the given time, by undoing the entries made since.
+SELECT (accounts.balance - COALESCE(SUM(entries.amount), 0))::bigint AS balance
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id AND entries.created_at >= sqlc.arg(at)
+WHERE accounts.id = sqlc.arg(id)
+GROUP BY accounts.id;
+
+-- name: CreatePocket :one
+INSERT INTO accounts (
+  owner,
+  balance,
+  available_balance,
+  currency,
+  type,
+  parent_account_id,
+  name,
+  target_amount
+) VALUES (
+  sqlc.arg(owner), 0, 0, sqlc.arg(currency), 'pocket', sqlc.arg(parent_account_id)::bigint, sqlc.arg(name), sqlc.narg(target_amount)
+) RETURNING *;
+
+-- name: ListPockets :many
+SELECT * FROM accounts
+WHERE parent_account_id = sqlc.arg(parent_account_id)::bigint
+ORDER BY id;
+
+-- name: UpdatePocket :one
+-- Fields left NULL are not changed.
+UPDATE accounts
+SET name = COALESCE(sqlc.narg(name), name),
+    target_amount = COALESCE(sqlc.narg(target_amount), target_amount)
+WHERE id = sqlc.arg(id) AND type = 'pocket'
+RETURNING *;
+
+-- name: GetPocketsBalance :one
+-- Returns the total balance of the pockets of the account.
+SELECT COALESCE(SUM(balance), 0)::bigint AS balance FROM accounts
+WHERE parent_account_id = sqlc.arg(parent_account_id)::bigint;
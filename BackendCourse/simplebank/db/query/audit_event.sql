@@ -0,0 +1,50 @@
+-- name: LockAuditChain :exec
+-- Serializes the appends to the audit chain until the transaction ends,
+-- so every event is chained to the one committed right before it.
+SELECT pg_advisory_xact_lock(hashtext('audit_events'));
+
+-- name: GetLastAuditEvent :one
+SELECT * FROM audit_events
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  actor,
+  action,
+  target_type,
+  target_id,
+  request_id,
+  client_ip,
+  before,
+  after,
+  prev_hash,
+  hash,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetAuditEvent :one
+SELECT * FROM audit_events
+WHERE id = $1 LIMIT 1;
+
+-- name: ListAuditEvents :many
+-- Lists the audit events matching the filters that are given, newest first.
+SELECT * FROM audit_events
+WHERE (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor)::varchar)
+  AND (sqlc.narg(action)::varchar IS NULL OR action = sqlc.narg(action)::varchar)
+  AND (sqlc.narg(target_type)::varchar IS NULL OR target_type = sqlc.narg(target_type)::varchar)
+  AND (sqlc.narg(target_id)::varchar IS NULL OR target_id = sqlc.narg(target_id)::varchar)
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time)::timestamptz)
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time)::timestamptz)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_rows)
+OFFSET sqlc.arg(skip_rows);
+
+-- name: ListAuditEventsAfter :many
+-- Lists the audit events in chain order, from the one after the given ID.
+SELECT * FROM audit_events
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(max_rows);
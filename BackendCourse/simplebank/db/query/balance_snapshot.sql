@@ -0,0 +1,33 @@
+-- name: CreateBalanceSnapshots :execrows
+-- Snapshots the end of day balance of every account that existed at the end of the day,
+-- by undoing the entries made since. Accounts already snapshotted for the day are skipped.
+INSERT INTO balance_snapshots (account_id, snapshot_date, balance)
+SELECT accounts.id, sqlc.arg(snapshot_date)::date, accounts.balance - COALESCE(SUM(entries.amount), 0)
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id AND entries.created_at >= sqlc.arg(day_end)
+WHERE accounts.created_at < sqlc.arg(day_end)
+GROUP BY accounts.id
+ON CONFLICT (account_id, snapshot_date) DO NOTHING;
+
+-- name: GetLatestBalanceSnapshot :one
+-- Returns the account's last snapshot of a day before the given date.
+SELECT * FROM balance_snapshots
+WHERE account_id = sqlc.arg(account_id) AND snapshot_date < sqlc.arg(before_date)::date
+ORDER BY snapshot_date DESC
+LIMIT 1;
+
+-- name: SumEntriesBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time);
+
+-- name: ListDailyEntryTotals :many
+-- Sums the account's entries per UTC day.
+SELECT (created_at AT TIME ZONE 'UTC')::date AS day, SUM(amount)::bigint AS total
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+GROUP BY day
+ORDER BY day;
@@ -1,9 +1,13 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  description,
+  reference,
+  category,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -28,4 +32,25 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: SearchEntries :many
+-- Lists the entries of the account, optionally only the ones of a category
+-- and the ones whose description or reference contain the search text, ignoring case.
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (sqlc.narg(category)::varchar IS NULL OR category = sqlc.narg(category)::varchar)
+  AND (sqlc.narg(search)::varchar IS NULL
+    OR description ILIKE '%' || sqlc.narg(search)::varchar || '%'
+    OR reference ILIKE '%' || sqlc.narg(search)::varchar || '%')
+ORDER BY id
+LIMIT sqlc.arg(max_rows)
+OFFSET sqlc.arg(skip_rows);
+
+-- name: ListEntriesBetween :many
+-- Lists the entries of the account made in the time range, oldest first.
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+ORDER BY created_at, id;
@@ -0,0 +1,35 @@
+-- name: CreateFeeRule :one
+INSERT INTO fee_rules (
+  currency,
+  account_type,
+  flat_fee,
+  percentage_bps,
+  min_fee,
+  max_fee
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetFeeRule :one
+SELECT * FROM fee_rules
+WHERE id = $1 LIMIT 1;
+
+-- name: ListFeeRules :many
+SELECT * FROM fee_rules
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: DeleteFeeRule :exec
+DELETE FROM fee_rules
+WHERE id = $1;
+
+-- name: GetApplicableFeeRule :one
+-- The most specific rule wins: a rule for the exact currency beats a rule for any currency,
+-- and then a rule for the exact account type beats a rule for any type.
+-- Among equally specific rules the newest one is used.
+SELECT * FROM fee_rules
+WHERE (currency = sqlc.arg(currency)::varchar OR currency IS NULL)
+  AND (account_type = sqlc.arg(account_type)::varchar OR account_type IS NULL)
+ORDER BY currency IS NULL, account_type IS NULL, id DESC
+LIMIT 1;
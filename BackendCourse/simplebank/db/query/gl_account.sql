@@ -0,0 +1,14 @@
+-- name: ListGLAccounts :many
+SELECT * FROM gl_accounts
+ORDER BY code;
+
+-- name: GetTrialBalance :many
+-- Sums the postings of every GL account per currency; debits are positive and credits negative,
+-- so the balances of a currency sum to zero.
+SELECT gl_accounts.code, gl_accounts.name, gl_accounts.type, journals.currency,
+  SUM(postings.amount)::bigint AS balance
+FROM postings
+JOIN journals ON journals.id = postings.journal_id
+JOIN gl_accounts ON gl_accounts.code = postings.gl_account_code
+GROUP BY gl_accounts.code, journals.currency
+ORDER BY journals.currency, gl_accounts.code;
@@ -0,0 +1,43 @@
+-- name: CreateInterestAccrual :one
+-- Returns no rows if the account already accrued interest for that day.
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  annual_rate_bps,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListUnpostedInterestAccounts :many
+-- Lists the accounts with interest accrued between the two days, from inclusive and to exclusive, that wasn't posted yet.
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posting_id IS NULL
+  AND accrual_date >= sqlc.arg(from_date)::date
+  AND accrual_date < sqlc.arg(to_date)::date
+ORDER BY account_id;
+
+-- name: SumUnpostedInterestAccruals :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND posting_id IS NULL
+  AND accrual_date >= sqlc.arg(from_date)::date
+  AND accrual_date < sqlc.arg(to_date)::date;
+
+-- name: MarkInterestAccrualsPosted :exec
+UPDATE interest_accruals
+SET posting_id = sqlc.arg(posting_id)
+WHERE account_id = sqlc.arg(account_id)
+  AND posting_id IS NULL
+  AND accrual_date >= sqlc.arg(from_date)::date
+  AND accrual_date < sqlc.arg(to_date)::date;
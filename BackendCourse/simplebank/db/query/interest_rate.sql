@@ -0,0 +1,24 @@
+-- name: CreateInterestRate :one
+INSERT INTO interest_rates (
+  account_type,
+  currency,
+  annual_rate_bps,
+  effective_from
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListInterestRates :many
+SELECT * FROM interest_rates
+ORDER BY account_type, currency, effective_from DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: GetInterestRate :one
+-- Returns the rate of the product in effect on the day, the latest one that took effect by then.
+SELECT * FROM interest_rates
+WHERE account_type = sqlc.arg(account_type)
+  AND currency = sqlc.arg(currency)
+  AND effective_from <= sqlc.arg(on_date)::date
+ORDER BY effective_from DESC
+LIMIT 1;
@@ -0,0 +1,50 @@
+-- name: CreateJournal :one
+INSERT INTO journals (
+  category,
+  description,
+  currency,
+  transfer_id
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetJournal :one
+SELECT * FROM journals
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTransferJournals :many
+-- Lists the journals of a transfer: the transfer itself, its fee and its captures.
+SELECT * FROM journals
+WHERE transfer_id = sqlc.arg(transfer_id)::bigint
+ORDER BY id;
+
+-- name: CreateEntryPosting :one
+-- Posts an entry to the GL account of its bank account. The entry's amount is what the bank owes
+-- the account, so crediting the account is a negative posting.
+INSERT INTO postings (
+  journal_id,
+  gl_account_code,
+  account_id,
+  entry_id,
+  amount
+)
+SELECT sqlc.arg(journal_id)::bigint, accounts.gl_account_code, accounts.id, entries.id, -entries.amount
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+WHERE entries.id = sqlc.arg(entry_id)::bigint
+RETURNING *;
+
+-- name: CreateGLPosting :one
+-- Posts straight to a GL account, without a bank account, e.g. to cash.
+INSERT INTO postings (
+  journal_id,
+  gl_account_code,
+  amount
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: ListJournalPostings :many
+SELECT * FROM postings
+WHERE journal_id = $1
+ORDER BY id;
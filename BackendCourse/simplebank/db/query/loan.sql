@@ -0,0 +1,38 @@
+-- name: CreateLoan :one
+INSERT INTO loans (
+  borrower,
+  account_id,
+  currency,
+  principal,
+  annual_rate_bps,
+  term_months,
+  outstanding_principal,
+  disbursement_transfer_id,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $4, $7, $8
+) RETURNING *;
+
+-- name: GetLoan :one
+SELECT * FROM loans
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLoanForUpdate :one
+SELECT * FROM loans
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListLoans :many
+SELECT * FROM loans
+WHERE borrower = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: RepayLoanPrincipal :one
+-- Marks the loan repaid once no principal is left.
+UPDATE loans
+SET outstanding_principal = outstanding_principal - sqlc.arg(amount),
+    status = CASE WHEN outstanding_principal - sqlc.arg(amount) = 0 THEN 'repaid' ELSE status END
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,45 @@
+-- name: CreateLoanInstallment :one
+INSERT INTO loan_installments (
+  loan_id,
+  number,
+  due_date,
+  principal,
+  interest
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListLoanInstallments :many
+SELECT * FROM loan_installments
+WHERE loan_id = $1
+ORDER BY number;
+
+-- name: GetLoanInstallmentForUpdate :one
+SELECT * FROM loan_installments
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListDueLoanInstallments :many
+-- Lists the unpaid installments due by the day in order, from the one after the given ID,
+-- so the ones that can't be collected don't keep being listed before the others.
+SELECT * FROM loan_installments
+WHERE status = 'due' AND due_date <= sqlc.arg(on_date)::date
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(max_rows);
+
+-- name: PayLoanInstallment :one
+-- Returns no rows if the installment was already paid.
+UPDATE loan_installments
+SET status = 'paid',
+    transfer_id = sqlc.arg(transfer_id),
+    paid_at = now()
+WHERE id = sqlc.arg(id) AND status = 'due'
+RETURNING *;
+
+-- name: ChargeLoanLateFees :many
+-- Adds the late fee to the unpaid installments that were due before the cutoff and weren't charged one yet.
+UPDATE loan_installments
+SET late_fee = sqlc.arg(late_fee)
+WHERE status = 'due' AND late_fee = 0 AND due_date < sqlc.arg(cutoff)::date
+RETURNING *;
@@ -0,0 +1,57 @@
+-- name: CreateMoneyRequest :one
+INSERT INTO money_requests (
+  requester,
+  payer,
+  to_account_id,
+  amount,
+  currency,
+  description,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetMoneyRequest :one
+SELECT * FROM money_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetMoneyRequestForUpdate :one
+SELECT * FROM money_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListIncomingMoneyRequests :many
+SELECT * FROM money_requests
+WHERE payer = $1 AND status = $2
+ORDER BY id DESC
+LIMIT $3
+OFFSET $4;
+
+-- name: ListOutgoingMoneyRequests :many
+SELECT * FROM money_requests
+WHERE requester = $1 AND status = $2
+ORDER BY id DESC
+LIMIT $3
+OFFSET $4;
+
+-- name: DecideMoneyRequest :one
+-- Only pending requests can be decided, so no rows means it was already decided or expired.
+UPDATE money_requests
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: ExpireMoneyRequests :many
+UPDATE money_requests
+SET status = 'expired',
+    decided_at = now()
+WHERE id IN (
+  SELECT id FROM money_requests
+  WHERE status = 'pending' AND expires_at <= sqlc.arg(now)::timestamptz
+  ORDER BY expires_at
+  LIMIT sqlc.arg(max_rows)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
@@ -0,0 +1,70 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+  event_type,
+  aggregate_type,
+  aggregate_id,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE id = $1 LIMIT 1;
+
+-- name: ClaimOutboxEvents :many
+-- Claims the oldest unpublished event of each aggregate that is due, until the claim expires.
+-- An event is only claimed once every earlier event of its aggregate is published,
+-- so the events of an aggregate are published one at a time and in order.
+UPDATE outbox_events
+SET claimed_until = sqlc.arg(claimed_until)::timestamptz
+WHERE id IN (
+  SELECT o.id FROM outbox_events o
+  WHERE o.published_at IS NULL
+    AND o.next_attempt_at <= sqlc.arg(now)
+    AND (o.claimed_until IS NULL OR o.claimed_until <= sqlc.arg(now))
+    AND NOT EXISTS (
+      SELECT 1 FROM outbox_events earlier
+      WHERE earlier.aggregate_type = o.aggregate_type
+        AND earlier.aggregate_id = o.aggregate_id
+        AND earlier.published_at IS NULL
+        AND earlier.id < o.id
+    )
+  ORDER BY o.id
+  LIMIT sqlc.arg(max_rows)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventPublished :one
+UPDATE outbox_events
+SET published_at = now(),
+  attempts = attempts + 1,
+  last_error = '',
+  claimed_until = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOutboxEventFailed :one
+UPDATE outbox_events
+SET attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  next_attempt_at = sqlc.arg(next_attempt_at),
+  claimed_until = NULL
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListAggregateOutboxEvents :many
+-- Lists the events of an aggregate in order, from the one after the given ID.
+SELECT * FROM outbox_events
+WHERE aggregate_type = sqlc.arg(aggregate_type)
+  AND aggregate_id = sqlc.arg(aggregate_id)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(max_rows);
+
+-- name: GetLastAggregateOutboxEventID :one
+-- Returns the ID of the last event of an aggregate, 0 if it has none.
+SELECT COALESCE(MAX(id), 0)::bigint AS last_id FROM outbox_events
+WHERE aggregate_type = sqlc.arg(aggregate_type)
+  AND aggregate_id = sqlc.arg(aggregate_id);
@@ -0,0 +1,15 @@
+-- name: CreateOverdraftCharge :one
+INSERT INTO overdraft_charges (
+  account_id,
+  entry_id,
+  charge_date,
+  balance,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetOverdraftCharge :one
+SELECT * FROM overdraft_charges
+WHERE account_id = $1 AND charge_date = $2
+LIMIT 1;
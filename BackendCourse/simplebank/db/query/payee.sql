@@ -0,0 +1,41 @@
+-- name: CreatePayee :one
+INSERT INTO payees (
+  owner,
+  nickname,
+  to_account_id,
+  currency,
+  cooling_off_until,
+  cooling_off_max_amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetPayee :one
+SELECT * FROM payees
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPayees :many
+SELECT * FROM payees
+WHERE owner = $1
+ORDER BY nickname
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdatePayeeNickname :one
+UPDATE payees
+SET nickname = sqlc.arg(nickname)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeletePayee :exec
+DELETE FROM payees
+WHERE id = $1;
+
+-- name: GetCoolingOffPayee :one
+SELECT * FROM payees
+WHERE owner = sqlc.arg(owner)
+  AND to_account_id = sqlc.arg(to_account_id)
+  AND cooling_off_until > now()
+  AND cooling_off_max_amount < sqlc.arg(amount)
+ORDER BY cooling_off_until DESC
+LIMIT 1;
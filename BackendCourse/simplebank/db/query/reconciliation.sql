@@ -0,0 +1,29 @@
+-- name: ListBalanceDrifts :many
+-- Lists the accounts whose balance differs from the sum of their entries.
+SELECT accounts.id, accounts.owner, accounts.currency, accounts.balance,
+  COALESCE(SUM(entries.amount), 0)::bigint AS entries_balance
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+GROUP BY accounts.id
+HAVING accounts.balance <> COALESCE(SUM(entries.amount), 0)
+ORDER BY accounts.id;
+
+-- name: ListTransferEntryMismatches :many
+-- Lists the transfers that don't have exactly one entry taking the captured amount
+-- from the from account and one giving it to the to account, netting to zero.
+-- Transfers that didn't move money, i.e. pending, voided or expired holds, must have no entries.
+SELECT transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.status, transfers.captured_amount,
+  COUNT(entries.id)::int AS entry_count,
+  COALESCE(SUM(entries.amount), 0)::bigint AS net_amount,
+  COUNT(entries.id) FILTER (WHERE
+    (entries.account_id = transfers.from_account_id AND entries.amount = -transfers.captured_amount)
+    OR (entries.account_id = transfers.to_account_id AND entries.amount = transfers.captured_amount))::int AS matching_entries
+FROM transfers
+LEFT JOIN entries ON entries.transfer_id = transfers.id
+GROUP BY transfers.id
+HAVING COUNT(entries.id) <> CASE WHEN transfers.status = 'captured' THEN 2 ELSE 0 END
+  OR COALESCE(SUM(entries.amount), 0) <> 0
+  OR COUNT(entries.id) FILTER (WHERE
+    (entries.account_id = transfers.from_account_id AND entries.amount = -transfers.captured_amount)
+    OR (entries.account_id = transfers.to_account_id AND entries.amount = transfers.captured_amount)) <> COUNT(entries.id)
+ORDER BY transfers.id;
@@ -0,0 +1,39 @@
+-- name: GetDepositTotals :many
+-- Sums the balances of the customers' accounts, pockets included, per currency.
+SELECT currency, COUNT(*) AS account_count, SUM(balance)::bigint AS total_balance
+FROM accounts
+WHERE type <> 'system'
+GROUP BY currency
+ORDER BY currency;
+
+-- name: GetDailyTransferVolume :many
+-- Counts the settled transfers and sums the amounts they moved per UTC day and currency.
+SELECT (transfers.created_at AT TIME ZONE 'UTC')::date AS day, accounts.currency,
+  COUNT(*) AS transfer_count, SUM(transfers.captured_amount)::bigint AS volume
+FROM transfers
+JOIN accounts ON accounts.id = transfers.from_account_id
+WHERE transfers.status = 'captured'
+  AND transfers.created_at >= sqlc.arg(from_time)
+  AND transfers.created_at < sqlc.arg(to_time)
+GROUP BY day, accounts.currency
+ORDER BY day, accounts.currency;
+
+-- name: ListTopAccountsByBalance :many
+SELECT id, owner, type, currency, balance FROM accounts
+WHERE type <> 'system' AND currency = sqlc.arg(currency)
+ORDER BY balance DESC, id
+LIMIT sqlc.arg(max_rows);
+
+-- name: GetNetFlows :many
+-- Sums the money that came into and left the customers' accounts per currency and category.
+SELECT accounts.currency, entries.category,
+  COALESCE(SUM(entries.amount) FILTER (WHERE entries.amount > 0), 0)::bigint AS inflow,
+  COALESCE(-SUM(entries.amount) FILTER (WHERE entries.amount < 0), 0)::bigint AS outflow,
+  SUM(entries.amount)::bigint AS net_flow
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+WHERE accounts.type <> 'system'
+  AND entries.created_at >= sqlc.arg(from_time)
+  AND entries.created_at < sqlc.arg(to_time)
+GROUP BY accounts.currency, entries.category
+ORDER BY accounts.currency, entries.category;
@@ -0,0 +1,54 @@
+-- name: CreateStandingOrder :one
+INSERT INTO standing_orders (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  currency,
+  frequency,
+  interval_count,
+  start_at,
+  end_at,
+  next_run_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $8
+) RETURNING *;
+
+-- name: GetStandingOrder :one
+SELECT * FROM standing_orders
+WHERE id = $1 LIMIT 1;
+
+-- name: ListStandingOrders :many
+SELECT * FROM standing_orders
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateStandingOrder :one
+UPDATE standing_orders
+SET amount = COALESCE(sqlc.narg(amount), amount),
+    end_at = COALESCE(sqlc.narg(end_at), end_at)
+WHERE id = sqlc.arg(id) AND status = 'active'
+RETURNING *;
+
+-- name: CancelStandingOrder :one
+UPDATE standing_orders
+SET status = 'cancelled'
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: ClaimDueStandingOrder :one
+-- Locks one due order, skipping those already claimed by another replica.
+SELECT * FROM standing_orders
+WHERE status = 'active' AND next_run_at <= sqlc.arg(now)::timestamptz
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: AdvanceStandingOrder :one
+UPDATE standing_orders
+SET next_run_at = sqlc.arg(next_run_at),
+    status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
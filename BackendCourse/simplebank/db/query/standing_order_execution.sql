@@ -0,0 +1,24 @@
+-- name: CreateStandingOrderExecution :one
+INSERT INTO standing_order_executions (
+  standing_order_id,
+  scheduled_at
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: FinishStandingOrderExecution :one
+UPDATE standing_order_executions
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    transfer_request_id = sqlc.narg(transfer_request_id),
+    error = sqlc.narg(error),
+    finished_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListStandingOrderExecutions :many
+SELECT * FROM standing_order_executions
+WHERE standing_order_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
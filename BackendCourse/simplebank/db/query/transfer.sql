@@ -2,9 +2,13 @@
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  captured_amount,
+  description,
+  reference,
+  category
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetTransfer :one
@@ -17,4 +21,98 @@ WHERE
     from_account_id = $1 OR to_account_id = $2
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+
+-- name: CreatePendingTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  status,
+  expires_at,
+  description,
+  reference,
+  category
+) VALUES (
+  sqlc.arg(from_account_id), sqlc.arg(to_account_id), sqlc.arg(amount), 'pending', sqlc.arg(expires_at)::timestamptz,
+  sqlc.arg(description), sqlc.arg(reference), sqlc.arg(category)
+) RETURNING *;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: CaptureTransfer :one
+UPDATE transfers
+SET status = 'captured',
+    captured_amount = sqlc.arg(captured_amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListExpiredPendingTransfers :many
+SELECT * FROM transfers
+WHERE status = 'pending' AND expires_at <= sqlc.arg(now)::timestamptz
+ORDER BY expires_at
+LIMIT sqlc.arg(max_rows);
+
+-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetOutgoingTransferTotals :one
+-- Sums what left the account since the given time: captured transfers count what was captured
+-- and pending holds what they hold. Reversals are corrections by the bank, not payments, so they don't count.
+-- Neither do payments to the bank's own accounts nor moves between the account and its pockets or parent,
+-- which aren't limited either.
+SELECT
+  COALESCE(SUM(CASE WHEN transfers.status = 'pending' THEN transfers.amount ELSE transfers.captured_amount END), 0)::bigint AS total,
+  count(*) AS count
+FROM transfers
+JOIN accounts AS from_account ON from_account.id = transfers.from_account_id
+JOIN accounts AS to_account ON to_account.id = transfers.to_account_id
+WHERE transfers.from_account_id = sqlc.arg(from_account_id)
+  AND transfers.created_at > sqlc.arg(since)::timestamptz
+  AND transfers.status IN ('pending', 'captured')
+  AND to_account.type <> 'system'
+  AND from_account.parent_account_id IS DISTINCT FROM to_account.id
+  AND to_account.parent_account_id IS DISTINCT FROM from_account.id
+  AND NOT EXISTS (SELECT 1 FROM transfer_reversals WHERE transfer_reversals.transfer_id = transfers.id);
+
+-- name: ListOutgoingTransferAmounts :many
+-- Lists what left the account since the given time, oldest first, counted like GetOutgoingTransferTotals.
+SELECT
+  transfers.created_at,
+  (CASE WHEN transfers.status = 'pending' THEN transfers.amount ELSE transfers.captured_amount END)::bigint AS amount
+FROM transfers
+JOIN accounts AS from_account ON from_account.id = transfers.from_account_id
+JOIN accounts AS to_account ON to_account.id = transfers.to_account_id
+WHERE transfers.from_account_id = sqlc.arg(from_account_id)
+  AND transfers.created_at > sqlc.arg(since)::timestamptz
+  AND transfers.status IN ('pending', 'captured')
+  AND to_account.type <> 'system'
+  AND from_account.parent_account_id IS DISTINCT FROM to_account.id
+  AND to_account.parent_account_id IS DISTINCT FROM from_account.id
+  AND NOT EXISTS (SELECT 1 FROM transfer_reversals WHERE transfer_reversals.transfer_id = transfers.id)
+ORDER BY transfers.created_at, transfers.id;
+
+-- name: SearchTransfers :many
+-- Lists the transfers into or out of the account, optionally only the ones of a category
+-- and the ones whose description or reference contain the search text, ignoring case.
+SELECT * FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND (sqlc.narg(category)::varchar IS NULL OR category = sqlc.narg(category)::varchar)
+  AND (sqlc.narg(search)::varchar IS NULL
+    OR description ILIKE '%' || sqlc.narg(search)::varchar || '%'
+    OR reference ILIKE '%' || sqlc.narg(search)::varchar || '%')
+ORDER BY id
+LIMIT sqlc.arg(max_rows)
+OFFSET sqlc.arg(skip_rows);
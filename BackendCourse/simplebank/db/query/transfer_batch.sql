@@ -0,0 +1,42 @@
+-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (
+  initiated_by,
+  from_account_id,
+  currency,
+  mode,
+  leg_count,
+  total_amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetTransferBatch :one
+SELECT * FROM transfer_batches
+WHERE id = $1 LIMIT 1;
+
+-- name: FinishTransferBatch :one
+UPDATE transfer_batches
+SET status = sqlc.arg(status),
+    succeeded_count = sqlc.arg(succeeded_count),
+    error = sqlc.narg(error),
+    finished_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateTransferBatchLeg :one
+INSERT INTO transfer_batch_legs (
+  batch_id,
+  leg_index,
+  to_account_id,
+  amount,
+  status,
+  transfer_id,
+  error
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListTransferBatchLegs :many
+SELECT * FROM transfer_batch_legs
+WHERE batch_id = $1
+ORDER BY leg_index;
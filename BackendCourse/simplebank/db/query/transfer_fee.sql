@@ -0,0 +1,17 @@
+-- name: CreateTransferFee :one
+INSERT INTO transfer_fees (
+  transfer_id,
+  fee_rule_id,
+  fee_account_id,
+  from_entry_id,
+  to_entry_id,
+  flat_fee,
+  percentage_fee,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetTransferFee :one
+SELECT * FROM transfer_fees
+WHERE transfer_id = $1 LIMIT 1;
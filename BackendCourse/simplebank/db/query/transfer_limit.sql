@@ -0,0 +1,34 @@
+-- name: CreateTransferLimit :one
+INSERT INTO transfer_limits (
+  tier,
+  currency,
+  account_id,
+  single_max,
+  daily_max,
+  monthly_max,
+  daily_count_max
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: DeleteTransferLimit :exec
+DELETE FROM transfer_limits
+WHERE id = $1;
+
+-- name: GetApplicableTransferLimit :one
+-- The limits of the account itself win over the limits of its owner's tier.
+SELECT * FROM transfer_limits
+WHERE account_id = sqlc.arg(account_id)::bigint
+   OR (tier = sqlc.arg(tier)::varchar AND currency = sqlc.arg(currency)::varchar)
+ORDER BY account_id IS NULL
+LIMIT 1;
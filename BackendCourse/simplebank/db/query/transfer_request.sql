@@ -0,0 +1,54 @@
+-- name: CreateTransferRequest :one
+INSERT INTO transfer_requests (
+  from_account_id,
+  to_account_id,
+  amount,
+  initiated_by,
+  description,
+  reference,
+  category
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetTransferRequest :one
+SELECT * FROM transfer_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferRequestForUpdate :one
+SELECT * FROM transfer_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListApprovableTransferRequests :many
+-- Lists the pending requests the approver can decide on, which excludes the ones they initiated.
+SELECT transfer_requests.* FROM transfer_requests
+JOIN account_approvers ON account_approvers.account_id = transfer_requests.from_account_id
+WHERE account_approvers.username = sqlc.arg(approver)
+  AND transfer_requests.initiated_by <> sqlc.arg(approver)
+  AND transfer_requests.status = 'pending_approval'
+ORDER BY transfer_requests.id
+LIMIT sqlc.arg(max_rows)
+OFFSET sqlc.arg(skip_rows);
+
+-- name: DecideTransferRequest :one
+UPDATE transfer_requests
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateTransferRequestDecision :one
+INSERT INTO transfer_request_decisions (
+  transfer_request_id,
+  decided_by,
+  decision,
+  reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTransferRequestDecisions :many
+SELECT * FROM transfer_request_decisions
+WHERE transfer_request_id = $1
+ORDER BY id;
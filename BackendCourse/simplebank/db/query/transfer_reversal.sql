@@ -0,0 +1,19 @@
+-- name: CreateTransferReversal :one
+INSERT INTO transfer_reversals (
+  transfer_id,
+  original_transfer_id,
+  amount,
+  initiated_by,
+  reason
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetTransferReversal :one
+SELECT * FROM transfer_reversals
+WHERE transfer_id = $1 LIMIT 1;
+
+-- name: ListTransferReversals :many
+SELECT * FROM transfer_reversals
+WHERE original_transfer_id = $1
+ORDER BY transfer_id;
@@ -11,4 +11,27 @@ INSERT INTO users (
 -- name: GetUser :one
 SELECT * FROM users
 WHERE username = $1 
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: UpdateUserTier :one
+UPDATE users
+SET tier = sqlc.arg(tier)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1
+LIMIT 1;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET is_email_verified = true
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1
+LIMIT 1
+FOR NO KEY UPDATE;
@@ -0,0 +1,108 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE owner = $1
+ORDER BY id;
+
+-- name: ListSubscribedWebhookEndpoints :many
+-- Lists the endpoints of the owner that the events of the type are delivered to.
+SELECT * FROM webhook_endpoints
+WHERE owner = sqlc.arg(owner)
+  AND sqlc.arg(event_type)::varchar = ANY(event_types)
+ORDER BY id;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: CreateWebhookDelivery :exec
+-- An event is delivered to an endpoint once, however many times it is published.
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+-- Lists the deliveries to an endpoint, newest first, of any status unless one is given.
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = sqlc.arg(endpoint_id)
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status)::varchar)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_rows)
+OFFSET sqlc.arg(skip_rows);
+
+-- name: ClaimWebhookDeliveries :many
+-- Claims pending deliveries that are due, until the claim expires.
+UPDATE webhook_deliveries
+SET claimed_until = sqlc.arg(claimed_until)::timestamptz
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending'
+    AND next_attempt_at <= sqlc.arg(now)
+    AND (claimed_until IS NULL OR claimed_until <= sqlc.arg(now))
+  ORDER BY next_attempt_at
+  LIMIT sqlc.arg(max_rows)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+SET status = sqlc.arg(status),
+  attempts = attempts + 1,
+  response_code = sqlc.arg(response_code),
+  last_error = sqlc.arg(last_error),
+  next_attempt_at = sqlc.arg(next_attempt_at),
+  delivered_at = sqlc.narg(delivered_at),
+  claimed_until = NULL
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetWebhookDeliveryForUpdate :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ReplayWebhookDelivery :one
+-- Only failed deliveries are replayed, so no rows means it is still pending or it succeeded.
+UPDATE webhook_deliveries
+SET status = 'pending',
+  attempts = 0,
+  next_attempt_at = now()
+WHERE id = $1 AND status = 'failed'
+RETURNING *;
+
+-- name: CreateWebhookDeliveryAttempt :one
+INSERT INTO webhook_delivery_attempts (
+  delivery_id,
+  response_code,
+  error
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY id;
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"strconv"
+)
+
+// CreateAccountTxParams contains the parameters for the CreateAccountTx function.
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateAccountTxResult contains the result of the CreateAccountTx function.
+type CreateAccountTxResult struct {
+	Account    Accounts    `json:"account"`
+	AuditEvent AuditEvents `json:"audit_event"`
+}
+
+// CreateAccountTx creates an account and records its audit event and account.created outbox event
+// in the same transaction.
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error) {
+	var result CreateAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Account, err = q.CreateAccount(ctx, arg.CreateAccountParams)
+		if err != nil {
+			return err
+		}
+
+		accountID := strconv.FormatInt(result.Account.ID, 10)
+		err = writeOutboxEvent(ctx, q, EventTypeAccountCreated, OutboxAggregateAccount, accountID, result.Account)
+		if err != nil {
+			return err
+		}
+
+		result.AuditEvent, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionAccountCreate,
+			TargetType: AuditTargetAccount,
+			TargetID:   accountID,
+			After:      result.Account,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateAccountOverdraftLimitTxParams contains the parameters for the UpdateAccountOverdraftLimitTx function.
+type UpdateAccountOverdraftLimitTxParams struct {
+	UpdateAccountOverdraftLimitParams
+	Audit AuditMeta `json:"-"`
+}
+
+// UpdateAccountOverdraftLimitTx sets an account's overdraft limit and records its audit event,
+// with the account before and after, in the same transaction.
+func (store *SQLStore) UpdateAccountOverdraftLimitTx(ctx context.Context, arg UpdateAccountOverdraftLimitTxParams) (Accounts, error) {
+	return store.updateAccountTx(ctx, arg.ID, arg.Audit, AuditActionAccountUpdateOverdraftLimit, func(q *Queries) (Accounts, error) {
+		return q.UpdateAccountOverdraftLimit(ctx, arg.UpdateAccountOverdraftLimitParams)
+	})
+}
+
+// UpdateAccountApprovalThresholdTxParams contains the parameters for the UpdateAccountApprovalThresholdTx function.
+type UpdateAccountApprovalThresholdTxParams struct {
+	UpdateAccountApprovalThresholdParams
+	Audit AuditMeta `json:"-"`
+}
+
+// UpdateAccountApprovalThresholdTx sets an account's approval threshold and records its audit event,
+// with the account before and after, in the same transaction.
+func (store *SQLStore) UpdateAccountApprovalThresholdTx(ctx context.Context, arg UpdateAccountApprovalThresholdTxParams) (Accounts, error) {
+	return store.updateAccountTx(ctx, arg.ID, arg.Audit, AuditActionAccountUpdateApprovalThreshold, func(q *Queries) (Accounts, error) {
+		return q.UpdateAccountApprovalThreshold(ctx, arg.UpdateAccountApprovalThresholdParams)
+	})
+}
+
+// updateAccountTx locks the account, applies the update to it and records the audit event of the change.
+// It returns sql.ErrNoRows if the account doesn't exist.
+func (store *SQLStore) updateAccountTx(ctx context.Context, accountID int64, audit AuditMeta, action string,
+	update func(q *Queries) (Accounts, error)) (Accounts, error) {
+	var account Accounts
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		account, err = update(q)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  audit,
+			Action:     action,
+			TargetType: AuditTargetAccount,
+			TargetID:   strconv.FormatInt(accountID, 10),
+			Before:     before,
+			After:      account,
+		})
+		return err
+	})
+
+	return account, err
+}
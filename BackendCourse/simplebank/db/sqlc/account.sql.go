@@ -7,13 +7,50 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const addAccountAvailableBalance = `-- name: AddAccountAvailableBalance :one
+UPDATE accounts
+SET available_balance = available_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
+`
+
+type AddAccountAvailableBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+// Places (negative amount) or releases (positive amount) a hold without touching the ledger balance.
+func (q *Queries) AddAccountAvailableBalance(ctx context.Context, arg AddAccountAvailableBalanceParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, addAccountAvailableBalance, arg.Amount, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
+	)
+	return i, err
+}
+
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
-SET balance = balance + $1
+SET balance = balance + $1,
+    available_balance = available_balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +67,14 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
 	)
 	return i, err
 }
@@ -38,20 +83,78 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type,
+  available_balance
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+  $1, $2, $3, $4, $2
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Type     string `json:"type"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Accounts, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Type,
+	)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
+	)
+	return i, err
+}
+
+const createPocket = `-- name: CreatePocket :one
+INSERT INTO accounts (
+  owner,
+  balance,
+  available_balance,
+  currency,
+  type,
+  parent_account_id,
+  name,
+  target_amount
+) VALUES (
+  $1, 0, 0, $2, 'pocket', $3::bigint, $4, $5
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
+`
+
+type CreatePocketParams struct {
+	Owner           string        `json:"owner"`
+	Currency        string        `json:"currency"`
+	ParentAccountID int64         `json:"parent_account_id"`
+	Name            string        `json:"name"`
+	TargetAmount    sql.NullInt64 `json:"target_amount"`
+}
+
+func (q *Queries) CreatePocket(ctx context.Context, arg CreatePocketParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, createPocket,
+		arg.Owner,
+		arg.Currency,
+		arg.ParentAccountID,
+		arg.Name,
+		arg.TargetAmount,
+	)
 	var i Accounts
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +162,14 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
 	)
 	return i, err
 }
@@ -74,7 +185,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
 WHERE id = $1 
 LIMIT 1
 `
@@ -88,12 +199,75 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Accounts, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
+	)
+	return i, err
+}
+
+const getAccountBalanceAt = `-- name: GetAccountBalanceAt :one
+SELECT (accounts.balance - COALESCE(SUM(entries.amount), 0))::bigint AS balance
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id AND entries.created_at >= $1
+WHERE accounts.id = $2
+GROUP BY accounts.id
+`
+
+type GetAccountBalanceAtParams struct {
+	At time.Time `json:"at"`
+	ID int64     `json:"id"`
+}
+
+// Returns the balance the account had at the given time, by undoing the entries made since.
+func (q *Queries) GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAccountBalanceAt, arg.At, arg.ID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const getAccountByOwnerCurrency = `-- name: GetAccountByOwnerCurrency :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
+WHERE owner = $1 AND currency = $2 AND type = 'checking'
+LIMIT 1
+`
+
+type GetAccountByOwnerCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+// Returns the owner's checking account in the currency, which is the one paid when the owner is paid by an alias.
+// An owner has at most one account of each type per currency, see the accounts_owner_currency_type_key index.
+func (q *Queries) GetAccountByOwnerCurrency(ctx context.Context, arg GetAccountByOwnerCurrencyParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByOwnerCurrency, arg.Owner, arg.Currency)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
 WHERE id = $1 
 LIMIT 1
 FOR NO KEY UPDATE
@@ -108,13 +282,34 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Accounts,
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
 	)
 	return i, err
 }
 
+const getPocketsBalance = `-- name: GetPocketsBalance :one
+SELECT COALESCE(SUM(balance), 0)::bigint AS balance FROM accounts
+WHERE parent_account_id = $1::bigint
+`
+
+// Returns the total balance of the pockets of the account.
+func (q *Queries) GetPocketsBalance(ctx context.Context, parentAccountID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getPocketsBalance, parentAccountID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const listAccounts = `-- name: ListAccounts :many
 
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -141,6 +336,193 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Type,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.ParentAccountID,
+			&i.Name,
+			&i.TargetAmount,
+			&i.GlAccountCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestBearingAccounts = `-- name: ListInterestBearingAccounts :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
+WHERE EXISTS (
+  SELECT 1 FROM interest_rates
+  WHERE interest_rates.account_type = accounts.type
+    AND interest_rates.currency = accounts.currency
+    AND interest_rates.effective_from <= $1::date
+)
+ORDER BY id
+`
+
+// Lists the accounts of the products that had an interest rate in effect on the day.
+func (q *Queries) ListInterestBearingAccounts(ctx context.Context, onDate time.Time) ([]Accounts, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestBearingAccounts, onDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Accounts{}
+	for rows.Next() {
+		var i Accounts
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Type,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.ParentAccountID,
+			&i.Name,
+			&i.TargetAmount,
+			&i.GlAccountCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverdrawnAccounts = `-- name: ListOverdrawnAccounts :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
+WHERE balance < 0
+ORDER BY id
+`
+
+func (q *Queries) ListOverdrawnAccounts(ctx context.Context) ([]Accounts, error) {
+	rows, err := q.db.QueryContext(ctx, listOverdrawnAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Accounts{}
+	for rows.Next() {
+		var i Accounts
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Type,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.ParentAccountID,
+			&i.Name,
+			&i.TargetAmount,
+			&i.GlAccountCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerAccounts = `-- name: ListOwnerAccounts :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
+WHERE owner = $1
+ORDER BY id
+`
+
+// Lists all the accounts of the owner, pockets included.
+func (q *Queries) ListOwnerAccounts(ctx context.Context, owner string) ([]Accounts, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerAccounts, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Accounts{}
+	for rows.Next() {
+		var i Accounts
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Type,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.ParentAccountID,
+			&i.Name,
+			&i.TargetAmount,
+			&i.GlAccountCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPockets = `-- name: ListPockets :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code FROM accounts
+WHERE parent_account_id = $1::bigint
+ORDER BY id
+`
+
+func (q *Queries) ListPockets(ctx context.Context, parentAccountID int64) ([]Accounts, error) {
+	rows, err := q.db.QueryContext(ctx, listPockets, parentAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Accounts{}
+	for rows.Next() {
+		var i Accounts
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Type,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.ParentAccountID,
+			&i.Name,
+			&i.TargetAmount,
+			&i.GlAccountCode,
 		); err != nil {
 			return nil, err
 		}
@@ -157,9 +539,10 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
-SET balance = $2
+SET balance = $2,
+    available_balance = available_balance + ($2 - balance)
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
 `
 
 type UpdateAccountParams struct {
@@ -176,6 +559,116 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
+	)
+	return i, err
+}
+
+const updateAccountApprovalThreshold = `-- name: UpdateAccountApprovalThreshold :one
+UPDATE accounts
+SET approval_threshold = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
+`
+
+type UpdateAccountApprovalThresholdParams struct {
+	ApprovalThreshold sql.NullInt64 `json:"approval_threshold"`
+	ID                int64         `json:"id"`
+}
+
+func (q *Queries) UpdateAccountApprovalThreshold(ctx context.Context, arg UpdateAccountApprovalThresholdParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountApprovalThreshold, arg.ApprovalThreshold, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
+	)
+	return i, err
+}
+
+const updatePocket = `-- name: UpdatePocket :one
+UPDATE accounts
+SET name = COALESCE($1, name),
+    target_amount = COALESCE($2, target_amount)
+WHERE id = $3 AND type = 'pocket'
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, type, available_balance, approval_threshold, parent_account_id, name, target_amount, gl_account_code
+`
+
+type UpdatePocketParams struct {
+	Name         sql.NullString `json:"name"`
+	TargetAmount sql.NullInt64  `json:"target_amount"`
+	ID           int64          `json:"id"`
+}
+
+// Fields left NULL are not changed.
+func (q *Queries) UpdatePocket(ctx context.Context, arg UpdatePocketParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, updatePocket, arg.Name, arg.TargetAmount, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Type,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.ParentAccountID,
+		&i.Name,
+		&i.TargetAmount,
+		&i.GlAccountCode,
 	)
 	return i, err
 }
@@ -18,6 +18,7 @@ func CreateRandomAccount(t *testing.T) Accounts {
 		Owner:    user.Username,
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
+		Type:     util.CheckingAccount,
 	}
 	account, err := testQueries.CreateAccount(context.Background(), arg)
 	require.NoError(t, err)
@@ -46,6 +47,16 @@ func TestGetAccount(t *testing.T) {
 	require.Equal(t, account1, account2)
 }
 
+func TestGetAccountByOwnerCurrency(t *testing.T) {
+	account1 := CreateRandomAccount(t)
+	account2, err := testQueries.GetAccountByOwnerCurrency(context.Background(), GetAccountByOwnerCurrencyParams{
+		Owner:    account1.Owner,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1, account2)
+}
+
 func TestUpdateAccount(t *testing.T) {
 	account1 := CreateRandomAccount(t)
 
@@ -96,3 +107,53 @@ func TestListAccount(t *testing.T) {
 		require.NotEmpty(t, account)
 	}
 }
+
+func updateOverdraftLimit(t *testing.T, account Accounts, limit int64) Accounts {
+	updated, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: limit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, limit, updated.OverdraftLimit)
+	return updated
+}
+
+func TestUpdateAccountOverdraftLimit(t *testing.T) {
+	account1 := CreateRandomAccount(t)
+	require.Zero(t, account1.OverdraftLimit)
+
+	limit := util.RandomMoney()
+	account2 := updateOverdraftLimit(t, account1, limit)
+
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, limit, account2.OverdraftLimit)
+
+	// a negative limit is rejected by the check constraint
+	_, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: -1,
+	})
+	require.Error(t, err)
+}
+
+func TestListOverdrawnAccounts(t *testing.T) {
+	account := CreateRandomAccount(t)
+	overdrawn, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: -util.RandomInt(1, 1000),
+	})
+	require.NoError(t, err)
+
+	accounts, err := testQueries.ListOverdrawnAccounts(context.Background())
+	require.NoError(t, err)
+
+	found := false
+	for _, a := range accounts {
+		require.Negative(t, a.Balance)
+		if a.ID == overdrawn.ID {
+			found = true
+		}
+	}
+	require.True(t, found)
+}
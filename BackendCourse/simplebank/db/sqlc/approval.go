@@ -0,0 +1,270 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+// Transfer request statuses. A request waits for approval and ends up approved, with its transfer, or rejected.
+const (
+	TransferRequestPendingApproval = "pending_approval"
+	TransferRequestApproved        = "approved"
+	TransferRequestRejected        = "rejected"
+)
+
+var (
+	// ErrTransferRequestNotPending is returned when deciding on a transfer request that was already approved or rejected.
+	ErrTransferRequestNotPending = errors.New("transfer request is not pending approval")
+	// ErrSelfApproval is returned when the initiator of a transfer request tries to decide on it.
+	ErrSelfApproval = errors.New("a transfer request can't be decided by its initiator")
+	// ErrNotApprover is returned when the user deciding on a transfer request isn't an approver of its from account.
+	ErrNotApprover = errors.New("user is not an approver of the account")
+)
+
+// NeedsApproval reports whether a transfer of amount from the account is above its approval threshold.
+func NeedsApproval(account Accounts, amount int64) bool {
+	return account.ApprovalThreshold.Valid && amount > account.ApprovalThreshold.Int64
+}
+
+// CreateTransferRequestTxParams contains the parameters for the CreateTransferRequestTx function.
+type CreateTransferRequestTxParams struct {
+	CreateTransferRequestParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateTransferRequestTx creates a transfer request waiting for approval and records its audit event
+// in the same transaction.
+func (store *SQLStore) CreateTransferRequestTx(ctx context.Context, arg CreateTransferRequestTxParams) (TransferRequests, error) {
+	var request TransferRequests
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		request, err = q.CreateTransferRequest(ctx, arg.CreateTransferRequestParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferRequestCreate,
+			TargetType: AuditTargetTransferRequest,
+			TargetID:   strconv.FormatInt(request.ID, 10),
+			After:      request,
+		})
+		return err
+	})
+
+	return request, err
+}
+
+// DecideTransferRequestTxParams contains the parameters for the ApproveTransferRequestTx and RejectTransferRequestTx functions.
+// Audit tells where the decision came from, for its audit event.
+type DecideTransferRequestTxParams struct {
+	RequestID int64     `json:"request_id"`
+	DecidedBy string    `json:"decided_by"`
+	Reason    string    `json:"reason"`
+	Audit     AuditMeta `json:"-"`
+}
+
+// DecideTransferRequestTxResult contains the result of the ApproveTransferRequestTx and RejectTransferRequestTx functions.
+// Transfer is only set when the request was approved.
+type DecideTransferRequestTxResult struct {
+	Request  TransferRequests         `json:"request"`
+	Decision TransferRequestDecisions `json:"decision"`
+	Transfer *TransferTxResult        `json:"transfer,omitempty"`
+}
+
+// ApproveTransferRequestTx approves a pending transfer request and makes its transfer, like TransferTx,
+// in the same transaction as the decision.
+// If the transfer fails, for example for lack of funds or a transfer limit, nothing is written and the
+// request stays pending, so it can be approved again later or rejected.
+// The decision's audit event, with the request before it, is recorded in the same transaction.
+func (store *SQLStore) ApproveTransferRequestTx(ctx context.Context, arg DecideTransferRequestTxParams) (DecideTransferRequestTxResult, error) {
+	var result DecideTransferRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := lockDecidableTransferRequest(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Decision, err = q.CreateTransferRequestDecision(ctx, CreateTransferRequestDecisionParams{
+			TransferRequestID: request.ID,
+			DecidedBy:         arg.DecidedBy,
+			Decision:          TransferRequestApproved,
+			Reason:            arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		transferResult, err := transfer(ctx, q, TransferTxParams{
+			FromAccountID: request.FromAccountID,
+			ToAccountID:   request.ToAccountID,
+			Amount:        request.Amount,
+			Description:   request.Description,
+			Reference:     request.Reference,
+			Category:      request.Category,
+		})
+		if err != nil {
+			return err
+		}
+		result.Transfer = &transferResult
+
+		result.Request, err = q.DecideTransferRequest(ctx, DecideTransferRequestParams{
+			ID:         request.ID,
+			Status:     TransferRequestApproved,
+			TransferID: sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return auditTransferRequestDecision(ctx, q, arg, AuditActionTransferRequestApprove, request, result)
+	})
+
+	return result, err
+}
+
+// RejectTransferRequestTx rejects a pending transfer request; no money is moved.
+// The decision's audit event, with the request before it, is recorded in the same transaction.
+func (store *SQLStore) RejectTransferRequestTx(ctx context.Context, arg DecideTransferRequestTxParams) (DecideTransferRequestTxResult, error) {
+	var result DecideTransferRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := lockDecidableTransferRequest(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Decision, err = q.CreateTransferRequestDecision(ctx, CreateTransferRequestDecisionParams{
+			TransferRequestID: request.ID,
+			DecidedBy:         arg.DecidedBy,
+			Decision:          TransferRequestRejected,
+			Reason:            arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Request, err = q.DecideTransferRequest(ctx, DecideTransferRequestParams{
+			ID:     request.ID,
+			Status: TransferRequestRejected,
+		})
+		if err != nil {
+			return err
+		}
+
+		return auditTransferRequestDecision(ctx, q, arg, AuditActionTransferRequestReject, request, result)
+	})
+
+	return result, err
+}
+
+// auditTransferRequestDecision records the audit event of a decision on a transfer request.
+func auditTransferRequestDecision(ctx context.Context, q *Queries, arg DecideTransferRequestTxParams,
+	action string, before TransferRequests, result DecideTransferRequestTxResult) error {
+	_, err := appendAuditEvent(ctx, q, AuditTxParams{
+		AuditMeta:  arg.Audit,
+		Action:     action,
+		TargetType: AuditTargetTransferRequest,
+		TargetID:   strconv.FormatInt(before.ID, 10),
+		Before:     before,
+		After:      result,
+	})
+	return err
+}
+
+// AccountApproverTxParams contains the parameters for the AddAccountApproverTx and RemoveAccountApproverTx functions.
+type AccountApproverTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Username  string    `json:"username"`
+	Audit     AuditMeta `json:"-"`
+}
+
+// AddAccountApproverTx designates a user as an approver of an account and records its audit event
+// in the same transaction.
+func (store *SQLStore) AddAccountApproverTx(ctx context.Context, arg AccountApproverTxParams) (AccountApprovers, error) {
+	var approver AccountApprovers
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		approver, err = q.CreateAccountApprover(ctx, CreateAccountApproverParams{
+			AccountID: arg.AccountID,
+			Username:  arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionAccountAddApprover,
+			TargetType: AuditTargetAccount,
+			TargetID:   strconv.FormatInt(arg.AccountID, 10),
+			After:      approver,
+		})
+		return err
+	})
+
+	return approver, err
+}
+
+// RemoveAccountApproverTx removes an approver of an account and records its audit event in the same transaction.
+// It returns sql.ErrNoRows if the user isn't an approver of the account.
+func (store *SQLStore) RemoveAccountApproverTx(ctx context.Context, arg AccountApproverTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		approver, err := q.GetAccountApprover(ctx, GetAccountApproverParams{
+			AccountID: arg.AccountID,
+			Username:  arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAccountApprover(ctx, DeleteAccountApproverParams{
+			AccountID: arg.AccountID,
+			Username:  arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionAccountRemoveApprover,
+			TargetType: AuditTargetAccount,
+			TargetID:   strconv.FormatInt(arg.AccountID, 10),
+			Before:     approver,
+		})
+		return err
+	})
+}
+
+// lockDecidableTransferRequest locks the transfer request and checks that it is pending
+// and that the user deciding on it is an approver of its from account other than its initiator.
+// The lock makes concurrent decisions on the same request wait for each other, so only one wins.
+func lockDecidableTransferRequest(ctx context.Context, q *Queries, arg DecideTransferRequestTxParams) (TransferRequests, error) {
+	request, err := q.GetTransferRequestForUpdate(ctx, arg.RequestID)
+	if err != nil {
+		return request, err
+	}
+
+	if request.Status != TransferRequestPendingApproval {
+		return request, ErrTransferRequestNotPending
+	}
+	if request.InitiatedBy == arg.DecidedBy {
+		return request, ErrSelfApproval
+	}
+
+	_, err = q.GetAccountApprover(ctx, GetAccountApproverParams{
+		AccountID: request.FromAccountID,
+		Username:  arg.DecidedBy,
+	})
+	if err == sql.ErrNoRows {
+		return request, ErrNotApprover
+	}
+	return request, err
+}
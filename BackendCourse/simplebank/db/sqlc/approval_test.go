@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createPendingTransferRequest creates a transfer request from account1 to account2, initiated by the owner of
+// account1, and designates a new user as an approver of account1. It returns the request and the approver.
+func createPendingTransferRequest(t *testing.T, account1, account2 Accounts, amount int64) (TransferRequests, Users) {
+	approver := CreateRandomUser(t)
+	_, err := testQueries.CreateAccountApprover(context.Background(), CreateAccountApproverParams{
+		AccountID: account1.ID,
+		Username:  approver.Username,
+	})
+	require.NoError(t, err)
+
+	request, err := testQueries.CreateTransferRequest(context.Background(), CreateTransferRequestParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		InitiatedBy:   account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestPendingApproval, request.Status)
+	require.False(t, request.TransferID.Valid)
+
+	return request, approver
+}
+
+func TestApproveTransferRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	request, approver := createPendingTransferRequest(t, account1, account2, 10)
+
+	// the initiator can't approve their own request, even as an approver
+	_, err := testQueries.CreateAccountApprover(context.Background(), CreateAccountApproverParams{
+		AccountID: account1.ID,
+		Username:  account1.Owner,
+	})
+	require.NoError(t, err)
+	_, err = store.ApproveTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrSelfApproval)
+
+	// nor can a user who isn't an approver of the account
+	outsider := CreateRandomUser(t)
+	_, err = store.ApproveTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: outsider.Username,
+	})
+	require.ErrorIs(t, err, ErrNotApprover)
+
+	result, err := store.ApproveTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: approver.Username,
+		Reason:    "checked with the payee",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferRequestApproved, result.Request.Status)
+	require.NotNil(t, result.Transfer)
+	require.True(t, result.Request.TransferID.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Request.TransferID.Int64)
+	require.Equal(t, account1.ID, result.Transfer.Transfer.FromAccountID)
+	require.Equal(t, account2.ID, result.Transfer.Transfer.ToAccountID)
+	require.Equal(t, int64(10), result.Transfer.Transfer.Amount)
+
+	require.Equal(t, request.ID, result.Decision.TransferRequestID)
+	require.Equal(t, approver.Username, result.Decision.DecidedBy)
+	require.Equal(t, TransferRequestApproved, result.Decision.Decision)
+	require.Equal(t, "checked with the payee", result.Decision.Reason)
+
+	// the decision can't be made twice
+	_, err = store.RejectTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: approver.Username,
+		Reason:    "changed my mind",
+	})
+	require.ErrorIs(t, err, ErrTransferRequestNotPending)
+
+	decisions, err := testQueries.ListTransferRequestDecisions(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	require.Equal(t, result.Decision, decisions[0])
+}
+
+func TestApproveTransferRequestTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	request, approver := createPendingTransferRequest(t, account1, account2, account1.Balance+1)
+
+	_, err := store.ApproveTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: approver.Username,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// the request stays pending, without a decision
+	request, err = testQueries.GetTransferRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestPendingApproval, request.Status)
+
+	decisions, err := testQueries.ListTransferRequestDecisions(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Empty(t, decisions)
+}
+
+func TestRejectTransferRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	request, approver := createPendingTransferRequest(t, account1, account2, 10)
+
+	result, err := store.RejectTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: approver.Username,
+		Reason:    "unknown payee",
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestRejected, result.Request.Status)
+	require.False(t, result.Request.TransferID.Valid)
+	require.Nil(t, result.Transfer)
+	require.Equal(t, TransferRequestRejected, result.Decision.Decision)
+
+	account1After, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account1After.Balance)
+
+	_, err = store.ApproveTransferRequestTx(context.Background(), DecideTransferRequestTxParams{
+		RequestID: request.ID,
+		DecidedBy: approver.Username,
+	})
+	require.ErrorIs(t, err, ErrTransferRequestNotPending)
+}
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Audit actions, named after the target type and what was done to it.
+const (
+	AuditActionUserCreate                     = "user.create"
+	AuditActionUserLogin                      = "user.login"
+	AuditActionUserLoginFailed                = "user.login_failed"
+	AuditActionUserUpdateTier                 = "user.update_tier"
+	AuditActionAccountCreate                  = "account.create"
+	AuditActionAccountDeposit                 = "account.deposit"
+	AuditActionAccountUpdateOverdraftLimit    = "account.update_overdraft_limit"
+	AuditActionAccountUpdateApprovalThreshold = "account.update_approval_threshold"
+	AuditActionAccountAddApprover             = "account.add_approver"
+	AuditActionAccountRemoveApprover          = "account.remove_approver"
+	AuditActionTransferCreate                 = "transfer.create"
+	AuditActionTransferAuthorize              = "transfer.authorize"
+	AuditActionTransferCapture                = "transfer.capture"
+	AuditActionTransferVoid                   = "transfer.void"
+	AuditActionTransferExpire                 = "transfer.expire"
+	AuditActionTransferReverse                = "transfer.reverse"
+	AuditActionTransferRequestCreate          = "transfer_request.create"
+	AuditActionTransferRequestApprove         = "transfer_request.approve"
+	AuditActionTransferRequestReject          = "transfer_request.reject"
+	AuditActionTransferBatchPay               = "transfer_batch.pay"
+	AuditActionTransferBatchPayLeg            = "transfer_batch.pay_leg"
+	AuditActionMoneyRequestAccept             = "money_request.accept"
+	AuditActionLoanCreate                     = "loan.create"
+	AuditActionLoanCollectInstallment         = "loan.collect_installment"
+	AuditActionLoanChargeLateFee              = "loan.charge_late_fee"
+	AuditActionStandingOrderCreate            = "standing_order.create"
+	AuditActionStandingOrderUpdate            = "standing_order.update"
+	AuditActionStandingOrderCancel            = "standing_order.cancel"
+	AuditActionTransferLimitCreate            = "transfer_limit.create"
+	AuditActionTransferLimitDelete            = "transfer_limit.delete"
+	AuditActionInterestRateCreate             = "interest_rate.create"
+	AuditActionPayeeCreate                    = "payee.create"
+	AuditActionPayeeUpdate                    = "payee.update"
+	AuditActionPayeeDelete                    = "payee.delete"
+	AuditActionWebhookEndpointCreate          = "webhook_endpoint.create"
+	AuditActionWebhookEndpointDelete          = "webhook_endpoint.delete"
+	AuditActionFeeRuleCreate                  = "fee_rule.create"
+	AuditActionFeeRuleDelete                  = "fee_rule.delete"
+)
+
+// Audit target types.
+const (
+	AuditTargetUser            = "user"
+	AuditTargetAccount         = "account"
+	AuditTargetTransfer        = "transfer"
+	AuditTargetTransferRequest = "transfer_request"
+	AuditTargetTransferBatch   = "transfer_batch"
+	AuditTargetMoneyRequest    = "money_request"
+	AuditTargetLoan            = "loan"
+	AuditTargetStandingOrder   = "standing_order"
+	AuditTargetTransferLimit   = "transfer_limit"
+	AuditTargetInterestRate    = "interest_rate"
+	AuditTargetPayee           = "payee"
+	AuditTargetWebhookEndpoint = "webhook_endpoint"
+	AuditTargetFeeRule         = "fee_rule"
+)
+
+// AuditActorSystem is the actor of the changes no user asked for, e.g. the ones made by background jobs.
+const AuditActorSystem = "system"
+
+// auditGenesisHash is the previous hash of the first audit event.
+var auditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// auditVerifyPageSize is the number of audit events VerifyAuditChain reads at a time.
+const auditVerifyPageSize = 1000
+
+// AuditMeta tells who made a change and where the request for it came from.
+// The actor is the username of the user, a change without one is recorded as made by the system.
+type AuditMeta struct {
+	Actor     string `json:"actor"`
+	RequestID string `json:"request_id"`
+	ClientIP  string `json:"client_ip"`
+}
+
+// AuditTxParams contains the parameters for the AuditTx function.
+// Before and After are snapshots of the target, marshaled to JSON; nil is recorded as null.
+type AuditTxParams struct {
+	AuditMeta
+	Action     string      `json:"action"`
+	TargetType string      `json:"target_type"`
+	TargetID   string      `json:"target_id"`
+	Before     interface{} `json:"before"`
+	After      interface{} `json:"after"`
+}
+
+// AuditTx records an audit event on its own, for operations that don't change anything else, e.g. logins.
+// Changes record their event through appendAuditEvent, in their own transaction.
+func (store *SQLStore) AuditTx(ctx context.Context, arg AuditTxParams) (AuditEvents, error) {
+	var event AuditEvents
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		event, err = appendAuditEvent(ctx, q, arg)
+		return err
+	})
+
+	return event, err
+}
+
+// appendAuditEvent appends an event to the audit chain: its hash covers the hash of the event before it,
+// so changing or removing any event breaks the chain from there on.
+// It takes the audit chain lock until the end of the transaction, so it should be the last step of the transaction.
+func appendAuditEvent(ctx context.Context, q *Queries, arg AuditTxParams) (AuditEvents, error) {
+	before, err := json.Marshal(arg.Before)
+	if err != nil {
+		return AuditEvents{}, err
+	}
+	after, err := json.Marshal(arg.After)
+	if err != nil {
+		return AuditEvents{}, err
+	}
+
+	actor := arg.Actor
+	if actor == "" {
+		actor = AuditActorSystem
+	}
+
+	if err := q.LockAuditChain(ctx); err != nil {
+		return AuditEvents{}, err
+	}
+
+	prevHash := auditGenesisHash
+	last, err := q.GetLastAuditEvent(ctx)
+	if err == nil {
+		prevHash = last.Hash
+	} else if err != sql.ErrNoRows {
+		return AuditEvents{}, err
+	}
+
+	event := CreateAuditEventParams{
+		Actor:      actor,
+		Action:     arg.Action,
+		TargetType: arg.TargetType,
+		TargetID:   arg.TargetID,
+		RequestID:  arg.RequestID,
+		ClientIp:   arg.ClientIP,
+		Before:     before,
+		After:      after,
+		PrevHash:   prevHash,
+		// the precision Postgres keeps, so the hash can be computed again from the row
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	event.Hash = auditEventHash(event)
+
+	return q.CreateAuditEvent(ctx, event)
+}
+
+// auditEventHash is the hex SHA-256 of the event's previous hash and fields, each length prefixed
+// so that no two different events hash the same text.
+func auditEventHash(event CreateAuditEventParams) string {
+	hash := sha256.New()
+	for _, field := range []string{
+		event.PrevHash,
+		event.Actor,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.RequestID,
+		event.ClientIp,
+		string(event.Before),
+		string(event.After),
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		hash.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// AuditChainReport is the result of checking the audit chain.
+// BrokenAt is the ID of the first event that doesn't chain to the one before it or whose hash doesn't match it.
+type AuditChainReport struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	EventCount    int64     `json:"event_count"`
+	Valid         bool      `json:"valid"`
+	BrokenAt      int64     `json:"broken_at,omitempty"`
+	LastEventHash string    `json:"last_event_hash"`
+}
+
+// VerifyAuditChain computes the hash of every audit event again, in chain order,
+// and checks that each event chains to the one before it.
+func (store *SQLStore) VerifyAuditChain(ctx context.Context) (AuditChainReport, error) {
+	report := AuditChainReport{
+		CheckedAt:     time.Now(),
+		Valid:         true,
+		LastEventHash: auditGenesisHash,
+	}
+
+	var afterID int64
+	for {
+		events, err := store.ListAuditEventsAfter(ctx, ListAuditEventsAfterParams{
+			AfterID: afterID,
+			MaxRows: auditVerifyPageSize,
+		})
+		if err != nil {
+			return report, err
+		}
+
+		for _, event := range events {
+			report.EventCount++
+			if event.PrevHash != report.LastEventHash || event.Hash != auditEventHash(CreateAuditEventParams{
+				Actor:      event.Actor,
+				Action:     event.Action,
+				TargetType: event.TargetType,
+				TargetID:   event.TargetID,
+				RequestID:  event.RequestID,
+				ClientIp:   event.ClientIp,
+				Before:     event.Before,
+				After:      event.After,
+				PrevHash:   event.PrevHash,
+				CreatedAt:  event.CreatedAt,
+			}) {
+				report.Valid = false
+				report.BrokenAt = event.ID
+				return report, nil
+			}
+			report.LastEventHash = event.Hash
+			afterID = event.ID
+		}
+
+		if len(events) < auditVerifyPageSize {
+			return report, nil
+		}
+	}
+}
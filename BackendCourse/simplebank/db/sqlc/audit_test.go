@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditChain(t *testing.T) {
+	store := NewStore(testDB)
+	meta := AuditMeta{Actor: util.RandomOwner(), RequestID: util.RandomString(12), ClientIP: "192.0.2.1"}
+
+	user := CreateRandomUser(t)
+	result, err := store.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Currency: util.RandomCurrency(),
+			Type:     util.CheckingAccount,
+		},
+		Audit: meta,
+	})
+	require.NoError(t, err)
+
+	created := result.AuditEvent
+	require.Equal(t, meta.Actor, created.Actor)
+	require.Equal(t, meta.RequestID, created.RequestID)
+	require.Equal(t, meta.ClientIP, created.ClientIp)
+	require.Equal(t, AuditActionAccountCreate, created.Action)
+	require.Equal(t, strconv.FormatInt(result.Account.ID, 10), created.TargetID)
+	require.JSONEq(t, "null", string(created.Before))
+	require.Contains(t, string(created.After), `"owner":"`+user.Username+`"`)
+
+	// the next event chains to it
+	login, err := store.AuditTx(context.Background(), AuditTxParams{
+		Action:     AuditActionUserLogin,
+		TargetType: AuditTargetUser,
+		TargetID:   user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AuditActorSystem, login.Actor)
+	require.Equal(t, created.Hash, login.PrevHash)
+	require.NotEqual(t, created.Hash, login.Hash)
+
+	// events can't be changed or removed
+	_, err = testDB.ExecContext(context.Background(), "UPDATE audit_events SET actor = 'mallory' WHERE id = $1", created.ID)
+	require.Error(t, err)
+	_, err = testDB.ExecContext(context.Background(), "DELETE FROM audit_events WHERE id = $1", created.ID)
+	require.Error(t, err)
+
+	report, err := store.VerifyAuditChain(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Valid)
+	require.GreaterOrEqual(t, report.EventCount, int64(2))
+	require.Equal(t, login.Hash, report.LastEventHash)
+}
+
+func TestTransferAuditEvent(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Audit:         AuditMeta{Actor: account1.Owner},
+	})
+	require.NoError(t, err)
+
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Actor:    sql.NullString{String: account1.Owner, Valid: true},
+		Action:   sql.NullString{String: AuditActionTransferCreate, Valid: true},
+		MaxRows:  5,
+		SkipRows: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Contains(t, string(events[0].Before), `"balance":`+strconv.FormatInt(account1.Balance, 10))
+	require.Contains(t, string(events[0].After), `"amount":10`)
+}
+
+func TestHoldAndSettingsAuditEvents(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	payer := AuditMeta{Actor: account1.Owner}
+	merchant := AuditMeta{Actor: account2.Owner}
+	banker := AuditMeta{Actor: util.RandomOwner()}
+
+	hold, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ExpiresAt:     time.Now().Add(time.Hour),
+		Audit:         payer,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		TransferID: hold.Transfer.ID,
+		Audit:      merchant,
+	})
+	require.NoError(t, err)
+
+	_, err = store.UpdateAccountOverdraftLimitTx(context.Background(), UpdateAccountOverdraftLimitTxParams{
+		UpdateAccountOverdraftLimitParams: UpdateAccountOverdraftLimitParams{ID: account1.ID, OverdraftLimit: 50},
+		Audit:                             banker,
+	})
+	require.NoError(t, err)
+
+	for _, want := range []struct {
+		meta     AuditMeta
+		action   string
+		targetID int64
+	}{
+		{payer, AuditActionTransferAuthorize, hold.Transfer.ID},
+		{merchant, AuditActionTransferCapture, hold.Transfer.ID},
+		{banker, AuditActionAccountUpdateOverdraftLimit, account1.ID},
+	} {
+		events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+			Actor:    sql.NullString{String: want.meta.Actor, Valid: true},
+			Action:   sql.NullString{String: want.action, Valid: true},
+			TargetID: sql.NullString{String: strconv.FormatInt(want.targetID, 10), Valid: true},
+			MaxRows:  5,
+			SkipRows: 0,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1, want.action)
+	}
+}
+
+func TestWebhookEndpointAuditEvents(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	meta := AuditMeta{Actor: user.Username}
+
+	endpoint, err := store.CreateWebhookEndpointTx(context.Background(), CreateWebhookEndpointTxParams{
+		CreateWebhookEndpointParams: CreateWebhookEndpointParams{
+			Owner:      user.Username,
+			Url:        "https://merchant.example/hooks",
+			Secret:     "whsec_" + util.RandomString(32),
+			EventTypes: []string{EventTypeTransferReceived},
+		},
+		Audit: meta,
+	})
+	require.NoError(t, err)
+
+	err = store.DeleteWebhookEndpointTx(context.Background(), DeleteWebhookEndpointTxParams{ID: endpoint.ID, Audit: meta})
+	require.NoError(t, err)
+
+	for _, action := range []string{AuditActionWebhookEndpointCreate, AuditActionWebhookEndpointDelete} {
+		events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+			Actor:    sql.NullString{String: user.Username, Valid: true},
+			Action:   sql.NullString{String: action, Valid: true},
+			TargetID: sql.NullString{String: strconv.FormatInt(endpoint.ID, 10), Valid: true},
+			MaxRows:  5,
+			SkipRows: 0,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1, action)
+
+		// the secret is never written to the audit log
+		require.NotContains(t, string(events[0].Before), endpoint.Secret)
+		require.NotContains(t, string(events[0].After), endpoint.Secret)
+	}
+
+	err = store.DeleteWebhookEndpointTx(context.Background(), DeleteWebhookEndpointTxParams{ID: endpoint.ID, Audit: meta})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestAuditEventHash(t *testing.T) {
+	event := CreateAuditEventParams{
+		Actor:      "alice",
+		Action:     AuditActionAccountCreate,
+		TargetType: AuditTargetAccount,
+		TargetID:   "1",
+		Before:     []byte("null"),
+		After:      []byte(`{"id":1}`),
+		PrevHash:   auditGenesisHash,
+		CreatedAt:  time.Now(),
+	}
+	hash := auditEventHash(event)
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, auditEventHash(event))
+
+	// moving text from one field to the next changes the hash
+	moved := event
+	moved.Actor, moved.Action = "alic", "e"+event.Action
+	require.NotEqual(t, hash, auditEventHash(moved))
+
+	tampered := event
+	tampered.After = []byte(`{"id":2}`)
+	require.NotEqual(t, hash, auditEventHash(tampered))
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// BalanceAt returns the balance the account had at the given time: its last end of day snapshot
+// before that time plus the entries made since. Without a snapshot, the entries made after
+// the time are undone from the current balance instead.
+func (store *SQLStore) BalanceAt(ctx context.Context, accountID int64, at time.Time) (int64, error) {
+	at = at.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	snapshot, err := store.GetLatestBalanceSnapshot(ctx, GetLatestBalanceSnapshotParams{
+		AccountID:  accountID,
+		BeforeDate: day,
+	})
+	if err == sql.ErrNoRows {
+		return store.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{ID: accountID, At: at})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	since, err := store.SumEntriesBetween(ctx, SumEntriesBetweenParams{
+		AccountID: accountID,
+		FromTime:  snapshot.SnapshotDate.AddDate(0, 0, 1),
+		ToTime:    at,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return snapshot.Balance + since, nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceAt(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+
+	_, err := store.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 100})
+	require.NoError(t, err)
+	afterFirst := time.Now()
+
+	_, err = store.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 50})
+	require.NoError(t, err)
+
+	// without a snapshot, the later entries are undone from the current balance
+	balance, err := store.BalanceAt(context.Background(), account.ID, afterFirst)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+100, balance)
+
+	// with a snapshot of yesterday, today's entries are added to it
+	now := time.Now().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+	_, err = testDB.ExecContext(context.Background(),
+		"INSERT INTO balance_snapshots (account_id, snapshot_date, balance) VALUES ($1, $2, $3)",
+		account.ID, yesterday, 1000)
+	require.NoError(t, err)
+
+	balance, err = store.BalanceAt(context.Background(), account.ID, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, int64(1000+150), balance)
+}
+
+func TestCreateBalanceSnapshots(t *testing.T) {
+	account := CreateRandomAccount(t)
+	now := time.Now().UTC()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	// the snapshot is taken once per account and day
+	_, err := testQueries.CreateBalanceSnapshots(context.Background(), CreateBalanceSnapshotsParams{
+		SnapshotDate: tomorrow.AddDate(0, 0, -1),
+		DayEnd:       tomorrow,
+	})
+	require.NoError(t, err)
+
+	snapshot, err := testQueries.GetLatestBalanceSnapshot(context.Background(), GetLatestBalanceSnapshotParams{
+		AccountID:  account.ID,
+		BeforeDate: tomorrow,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, snapshot.Balance)
+}
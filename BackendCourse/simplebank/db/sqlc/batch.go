@@ -0,0 +1,306 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// Transfer batch modes. An atomic batch pays every leg or none of them,
+// a best effort batch pays each leg on its own and records the ones that failed.
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+// Transfer batch statuses. A batch is processing until all its legs were tried.
+const (
+	BatchStatusProcessing = "processing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusPartial    = "partial"
+	BatchStatusFailed     = "failed"
+)
+
+// Transfer batch leg statuses.
+const (
+	BatchLegStatusSucceeded = "succeeded"
+	BatchLegStatusFailed    = "failed"
+)
+
+var (
+	// ErrBatchLegAccountNotFound is returned for a batch leg paying into an account that doesn't exist.
+	ErrBatchLegAccountNotFound = errors.New("to account not found")
+	// ErrBatchLegCurrencyMismatch is returned for a batch leg paying into an account in another currency than the batch.
+	ErrBatchLegCurrencyMismatch = errors.New("to account currency doesn't match the batch currency")
+	// ErrBatchLegPocket is returned for a batch leg paying into a pocket, which only its parent account pays into.
+	ErrBatchLegPocket = errors.New("pockets only move money to and from their parent account")
+	// ErrBatchLegSystemAccount is returned for a batch leg paying into one of the bank's own accounts.
+	ErrBatchLegSystemAccount = errors.New("batches can't pay into the bank's own accounts")
+)
+
+// BatchLeg is one payment of a transfer batch.
+type BatchLeg struct {
+	ToAccountID int64 `json:"to_account_id"`
+	Amount      int64 `json:"amount"`
+}
+
+// TransferBatchTxParams contains the parameters for the TransferBatchTx function.
+// Audit tells who paid the batch, for the audit events of its payments.
+type TransferBatchTxParams struct {
+	InitiatedBy   string     `json:"initiated_by"`
+	FromAccountID int64      `json:"from_account_id"`
+	Currency      string     `json:"currency"`
+	Mode          string     `json:"mode"`
+	Legs          []BatchLeg `json:"legs"`
+	Audit         AuditMeta  `json:"-"`
+}
+
+// TransferBatchTxResult contains the result of the TransferBatchTx function.
+type TransferBatchTxResult struct {
+	Batch TransferBatches     `json:"batch"`
+	Legs  []TransferBatchLegs `json:"legs"`
+}
+
+// TransferBatchTx pays many legs from one source account and records them as a batch.
+// Every leg is a regular transfer, with its entries and fee.
+// A leg that can't be paid, for example for lack of funds, doesn't make it return an error:
+// in atomic mode the whole batch is rolled back and recorded as failed with the reason,
+// in best effort mode the leg is recorded as failed and the other legs go on.
+// Errors are returned when the batch itself can't be recorded, and in atomic mode
+// also when the batch failed for another reason than one of its legs, e.g. a database error.
+// The payments are audited in the transactions that make them: once for the whole batch in atomic mode,
+// once per paid leg in best effort mode.
+func (store *SQLStore) TransferBatchTx(ctx context.Context, arg TransferBatchTxParams) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+
+	var total int64
+	for _, leg := range arg.Legs {
+		total += leg.Amount
+	}
+
+	batch, err := store.CreateTransferBatch(ctx, CreateTransferBatchParams{
+		InitiatedBy:   arg.InitiatedBy,
+		FromAccountID: arg.FromAccountID,
+		Currency:      arg.Currency,
+		Mode:          arg.Mode,
+		LegCount:      int32(len(arg.Legs)),
+		TotalAmount:   total,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if arg.Mode == BatchModeAtomic {
+		return store.atomicBatch(ctx, batch, arg)
+	}
+	return store.bestEffortBatch(ctx, batch, arg)
+}
+
+// atomicBatch pays all the legs of the batch in one transaction.
+// All the accounts are locked up front in ascending ID order, the same order TransferTx uses,
+// so the legs' transfers don't take any new account lock except the fee account's, which is always last.
+func (store *SQLStore) atomicBatch(ctx context.Context, batch TransferBatches, arg TransferBatchTxParams) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		ids := []int64{arg.FromAccountID}
+		for i, leg := range arg.Legs {
+			if err := checkBatchLeg(ctx, q, arg.Currency, leg); err != nil {
+				return fmt.Errorf("leg %d: %w", i, err)
+			}
+			ids = append(ids, leg.ToAccountID)
+		}
+
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for i, id := range ids {
+			if i > 0 && id == ids[i-1] {
+				continue
+			}
+			if _, err := q.GetAccountForUpdate(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		for i, leg := range arg.Legs {
+			transferResult, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   leg.ToAccountID,
+				Amount:        leg.Amount,
+			})
+			if err != nil {
+				return fmt.Errorf("leg %d: %w", i, err)
+			}
+
+			batchLeg, err := q.CreateTransferBatchLeg(ctx, CreateTransferBatchLegParams{
+				BatchID:     batch.ID,
+				LegIndex:    int32(i),
+				ToAccountID: leg.ToAccountID,
+				Amount:      leg.Amount,
+				Status:      BatchLegStatusSucceeded,
+				TransferID:  sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			result.Legs = append(result.Legs, batchLeg)
+		}
+
+		var err error
+		result.Batch, err = q.FinishTransferBatch(ctx, FinishTransferBatchParams{
+			ID:             batch.ID,
+			Status:         BatchStatusCompleted,
+			SucceededCount: int32(len(arg.Legs)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferBatchPay,
+			TargetType: AuditTargetTransferBatch,
+			TargetID:   strconv.FormatInt(batch.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+	if err == nil {
+		return result, nil
+	}
+
+	// nothing was paid, so there are no legs to show
+	result.Legs = []TransferBatchLegs{}
+	var finishErr error
+	result.Batch, finishErr = store.FinishTransferBatch(ctx, FinishTransferBatchParams{
+		ID:     batch.ID,
+		Status: BatchStatusFailed,
+		Error:  sql.NullString{String: err.Error(), Valid: true},
+	})
+	if finishErr != nil {
+		return result, finishErr
+	}
+	if !isBatchLegError(err) {
+		return result, err
+	}
+	return result, nil
+}
+
+// isBatchLegError reports whether err is a reason for a leg not to be paid, as opposed to a failure
+// of the batch itself.
+func isBatchLegError(err error) bool {
+	var limitErr *LimitExceededError
+	var coolingOffErr *PayeeCoolingOffError
+	return errors.Is(err, ErrInsufficientFunds) ||
+		errors.As(err, &limitErr) ||
+		errors.As(err, &coolingOffErr) ||
+		errors.Is(err, ErrBatchLegAccountNotFound) ||
+		errors.Is(err, ErrBatchLegCurrencyMismatch) ||
+		errors.Is(err, ErrBatchLegPocket) ||
+		errors.Is(err, ErrBatchLegSystemAccount)
+}
+
+// bestEffortBatch pays each leg of the batch in its own transaction, together with its leg record and audit event.
+func (store *SQLStore) bestEffortBatch(ctx context.Context, batch TransferBatches, arg TransferBatchTxParams) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+
+	succeeded := 0
+	for i, leg := range arg.Legs {
+		var batchLeg TransferBatchLegs
+		err := store.execTx(ctx, func(q *Queries) error {
+			if err := checkBatchLeg(ctx, q, arg.Currency, leg); err != nil {
+				return err
+			}
+
+			transferResult, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   leg.ToAccountID,
+				Amount:        leg.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			batchLeg, err = q.CreateTransferBatchLeg(ctx, CreateTransferBatchLegParams{
+				BatchID:     batch.ID,
+				LegIndex:    int32(i),
+				ToAccountID: leg.ToAccountID,
+				Amount:      leg.Amount,
+				Status:      BatchLegStatusSucceeded,
+				TransferID:  sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = appendAuditEvent(ctx, q, AuditTxParams{
+				AuditMeta:  arg.Audit,
+				Action:     AuditActionTransferBatchPayLeg,
+				TargetType: AuditTargetTransferBatch,
+				TargetID:   strconv.FormatInt(batch.ID, 10),
+				After:      batchLeg,
+			})
+			return err
+		})
+		if err == nil {
+			succeeded++
+		} else {
+			batchLeg, err = store.CreateTransferBatchLeg(ctx, CreateTransferBatchLegParams{
+				BatchID:     batch.ID,
+				LegIndex:    int32(i),
+				ToAccountID: leg.ToAccountID,
+				Amount:      leg.Amount,
+				Status:      BatchLegStatusFailed,
+				Error:       sql.NullString{String: err.Error(), Valid: true},
+			})
+			if err != nil {
+				return result, err
+			}
+		}
+		result.Legs = append(result.Legs, batchLeg)
+	}
+
+	status := BatchStatusPartial
+	switch succeeded {
+	case len(arg.Legs):
+		status = BatchStatusCompleted
+	case 0:
+		status = BatchStatusFailed
+	}
+
+	var err error
+	result.Batch, err = store.FinishTransferBatch(ctx, FinishTransferBatchParams{
+		ID:             batch.ID,
+		Status:         status,
+		SucceededCount: int32(succeeded),
+	})
+	return result, err
+}
+
+// checkBatchLeg returns an error if the leg's to account doesn't exist, is in another currency than the batch,
+// or is a pocket or one of the bank's own accounts, which regular transfers can't pay into either.
+func checkBatchLeg(ctx context.Context, q *Queries, currency string, leg BatchLeg) error {
+	account, err := q.GetAccount(ctx, leg.ToAccountID)
+	if err == sql.ErrNoRows {
+		return ErrBatchLegAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if account.Currency != currency {
+		return ErrBatchLegCurrencyMismatch
+	}
+
+	switch account.Type {
+	case util.PocketAccount:
+		return ErrBatchLegPocket
+	case util.SystemAccount:
+		return ErrBatchLegSystemAccount
+	}
+	return nil
+}
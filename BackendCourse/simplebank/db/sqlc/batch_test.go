@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createBatchAccounts(t *testing.T, n int) (Accounts, []Accounts) {
+	from := CreateRandomAccount(t)
+
+	// the legs must be in the batch currency, which is the from account's
+	to := make([]Accounts, n)
+	for i := range to {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    CreateRandomUser(t).Username,
+			Balance:  util.RandomMoney(),
+			Currency: from.Currency,
+			Type:     util.CheckingAccount,
+		})
+		require.NoError(t, err)
+		to[i] = account
+	}
+	return from, to
+}
+
+func TestTransferBatchTxAtomic(t *testing.T) {
+	store := NewStore(testDB)
+	from, to := createBatchAccounts(t, 3)
+
+	arg := TransferBatchTxParams{
+		InitiatedBy:   from.Owner,
+		FromAccountID: from.ID,
+		Currency:      from.Currency,
+		Mode:          BatchModeAtomic,
+	}
+	for _, account := range to {
+		arg.Legs = append(arg.Legs, BatchLeg{ToAccountID: account.ID, Amount: 1})
+	}
+
+	result, err := store.TransferBatchTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, BatchStatusCompleted, result.Batch.Status)
+	require.Equal(t, int32(3), result.Batch.SucceededCount)
+	require.Equal(t, int64(3), result.Batch.TotalAmount)
+	require.Len(t, result.Legs, 3)
+
+	for i, leg := range result.Legs {
+		require.Equal(t, int32(i), leg.LegIndex)
+		require.Equal(t, BatchLegStatusSucceeded, leg.Status)
+		require.True(t, leg.TransferID.Valid)
+
+		account, err := store.GetAccount(context.Background(), to[i].ID)
+		require.NoError(t, err)
+		require.Equal(t, to[i].Balance+1, account.Balance)
+	}
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-3, account.Balance)
+}
+
+func TestTransferBatchTxAtomicRollback(t *testing.T) {
+	store := NewStore(testDB)
+	from, to := createBatchAccounts(t, 2)
+
+	// the second leg overdraws the account, so nothing is paid
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		InitiatedBy:   from.Owner,
+		FromAccountID: from.ID,
+		Currency:      from.Currency,
+		Mode:          BatchModeAtomic,
+		Legs: []BatchLeg{
+			{ToAccountID: to[0].ID, Amount: 1},
+			{ToAccountID: to[1].ID, Amount: from.Balance},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, BatchStatusFailed, result.Batch.Status)
+	require.Zero(t, result.Batch.SucceededCount)
+	require.Contains(t, result.Batch.Error.String, ErrInsufficientFunds.Error())
+	require.Empty(t, result.Legs)
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, account.Balance)
+
+	legs, err := store.ListTransferBatchLegs(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Empty(t, legs)
+}
+
+func TestTransferBatchTxBestEffort(t *testing.T) {
+	store := NewStore(testDB)
+	from, to := createBatchAccounts(t, 2)
+
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		InitiatedBy:   from.Owner,
+		FromAccountID: from.ID,
+		Currency:      from.Currency,
+		Mode:          BatchModeBestEffort,
+		Legs: []BatchLeg{
+			{ToAccountID: to[0].ID, Amount: 1},
+			{ToAccountID: to[1].ID, Amount: from.Balance},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, BatchStatusPartial, result.Batch.Status)
+	require.Equal(t, int32(1), result.Batch.SucceededCount)
+	require.Len(t, result.Legs, 2)
+
+	require.Equal(t, BatchLegStatusSucceeded, result.Legs[0].Status)
+	require.Equal(t, BatchLegStatusFailed, result.Legs[1].Status)
+	require.False(t, result.Legs[1].TransferID.Valid)
+	require.Equal(t, ErrInsufficientFunds.Error(), result.Legs[1].Error.String)
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-1, account.Balance)
+
+	batch, err := store.GetTransferBatch(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Batch, batch)
+}
+
+func TestTransferBatchTxRejectsPocketAndSystemLegs(t *testing.T) {
+	store := NewStore(testDB)
+	from, to := createBatchAccounts(t, 1)
+	pocket := createRandomPocket(t, to[0])
+
+	feeAccount, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountFees,
+		Currency: from.Currency,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		InitiatedBy:   from.Owner,
+		FromAccountID: from.ID,
+		Currency:      from.Currency,
+		Mode:          BatchModeBestEffort,
+		Legs: []BatchLeg{
+			{ToAccountID: pocket.ID, Amount: 1},
+			{ToAccountID: feeAccount.AccountID, Amount: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, BatchStatusFailed, result.Batch.Status)
+	require.Len(t, result.Legs, 2)
+	require.Equal(t, ErrBatchLegPocket.Error(), result.Legs[0].Error.String)
+	require.Equal(t, ErrBatchLegSystemAccount.Error(), result.Legs[1].Error.String)
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, account.Balance)
+}
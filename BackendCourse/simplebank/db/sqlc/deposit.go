@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"strconv"
+)
+
+// EntryCategoryDeposit is the category of the entries of cash paid in at the bank.
+const EntryCategoryDeposit = "deposit"
+
+// DepositTxParams contains the parameters for the DepositTx function.
+// Audit tells who took the deposit, for its audit event.
+type DepositTxParams struct {
+	AccountID   int64     `json:"account_id"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description"`
+	Audit       AuditMeta `json:"-"`
+}
+
+// DepositTxResult contains the result of the DepositTx function.
+type DepositTxResult struct {
+	Account Accounts `json:"account"`
+	Entry   Entries  `json:"entry"`
+	Journal Journals `json:"journal"`
+}
+
+// DepositTx credits cash paid in at the bank to an account.
+// The account's entry is balanced in the general ledger by a debit to cash.
+// The entry's entry.created outbox event and the deposit's audit event are recorded in the same transaction.
+func (store *SQLStore) DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error) {
+	var result DepositTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:   arg.AccountID,
+			Amount:      arg.Amount,
+			Description: arg.Description,
+			Category:    EntryCategoryDeposit,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:      arg.AccountID,
+			Ammount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Journal, err = postJournal(ctx, q, CreateJournalParams{
+			Category:    JournalCategoryDeposit,
+			Description: arg.Description,
+			Currency:    result.Account.Currency,
+		}, []Entries{result.Entry}, GLPosting{GLAccountCode: GLAccountCash, Amount: arg.Amount})
+		if err != nil {
+			return err
+		}
+
+		err = writeEntryEvent(ctx, q, result.Entry, result.Account)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionAccountDeposit,
+			TargetType: AuditTargetAccount,
+			TargetID:   strconv.FormatInt(arg.AccountID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -7,36 +7,57 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  description,
+  reference,
+  category,
+  transfer_id
 ) VALUES (
-  $1, $2
-) RETURNING id, account_id, amount, created_at
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, account_id, amount, created_at, description, reference, category, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID   int64         `json:"account_id"`
+	Amount      int64         `json:"amount"`
+	Description string        `json:"description"`
+	Reference   string        `json:"reference"`
+	Category    string        `json:"category"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entries, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.Description,
+		arg.Reference,
+		arg.Category,
+		arg.TransferID,
+	)
 	var i Entries
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, description, reference, category, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,6 +69,10 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entries, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+		&i.TransferID,
 	)
 	return i, err
 }
@@ -55,7 +80,7 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entries, error) {
 const listEntries = `-- name: ListEntries :many
 
 
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, description, reference, category, transfer_id FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -92,6 +117,117 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Description,
+			&i.Reference,
+			&i.Category,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesBetween = `-- name: ListEntriesBetween :many
+SELECT id, account_id, amount, created_at, description, reference, category, transfer_id FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+ORDER BY created_at, id
+`
+
+type ListEntriesBetweenParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+// Lists the entries of the account made in the time range, oldest first.
+func (q *Queries) ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entries, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesBetween, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entries{}
+	for rows.Next() {
+		var i Entries
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Description,
+			&i.Reference,
+			&i.Category,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchEntries = `-- name: SearchEntries :many
+SELECT id, account_id, amount, created_at, description, reference, category, transfer_id FROM entries
+WHERE account_id = $1
+  AND ($2::varchar IS NULL OR category = $2::varchar)
+  AND ($3::varchar IS NULL
+    OR description ILIKE '%' || $3::varchar || '%'
+    OR reference ILIKE '%' || $3::varchar || '%')
+ORDER BY id
+LIMIT $4
+OFFSET $5
+`
+
+type SearchEntriesParams struct {
+	AccountID int64          `json:"account_id"`
+	Category  sql.NullString `json:"category"`
+	Search    sql.NullString `json:"search"`
+	MaxRows   int32          `json:"max_rows"`
+	SkipRows  int32          `json:"skip_rows"`
+}
+
+// Lists the entries of the account, optionally only the ones of a category
+// and the ones whose description or reference contain the search text, ignoring case.
+func (q *Queries) SearchEntries(ctx context.Context, arg SearchEntriesParams) ([]Entries, error) {
+	rows, err := q.db.QueryContext(ctx, searchEntries,
+		arg.AccountID,
+		arg.Category,
+		arg.Search,
+		arg.MaxRows,
+		arg.SkipRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entries{}
+	for rows.Next() {
+		var i Entries
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Description,
+			&i.Reference,
+			&i.Category,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
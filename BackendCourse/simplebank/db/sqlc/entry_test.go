@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
 
@@ -54,3 +55,26 @@ func TestListEntries(t *testing.T) {
 		require.NotEmpty(t, entry)
 	}
 }
+
+func TestListEntriesBetween(t *testing.T) {
+	account := CreateRandomAccount(t)
+	entry1 := CreateRandomEntry(t, account.ID)
+	entry2 := CreateRandomEntry(t, account.ID)
+
+	entries, err := testQueries.ListEntriesBetween(context.Background(), ListEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  entry1.CreatedAt,
+		ToTime:    entry2.CreatedAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Entries{entry1, entry2}, entries)
+
+	// the end of the range is excluded
+	entries, err = testQueries.ListEntriesBetween(context.Background(), ListEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  entry1.CreatedAt,
+		ToTime:    entry1.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+)
+
+// SystemAccountFees is the purpose of the bank-owned accounts that collect transfer fees, one per currency.
+const SystemAccountFees = "fees"
+
+// FeeBreakdown describes the fee charged on a transfer.
+// It is left empty when no fee rule applies or the fee comes to zero.
+type FeeBreakdown struct {
+	RuleID        int64    `json:"rule_id"`
+	FlatFee       int64    `json:"flat_fee"`
+	PercentageFee int64    `json:"percentage_fee"`
+	Amount        int64    `json:"amount"`
+	FeeAccount    Accounts `json:"fee_account"`
+	FromEntry     Entries  `json:"from_entry"`
+	ToEntry       Entries  `json:"to_entry"`
+}
+
+// ComputeFee returns the flat part, the percentage part and the total fee
+// for a transfer of amount under the given rule.
+// The total is the sum of both parts, raised to the rule's minimum and lowered to its maximum, if any.
+func ComputeFee(rule FeeRules, amount int64) (flat int64, percentage int64, total int64) {
+	flat = rule.FlatFee
+	// round half up to the nearest minor unit
+	percentage = (amount*rule.PercentageBps + 5000) / 10000
+
+	total = flat + percentage
+	if total < rule.MinFee {
+		total = rule.MinFee
+	}
+	if rule.MaxFee.Valid && total > rule.MaxFee.Int64 {
+		total = rule.MaxFee.Int64
+	}
+	return
+}
+
+// chargeTransferFee charges the fee for moving amount under the transfer, if any, from the from account into the bank's fee account
+// for its currency, as an extra pair of entries. It must run inside the transfer's transaction after the
+// from account was locked by its balance update. The fee account is always locked last, after both
+// transfer accounts, so concurrent transfers can't deadlock on it.
+// It returns the fee breakdown and the from account after the fee was taken.
+func chargeTransferFee(ctx context.Context, q *Queries, from Accounts, transferID int64, amount int64) (FeeBreakdown, Accounts, error) {
+	var fee FeeBreakdown
+
+	rule, err := q.GetApplicableFeeRule(ctx, GetApplicableFeeRuleParams{
+		Currency:    from.Currency,
+		AccountType: from.Type,
+	})
+	if err == sql.ErrNoRows {
+		return fee, from, nil
+	}
+	if err != nil {
+		return fee, from, err
+	}
+
+	flat, percentage, total := ComputeFee(rule, amount)
+	if total <= 0 {
+		return fee, from, nil
+	}
+
+	feeAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountFees,
+		Currency: from.Currency,
+	})
+	if err != nil {
+		return fee, from, err
+	}
+
+	fee.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: from.ID,
+		Amount:    -total,
+	})
+	if err != nil {
+		return fee, from, err
+	}
+
+	fee.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: feeAccount.AccountID,
+		Amount:    total,
+	})
+	if err != nil {
+		return fee, from, err
+	}
+
+	from, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:      from.ID,
+		Ammount: -total,
+	})
+	if err != nil {
+		return fee, from, err
+	}
+
+	fee.FeeAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:      feeAccount.AccountID,
+		Ammount: total,
+	})
+	if err != nil {
+		return fee, from, err
+	}
+
+	_, err = q.CreateTransferFee(ctx, CreateTransferFeeParams{
+		TransferID:    transferID,
+		FeeRuleID:     rule.ID,
+		FeeAccountID:  feeAccount.AccountID,
+		FromEntryID:   fee.FromEntry.ID,
+		ToEntryID:     fee.ToEntry.ID,
+		FlatFee:       flat,
+		PercentageFee: percentage,
+		Amount:        total,
+	})
+	if err != nil {
+		return fee, from, err
+	}
+
+	_, err = postJournal(ctx, q, CreateJournalParams{
+		Category:   JournalCategoryFee,
+		Currency:   from.Currency,
+		TransferID: sql.NullInt64{Int64: transferID, Valid: true},
+	}, []Entries{fee.FromEntry, fee.ToEntry})
+	if err != nil {
+		return fee, from, err
+	}
+
+	fee.RuleID = rule.ID
+	fee.FlatFee = flat
+	fee.PercentageFee = percentage
+	fee.Amount = total
+	return fee, from, nil
+}
+
+// CreateFeeRuleTxParams contains the parameters for the CreateFeeRuleTx function.
+type CreateFeeRuleTxParams struct {
+	CreateFeeRuleParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateFeeRuleTx creates a fee rule and records its audit event in the same transaction.
+func (store *SQLStore) CreateFeeRuleTx(ctx context.Context, arg CreateFeeRuleTxParams) (FeeRules, error) {
+	var rule FeeRules
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		rule, err = q.CreateFeeRule(ctx, arg.CreateFeeRuleParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionFeeRuleCreate,
+			TargetType: AuditTargetFeeRule,
+			TargetID:   strconv.FormatInt(rule.ID, 10),
+			After:      rule,
+		})
+		return err
+	})
+
+	return rule, err
+}
+
+// DeleteFeeRuleTxParams contains the parameters for the DeleteFeeRuleTx function.
+type DeleteFeeRuleTxParams struct {
+	ID    int64     `json:"id"`
+	Audit AuditMeta `json:"-"`
+}
+
+// DeleteFeeRuleTx deletes a fee rule and records its audit event, with the deleted rule, in the same transaction.
+// It returns sql.ErrNoRows if the rule doesn't exist.
+func (store *SQLStore) DeleteFeeRuleTx(ctx context.Context, arg DeleteFeeRuleTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		rule, err := q.GetFeeRule(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteFeeRule(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionFeeRuleDelete,
+			TargetType: AuditTargetFeeRule,
+			TargetID:   strconv.FormatInt(rule.ID, 10),
+			Before:     rule,
+		})
+		return err
+	})
+}
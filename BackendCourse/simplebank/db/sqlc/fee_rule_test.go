@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// CreateRandomFeeRule creates a fee rule for the currency and deletes it when the test ends,
+// so it doesn't apply to transfers made by other tests.
+func CreateRandomFeeRule(t *testing.T, currency string) FeeRules {
+	arg := CreateFeeRuleParams{
+		Currency:      sql.NullString{String: currency, Valid: true},
+		FlatFee:       util.RandomInt(1, 10),
+		PercentageBps: util.RandomInt(1, 100),
+	}
+	rule, err := testQueries.CreateFeeRule(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, rule)
+
+	require.Equal(t, arg.Currency, rule.Currency)
+	require.False(t, rule.AccountType.Valid)
+	require.Equal(t, arg.FlatFee, rule.FlatFee)
+	require.Equal(t, arg.PercentageBps, rule.PercentageBps)
+	require.Zero(t, rule.MinFee)
+	require.False(t, rule.MaxFee.Valid)
+	require.NotZero(t, rule.ID)
+	require.NotZero(t, rule.CreatedAt)
+
+	t.Cleanup(func() {
+		err := testQueries.DeleteFeeRule(context.Background(), rule.ID)
+		require.NoError(t, err)
+	})
+
+	return rule
+}
+
+func TestCreateFeeRule(t *testing.T) {
+	CreateRandomFeeRule(t, util.RandomCurrency())
+}
+
+func TestGetApplicableFeeRule(t *testing.T) {
+	currency := util.RandomCurrency()
+
+	anyCurrency, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{FlatFee: 1})
+	require.NoError(t, err)
+	defer testQueries.DeleteFeeRule(context.Background(), anyCurrency.ID)
+
+	arg := GetApplicableFeeRuleParams{
+		Currency:    currency,
+		AccountType: util.CheckingAccount,
+	}
+	rule, err := testQueries.GetApplicableFeeRule(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, anyCurrency.ID, rule.ID)
+
+	// a rule for the exact currency is more specific
+	exactCurrency := CreateRandomFeeRule(t, currency)
+	rule, err = testQueries.GetApplicableFeeRule(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, exactCurrency.ID, rule.ID)
+
+	// a rule for another account type doesn't apply
+	otherType, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency:    sql.NullString{String: currency, Valid: true},
+		AccountType: sql.NullString{String: util.SystemAccount, Valid: true},
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteFeeRule(context.Background(), otherType.ID)
+
+	rule, err = testQueries.GetApplicableFeeRule(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, exactCurrency.ID, rule.ID)
+}
+
+func TestComputeFee(t *testing.T) {
+	rule := FeeRules{
+		FlatFee:       10,
+		PercentageBps: 150, // 1.5%
+	}
+
+	flat, percentage, total := ComputeFee(rule, 1000)
+	require.Equal(t, int64(10), flat)
+	require.Equal(t, int64(15), percentage)
+	require.Equal(t, int64(25), total)
+
+	// raised to the minimum
+	rule.MinFee = 50
+	_, _, total = ComputeFee(rule, 1000)
+	require.Equal(t, int64(50), total)
+
+	// lowered to the maximum
+	rule.MaxFee = sql.NullInt64{Int64: 100, Valid: true}
+	flat, percentage, total = ComputeFee(rule, 100000)
+	require.Equal(t, int64(10), flat)
+	require.Equal(t, int64(1500), percentage)
+	require.Equal(t, int64(100), total)
+}
+
+func TestTransferTxWithFee(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	rule := CreateRandomFeeRule(t, account1.Currency)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	feeAccount, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountFees,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	feeAccountBefore, err := testQueries.GetAccount(context.Background(), feeAccount.AccountID)
+	require.NoError(t, err)
+
+	amount := int64(100)
+	flat, percentage, total := ComputeFee(rule, amount)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	fee := result.Fee
+	require.Equal(t, rule.ID, fee.RuleID)
+	require.Equal(t, flat, fee.FlatFee)
+	require.Equal(t, percentage, fee.PercentageFee)
+	require.Equal(t, total, fee.Amount)
+
+	require.Equal(t, account1.ID, fee.FromEntry.AccountID)
+	require.Equal(t, -total, fee.FromEntry.Amount)
+	require.Equal(t, feeAccount.AccountID, fee.ToEntry.AccountID)
+	require.Equal(t, total, fee.ToEntry.Amount)
+
+	// the amount reaches the to account in full, the fee is on top
+	require.Equal(t, account1.Balance-amount-total, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+amount, result.ToAccount.Balance)
+	require.Equal(t, feeAccount.AccountID, fee.FeeAccount.ID)
+	require.GreaterOrEqual(t, fee.FeeAccount.Balance, feeAccountBefore.Balance+total)
+
+	transferFee, err := testQueries.GetTransferFee(context.Background(), result.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, rule.ID, transferFee.FeeRuleID)
+	require.Equal(t, fee.FromEntry.ID, transferFee.FromEntryID)
+	require.Equal(t, fee.ToEntry.ID, transferFee.ToEntryID)
+	require.Equal(t, total, transferFee.Amount)
+}
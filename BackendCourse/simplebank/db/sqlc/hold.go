@@ -0,0 +1,296 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Transfer statuses. Instant transfers are created captured; holds start pending
+// and end up captured, voided or expired.
+const (
+	TransferStatusPending  = "pending"
+	TransferStatusCaptured = "captured"
+	TransferStatusVoided   = "voided"
+	TransferStatusExpired  = "expired"
+)
+
+var (
+	// ErrTransferNotPending is returned when capturing or releasing a transfer that is no longer a pending hold.
+	ErrTransferNotPending = errors.New("transfer is not pending")
+	// ErrHoldExpired is returned when capturing a hold after its expiry, even if the sweeper hasn't released it yet.
+	ErrHoldExpired = errors.New("hold has expired")
+	// ErrCaptureExceedsHold is returned when capturing more than the authorized amount.
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the authorized amount")
+)
+
+// AuthorizeTransferTxParams contains the parameters for the AuthorizeTransferTx function.
+// Audit tells who asked for the hold, for its audit event.
+type AuthorizeTransferTxParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Description   string    `json:"description"`
+	Reference     string    `json:"reference"`
+	Category      string    `json:"category"`
+	Audit         AuditMeta `json:"-"`
+}
+
+// AuthorizeTransferTxResult contains the result of the AuthorizeTransferTx function.
+type AuthorizeTransferTxResult struct {
+	Transfer    Transfers `json:"transfer"`
+	FromAccount Accounts  `json:"from_account"`
+}
+
+// AuthorizeTransferTx places a hold for a transfer: it creates a pending transfer and reduces
+// the from account's available balance by the amount, without touching its ledger balance.
+// No entries are written until the hold is captured.
+// It returns ErrInsufficientFunds if the available balance would go below the overdraft limit,
+// a *LimitExceededError if the held amount goes over the from account's transfer limits,
+// and a *PayeeCoolingOffError if it is above the max amount of a payee that is still cooling off.
+// Capturing the hold later doesn't count against the limits again.
+// The hold's audit event is recorded in the same transaction.
+func (store *SQLStore) AuthorizeTransferTx(ctx context.Context, arg AuthorizeTransferTxParams) (AuthorizeTransferTxResult, error) {
+	var result AuthorizeTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Transfer, err = q.CreatePendingTransfer(ctx, CreatePendingTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			ExpiresAt:     arg.ExpiresAt,
+			Description:   arg.Description,
+			Reference:     arg.Reference,
+			Category:      arg.Category,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromAccount, err = q.AddAccountAvailableBalance(ctx, AddAccountAvailableBalanceParams{
+			ID:     arg.FromAccountID,
+			Amount: -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := checkTransferLimits(ctx, q, result.FromAccount, arg.Amount); err != nil {
+			return err
+		}
+
+		if err := checkPayeeCoolingOff(ctx, q, result.FromAccount, arg.ToAccountID, arg.Amount); err != nil {
+			return err
+		}
+
+		if err := checkOverdraft(result.FromAccount); err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferAuthorize,
+			TargetType: AuditTargetTransfer,
+			TargetID:   strconv.FormatInt(result.Transfer.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CaptureTransferTxParams contains the parameters for the CaptureTransferTx function.
+// An Amount of 0 captures the full authorized amount.
+// Audit tells who captured the hold, for its audit event.
+type CaptureTransferTxParams struct {
+	TransferID int64     `json:"transfer_id"`
+	Amount     int64     `json:"amount"`
+	Audit      AuditMeta `json:"-"`
+}
+
+// CaptureTransferTx settles a pending hold, in full or in part.
+// The captured amount is moved like an instant transfer, with its entries and fee, and the rest
+// of the hold is released, so a hold can only be captured once.
+// The transfer row is locked before the accounts, so a capture can't race with a void or the expiry sweeper.
+// Its transfer.sent and transfer.received outbox events and the capture's audit event, with the hold
+// before it, are recorded in the same transaction.
+func (store *SQLStore) CaptureTransferTx(ctx context.Context, arg CaptureTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if transfer.Status != TransferStatusPending {
+			return ErrTransferNotPending
+		}
+		if transfer.ExpiresAt.Valid && !transfer.ExpiresAt.Time.After(time.Now()) {
+			return ErrHoldExpired
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = transfer.Amount
+		}
+		if amount > transfer.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		result.Transfer, err = q.CaptureTransfer(ctx, CaptureTransferParams{
+			ID:             transfer.ID,
+			CapturedAmount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:   transfer.FromAccountID,
+			Amount:      -amount,
+			Description: transfer.Description,
+			Reference:   transfer.Reference,
+			Category:    transfer.Category,
+			TransferID:  sql.NullInt64{Int64: transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:   transfer.ToAccountID,
+			Amount:      amount,
+			Description: transfer.Description,
+			Reference:   transfer.Reference,
+			Category:    transfer.Category,
+			TransferID:  sql.NullInt64{Int64: transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// same lock order as TransferTx
+		if transfer.FromAccountID < transfer.ToAccountID {
+			result.FromAccount, result.ToAccount, err = addMonney(ctx, q, transfer.FromAccountID, -amount, transfer.ToAccountID, amount)
+		} else {
+			result.ToAccount, result.FromAccount, err = addMonney(ctx, q, transfer.ToAccountID, amount, transfer.FromAccountID, -amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = postJournal(ctx, q, transferJournal(JournalCategoryTransfer, result.Transfer, result.FromAccount.Currency),
+			[]Entries{result.FromEntry, result.ToEntry})
+		if err != nil {
+			return err
+		}
+
+		// the captured amount has now left the available balance through the ledger, so the whole hold is released
+		result.FromAccount, err = q.AddAccountAvailableBalance(ctx, AddAccountAvailableBalanceParams{
+			ID:     transfer.FromAccountID,
+			Amount: transfer.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Fee, result.FromAccount, err = chargeTransferFee(ctx, q, result.FromAccount, transfer.ID, amount)
+		if err != nil {
+			return err
+		}
+
+		if err := checkOverdraft(result.FromAccount); err != nil {
+			return err
+		}
+
+		err = writeTransferEvents(ctx, q, result)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferCapture,
+			TargetType: AuditTargetTransfer,
+			TargetID:   strconv.FormatInt(transfer.ID, 10),
+			Before:     transfer,
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReleaseTransferTxParams contains the parameters for the ReleaseTransferTx function.
+// Status is the final status of the transfer: TransferStatusVoided or TransferStatusExpired.
+// Audit tells who voided the hold, for its audit event; expired holds are released by the system.
+type ReleaseTransferTxParams struct {
+	TransferID int64     `json:"transfer_id"`
+	Status     string    `json:"status"`
+	Audit      AuditMeta `json:"-"`
+}
+
+// ReleaseTransferTxResult contains the result of the ReleaseTransferTx function.
+type ReleaseTransferTxResult struct {
+	Transfer    Transfers `json:"transfer"`
+	FromAccount Accounts  `json:"from_account"`
+}
+
+// ReleaseTransferTx ends a pending hold without moving any money, giving the held amount
+// back to the from account's available balance. It is used both to void a hold and
+// to expire it once its expiry has passed.
+// The release's audit event, with the hold before it, is recorded in the same transaction.
+func (store *SQLStore) ReleaseTransferTx(ctx context.Context, arg ReleaseTransferTxParams) (ReleaseTransferTxResult, error) {
+	var result ReleaseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if transfer.Status != TransferStatusPending {
+			return ErrTransferNotPending
+		}
+
+		result.Transfer, err = q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+			ID:     transfer.ID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromAccount, err = q.AddAccountAvailableBalance(ctx, AddAccountAvailableBalanceParams{
+			ID:     transfer.FromAccountID,
+			Amount: transfer.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		action := AuditActionTransferVoid
+		if arg.Status == TransferStatusExpired {
+			action = AuditActionTransferExpire
+		}
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     action,
+			TargetType: AuditTargetTransfer,
+			TargetID:   strconv.FormatInt(transfer.ID, 10),
+			Before:     transfer,
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, store Store, from, to Accounts, amount int64) AuthorizeTransferTxResult {
+	result, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        amount,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferStatusPending, result.Transfer.Status)
+	require.Equal(t, amount, result.Transfer.Amount)
+	require.Zero(t, result.Transfer.CapturedAmount)
+	require.True(t, result.Transfer.ExpiresAt.Valid)
+
+	// the hold only reduces the available balance
+	require.Equal(t, from.ID, result.FromAccount.ID)
+	require.Equal(t, from.Balance, result.FromAccount.Balance)
+	require.Equal(t, from.AvailableBalance-amount, result.FromAccount.AvailableBalance)
+
+	return result
+}
+
+func TestAuthorizeAndCaptureTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	hold := createRandomHold(t, store, account1, account2, 100)
+
+	// capture only part of the hold, the rest is released
+	result, err := store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		TransferID: hold.Transfer.ID,
+		Amount:     60,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferStatusCaptured, result.Transfer.Status)
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.Equal(t, int64(60), result.Transfer.CapturedAmount)
+
+	require.Equal(t, -int64(60), result.FromEntry.Amount)
+	require.Equal(t, int64(60), result.ToEntry.Amount)
+
+	require.Equal(t, account1.Balance-60, result.FromAccount.Balance)
+	require.Equal(t, account1.AvailableBalance-60, result.FromAccount.AvailableBalance)
+	require.Equal(t, account2.Balance+60, result.ToAccount.Balance)
+	require.Equal(t, account2.AvailableBalance+60, result.ToAccount.AvailableBalance)
+
+	// a hold can only be captured once
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{TransferID: hold.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferNotPending)
+}
+
+func TestCaptureTransferTxExceedsHold(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	hold := createRandomHold(t, store, account1, account2, 100)
+
+	_, err := store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		TransferID: hold.Transfer.ID,
+		Amount:     101,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	// still pending
+	transfer, err := store.GetTransfer(context.Background(), hold.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusPending, transfer.Status)
+}
+
+func TestVoidTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	hold := createRandomHold(t, store, account1, account2, 100)
+
+	result, err := store.ReleaseTransferTx(context.Background(), ReleaseTransferTxParams{
+		TransferID: hold.Transfer.ID,
+		Status:     TransferStatusVoided,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusVoided, result.Transfer.Status)
+	require.Equal(t, account1.Balance, result.FromAccount.Balance)
+	require.Equal(t, account1.AvailableBalance, result.FromAccount.AvailableBalance)
+
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{TransferID: hold.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferNotPending)
+}
+
+func TestAuthorizeTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	_, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.AvailableBalance + 1,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestListExpiredPendingTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	expired, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	notExpired := createRandomHold(t, store, account1, account2, 10)
+
+	transfers, err := store.ListExpiredPendingTransfers(context.Background(), ListExpiredPendingTransfersParams{
+		Now:     time.Now(),
+		MaxRows: 1000,
+	})
+	require.NoError(t, err)
+
+	ids := make(map[int64]bool)
+	for _, transfer := range transfers {
+		require.Equal(t, TransferStatusPending, transfer.Status)
+		ids[transfer.ID] = true
+	}
+	require.True(t, ids[expired.Transfer.ID])
+	require.False(t, ids[notExpired.Transfer.ID])
+
+	// an expired hold can't be captured any more
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{TransferID: expired.Transfer.ID})
+	require.ErrorIs(t, err, ErrHoldExpired)
+}
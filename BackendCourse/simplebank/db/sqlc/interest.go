@@ -0,0 +1,277 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// SystemAccountInterestExpense is the purpose of the bank-owned accounts that pay the interest credited to accounts, one per currency.
+const SystemAccountInterestExpense = "interest_expense"
+
+// EntryCategoryInterest is the category of the entries crediting interest, and of their expense entries.
+const EntryCategoryInterest = "interest"
+
+// AccrueInterestTxParams contains the parameters for the AccrueInterestTx function.
+// AccrualDate is the UTC day whose end-of-day balance earns the interest.
+type AccrueInterestTxParams struct {
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+}
+
+// AccrueInterestTxResult contains the result of the AccrueInterestTx function.
+// Accrued is false when there was nothing to accrue: the product has no rate, the balance is not positive,
+// the interest rounds to zero, or it was already accrued for that day.
+type AccrueInterestTxResult struct {
+	Accrued bool             `json:"accrued"`
+	Accrual InterestAccruals `json:"accrual"`
+}
+
+// AccrueInterestTx records one day of interest earned by an account on its end-of-day balance,
+// at the rate of its product in effect on that day. Nothing is credited yet, see PostInterestTx.
+// interest_accruals allows at most one accrual per account per day, so running the job twice on the same day is harmless.
+func (store *SQLStore) AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (AccrueInterestTxResult, error) {
+	var result AccrueInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		rate, err := q.GetInterestRate(ctx, GetInterestRateParams{
+			AccountType: account.Type,
+			Currency:    account.Currency,
+			OnDate:      arg.AccrualDate,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		balance, err := q.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{
+			ID: arg.AccountID,
+			At: arg.AccrualDate.AddDate(0, 0, 1),
+		})
+		if err != nil {
+			return err
+		}
+
+		// overdrawn balances are charged overdraft interest instead
+		if balance <= 0 {
+			return nil
+		}
+
+		amount := util.DailyInterest(balance, rate.AnnualRateBps)
+		if amount <= 0 {
+			return nil
+		}
+
+		result.Accrual, err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:     arg.AccountID,
+			AccrualDate:   arg.AccrualDate,
+			Balance:       balance,
+			AnnualRateBps: rate.AnnualRateBps,
+			Amount:        amount,
+		})
+		if err == sql.ErrNoRows {
+			// already accrued for this day
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Accrued = true
+		return nil
+	})
+
+	return result, err
+}
+
+// PostInterestTxParams contains the parameters for the PostInterestTx function.
+// Period is the first day of the month whose accrued interest is posted.
+type PostInterestTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Period    time.Time `json:"period"`
+}
+
+// PostInterestTxResult contains the result of the PostInterestTx function.
+// Posted is false when there was nothing to post: no interest was accrued in the period,
+// or it was already posted.
+type PostInterestTxResult struct {
+	Posted         bool             `json:"posted"`
+	Account        Accounts         `json:"account"`
+	Entry          Entries          `json:"entry"`
+	ExpenseAccount Accounts         `json:"expense_account"`
+	ExpenseEntry   Entries          `json:"expense_entry"`
+	Posting        InterestPostings `json:"posting"`
+}
+
+// PostInterestTx credits the interest an account accrued during a month, as a pair of entries
+// moving it from the bank's interest expense account for the currency, and marks the accruals posted.
+// The entry.created outbox events of both entries are written in the same transaction.
+// interest_postings allows at most one posting per account per month, so posting a month twice is harmless.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// lock the account so concurrent runs of the job for the same account are serialized
+		result.Account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetInterestPosting(ctx, GetInterestPostingParams{
+			AccountID: arg.AccountID,
+			Period:    arg.Period,
+		})
+		if err == nil {
+			// already posted for this period
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		periodEnd := arg.Period.AddDate(0, 1, 0)
+		amount, err := q.SumUnpostedInterestAccruals(ctx, SumUnpostedInterestAccrualsParams{
+			AccountID: arg.AccountID,
+			FromDate:  arg.Period,
+			ToDate:    periodEnd,
+		})
+		if err != nil {
+			return err
+		}
+		if amount <= 0 {
+			return nil
+		}
+
+		expenseAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountInterestExpense,
+			Currency: result.Account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		description := "Interest for " + arg.Period.Format("January 2006")
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:   arg.AccountID,
+			Amount:      amount,
+			Description: description,
+			Category:    EntryCategoryInterest,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ExpenseEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:   expenseAccount.AccountID,
+			Amount:      -amount,
+			Description: description,
+			Category:    EntryCategoryInterest,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:      arg.AccountID,
+			Ammount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		// like the fee accounts, the expense account is always locked last
+		result.ExpenseAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:      expenseAccount.AccountID,
+			Ammount: -amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = postJournal(ctx, q, CreateJournalParams{
+			Category:    JournalCategoryInterest,
+			Description: description,
+			Currency:    result.Account.Currency,
+		}, []Entries{result.Entry, result.ExpenseEntry})
+		if err != nil {
+			return err
+		}
+
+		result.Posting, err = q.CreateInterestPosting(ctx, CreateInterestPostingParams{
+			AccountID:      arg.AccountID,
+			Period:         arg.Period,
+			EntryID:        result.Entry.ID,
+			ExpenseEntryID: result.ExpenseEntry.ID,
+			Amount:         amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.MarkInterestAccrualsPosted(ctx, MarkInterestAccrualsPostedParams{
+			PostingID: result.Posting.ID,
+			AccountID: arg.AccountID,
+			FromDate:  arg.Period,
+			ToDate:    periodEnd,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeEntryEvent(ctx, q, result.Entry, result.Account)
+		if err != nil {
+			return err
+		}
+		err = writeEntryEvent(ctx, q, result.ExpenseEntry, result.ExpenseAccount)
+		if err != nil {
+			return err
+		}
+
+		result.Posted = true
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateInterestRateTxParams contains the parameters for the CreateInterestRateTx function.
+type CreateInterestRateTxParams struct {
+	CreateInterestRateParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateInterestRateTx sets a new interest rate for a product and records its audit event in the same transaction.
+func (store *SQLStore) CreateInterestRateTx(ctx context.Context, arg CreateInterestRateTxParams) (InterestRates, error) {
+	var result InterestRates
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateInterestRate(ctx, arg.CreateInterestRateParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionInterestRateCreate,
+			TargetType: AuditTargetInterestRate,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createSavingsAccount(t *testing.T, balance int64) Accounts {
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    CreateRandomUser(t).Username,
+		Balance:  balance,
+		Currency: util.RandomCurrency(),
+		Type:     util.SavingsAccount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.SavingsAccount, account.Type)
+
+	// a rate in effect long ago, so the product has one whatever other tests set
+	longAgo := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = testQueries.GetInterestRate(context.Background(), GetInterestRateParams{
+		AccountType: util.SavingsAccount,
+		Currency:    account.Currency,
+		OnDate:      longAgo,
+	})
+	if err == sql.ErrNoRows {
+		_, err = testQueries.CreateInterestRate(context.Background(), CreateInterestRateParams{
+			AccountType:   util.SavingsAccount,
+			Currency:      account.Currency,
+			AnnualRateBps: 500,
+			EffectiveFrom: longAgo,
+		})
+	}
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestAccrueAndPostInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createSavingsAccount(t, 1_000_000)
+
+	now := time.Now().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+
+	// money paid in today doesn't earn interest for yesterday
+	_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{AccountID: account.ID, Amount: 5000})
+	require.NoError(t, err)
+	_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account.ID, Ammount: 5000})
+	require.NoError(t, err)
+
+	accrued, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+		AccountID:   account.ID,
+		AccrualDate: yesterday,
+	})
+	require.NoError(t, err)
+	require.True(t, accrued.Accrued)
+	require.Equal(t, int64(1_000_000), accrued.Accrual.Balance)
+	require.Equal(t, util.DailyInterest(1_000_000, accrued.Accrual.AnnualRateBps), accrued.Accrual.Amount)
+	require.Positive(t, accrued.Accrual.Amount)
+
+	// accruing the same day again does nothing
+	again, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+		AccountID:   account.ID,
+		AccrualDate: yesterday,
+	})
+	require.NoError(t, err)
+	require.False(t, again.Accrued)
+
+	period := time.Date(yesterday.Year(), yesterday.Month(), 1, 0, 0, 0, 0, time.UTC)
+	posted, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+		AccountID: account.ID,
+		Period:    period,
+	})
+	require.NoError(t, err)
+	require.True(t, posted.Posted)
+	require.Equal(t, accrued.Accrual.Amount, posted.Posting.Amount)
+	require.Equal(t, accrued.Accrual.Amount, posted.Entry.Amount)
+	require.Equal(t, -accrued.Accrual.Amount, posted.ExpenseEntry.Amount)
+	require.Equal(t, EntryCategoryInterest, posted.Entry.Category)
+	require.Equal(t, int64(1_005_000)+accrued.Accrual.Amount, posted.Account.Balance)
+	require.Equal(t, util.SystemAccount, posted.ExpenseAccount.Type)
+
+	accruals, err := testQueries.ListInterestAccruals(context.Background(), ListInterestAccrualsParams{
+		AccountID: account.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+	require.Equal(t, posted.Posting.ID, accruals[0].PostingID.Int64)
+
+	// posting the same month again does nothing
+	again2, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+		AccountID: account.ID,
+		Period:    period,
+	})
+	require.NoError(t, err)
+	require.False(t, again2.Posted)
+}
+
+func TestAccrueInterestTxNoRate(t *testing.T) {
+	store := NewStore(testDB)
+
+	// checking accounts earn nothing until a rate is set for them
+	account := CreateRandomAccount(t)
+	result, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+		AccountID:   account.ID,
+		AccrualDate: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.False(t, result.Accrued)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Journal categories, i.e. what moved the money.
+const (
+	JournalCategoryTransfer          = "transfer"
+	JournalCategoryFee               = "fee"
+	JournalCategoryReversal          = "reversal"
+	JournalCategoryInterest          = "interest"
+	JournalCategoryOverdraftInterest = "overdraft_interest"
+	JournalCategoryDeposit           = "deposit"
+	JournalCategoryLoanInterest      = "loan_interest"
+)
+
+// GL accounts of the chart of accounts that are posted to without a bank account.
+// The others are posted to through the bank accounts that are their sub-ledgers.
+const (
+	GLAccountCash           = "1000"
+	GLAccountInterestIncome = "4100"
+)
+
+// ErrUnbalancedJournal is returned when the postings of a journal don't sum to zero.
+var ErrUnbalancedJournal = errors.New("journal postings don't sum to zero")
+
+// GLPosting is a posting straight to a GL account, without a bank account.
+type GLPosting struct {
+	GLAccountCode string
+	Amount        int64
+}
+
+// postJournal records a money movement in the general ledger: a posting to the GL account of each
+// entry's bank account, and the given postings straight to GL accounts. Debits are positive and
+// credits negative, so unless they sum to zero it returns ErrUnbalancedJournal, which rolls the
+// transaction back. The database checks the same invariant again when the transaction commits.
+func postJournal(ctx context.Context, q *Queries, arg CreateJournalParams, entries []Entries, glPostings ...GLPosting) (Journals, error) {
+	journal, err := q.CreateJournal(ctx, arg)
+	if err != nil {
+		return journal, err
+	}
+
+	var sum int64
+	for _, entry := range entries {
+		posting, err := q.CreateEntryPosting(ctx, CreateEntryPostingParams{
+			JournalID: journal.ID,
+			EntryID:   entry.ID,
+		})
+		if err != nil {
+			return journal, err
+		}
+		sum += posting.Amount
+	}
+
+	for _, glPosting := range glPostings {
+		posting, err := q.CreateGLPosting(ctx, CreateGLPostingParams{
+			JournalID:     journal.ID,
+			GlAccountCode: glPosting.GLAccountCode,
+			Amount:        glPosting.Amount,
+		})
+		if err != nil {
+			return journal, err
+		}
+		sum += posting.Amount
+	}
+
+	if sum != 0 {
+		return journal, ErrUnbalancedJournal
+	}
+	return journal, nil
+}
+
+// transferJournal returns the parameters of a journal belonging to a transfer.
+func transferJournal(category string, transfer Transfers, currency string) CreateJournalParams {
+	return CreateJournalParams{
+		Category:    category,
+		Description: transfer.Description,
+		Currency:    currency,
+		TransferID:  sql.NullInt64{Int64: transfer.ID, Valid: true},
+	}
+}
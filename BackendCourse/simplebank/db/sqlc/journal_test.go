@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireBalancedJournal(t *testing.T, journal Journals) []Postings {
+	postings, err := testQueries.ListJournalPostings(context.Background(), journal.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, postings)
+
+	var sum int64
+	for _, posting := range postings {
+		sum += posting.Amount
+	}
+	require.Zero(t, sum)
+	return postings
+}
+
+func TestTransferJournal(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := updateOverdraftLimit(t, CreateRandomAccount(t), 1000)
+	account2 := CreateRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	journals, err := testQueries.ListTransferJournals(context.Background(), result.Transfer.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, journals)
+	require.Equal(t, JournalCategoryTransfer, journals[0].Category)
+	require.Equal(t, account1.Currency, journals[0].Currency)
+
+	// the from customer is debited and the to customer credited
+	postings := requireBalancedJournal(t, journals[0])
+	require.Len(t, postings, 2)
+	require.Equal(t, account1.ID, postings[0].AccountID.Int64)
+	require.Equal(t, int64(10), postings[0].Amount)
+	require.Equal(t, account2.ID, postings[1].AccountID.Int64)
+	require.Equal(t, int64(-10), postings[1].Amount)
+	require.Equal(t, "2000", postings[1].GlAccountCode)
+}
+
+func TestDepositTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+
+	result, err := store.DepositTx(context.Background(), DepositTxParams{
+		AccountID:   account.ID,
+		Amount:      250,
+		Description: "Cash at the counter",
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+250, result.Account.Balance)
+	require.Equal(t, int64(250), result.Entry.Amount)
+	require.Equal(t, EntryCategoryDeposit, result.Entry.Category)
+	require.Equal(t, JournalCategoryDeposit, result.Journal.Category)
+
+	// cash is debited, the customer's deposits credited
+	postings := requireBalancedJournal(t, result.Journal)
+	require.Len(t, postings, 2)
+	require.Equal(t, int64(-250), postings[0].Amount)
+	require.Equal(t, GLAccountCash, postings[1].GlAccountCode)
+	require.False(t, postings[1].AccountID.Valid)
+	require.Equal(t, int64(250), postings[1].Amount)
+}
+
+func TestUnbalancedJournal(t *testing.T) {
+	tx, err := testDB.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	q := New(tx)
+	_, err = postJournal(context.Background(), q, CreateJournalParams{
+		Category: JournalCategoryDeposit,
+		Currency: "USD",
+	}, nil, GLPosting{GLAccountCode: GLAccountCash, Amount: 100})
+	require.ErrorIs(t, err, ErrUnbalancedJournal)
+
+	// the database refuses it too when the transaction commits
+	err = tx.Commit()
+	require.Error(t, err)
+}
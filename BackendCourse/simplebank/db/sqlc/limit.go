@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Names of the transfer limits, as reported by LimitExceededError.
+const (
+	LimitSingleTransfer = "single_transfer"
+	LimitDailyAmount    = "daily_amount"
+	LimitMonthlyAmount  = "monthly_amount"
+	LimitDailyCount     = "daily_count"
+)
+
+// LimitExceededError is returned when a transfer would go over one of the from account's transfer limits.
+// ResetsAt is when the same transfer would fit in the limit again: for the rolling daily limits, when enough
+// of the transfers counted have left the window, and for the monthly limit, the start of the next month.
+// It is zero for the single transfer limit, and for a daily limit the transfer goes over on its own.
+type LimitExceededError struct {
+	Limit    string    `json:"limit"`
+	Max      int64     `json:"max"`
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.ResetsAt.IsZero() {
+		return fmt.Sprintf("limit_exceeded: %s limit of %d", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("limit_exceeded: %s limit of %d, resets at %s", e.Limit, e.Max, e.ResetsAt.Format(time.RFC3339))
+}
+
+// checkTransferLimits returns a *LimitExceededError if the transfer of amount from the account goes over
+// the limits of the account, or of its owner's tier when the account has none of its own.
+// It must be called after the transfer was created and the from account row was locked, so the
+// transfer is counted and concurrent transfers from the same account can't both pass the check.
+func checkTransferLimits(ctx context.Context, q *Queries, from Accounts, amount int64) error {
+	owner, err := q.GetUser(ctx, from.Owner)
+	if err != nil {
+		return err
+	}
+
+	limit, err := q.GetApplicableTransferLimit(ctx, GetApplicableTransferLimitParams{
+		AccountID: from.ID,
+		Tier:      owner.Tier,
+		Currency:  from.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if limit.SingleMax.Valid && amount > limit.SingleMax.Int64 {
+		return &LimitExceededError{Limit: LimitSingleTransfer, Max: limit.SingleMax.Int64}
+	}
+
+	now := time.Now().UTC()
+	if limit.DailyMax.Valid || limit.DailyCountMax.Valid {
+		window, err := q.ListOutgoingTransferAmounts(ctx, ListOutgoingTransferAmountsParams{
+			FromAccountID: from.ID,
+			Since:         now.Add(-24 * time.Hour),
+		})
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for _, transfer := range window {
+			total += transfer.Amount
+		}
+		count := int64(len(window))
+
+		if limit.DailyMax.Valid && total > limit.DailyMax.Int64 {
+			resetsAt := rollingResetsAt(window, func(total int64, count int64) bool { return total <= limit.DailyMax.Int64 })
+			return &LimitExceededError{Limit: LimitDailyAmount, Max: limit.DailyMax.Int64, ResetsAt: resetsAt}
+		}
+		if limit.DailyCountMax.Valid && count > int64(limit.DailyCountMax.Int32) {
+			resetsAt := rollingResetsAt(window, func(total int64, count int64) bool { return count <= int64(limit.DailyCountMax.Int32) })
+			return &LimitExceededError{Limit: LimitDailyCount, Max: int64(limit.DailyCountMax.Int32), ResetsAt: resetsAt}
+		}
+	}
+
+	if limit.MonthlyMax.Valid {
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		totals, err := q.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+			FromAccountID: from.ID,
+			Since:         monthStart,
+		})
+		if err != nil {
+			return err
+		}
+
+		if totals.Total > limit.MonthlyMax.Int64 {
+			return &LimitExceededError{Limit: LimitMonthlyAmount, Max: limit.MonthlyMax.Int64, ResetsAt: monthStart.AddDate(0, 1, 0)}
+		}
+	}
+
+	return nil
+}
+
+// rollingResetsAt returns when the transfers of a rolling 24 hour window, oldest first, have left it until
+// the rest are within the limit. The last transfer of the window is the one being checked, which stays
+// counted, so it returns the zero time if that transfer goes over the limit on its own.
+func rollingResetsAt(window []ListOutgoingTransferAmountsRow, within func(total int64, count int64) bool) time.Time {
+	var total int64
+	for _, transfer := range window {
+		total += transfer.Amount
+	}
+	count := int64(len(window))
+
+	for i := 0; i < len(window)-1; i++ {
+		total -= window[i].Amount
+		count--
+		if within(total, count) {
+			return window[i].CreatedAt.Add(24 * time.Hour)
+		}
+	}
+	return time.Time{}
+}
+
+// CreateTransferLimitTxParams contains the parameters for the CreateTransferLimitTx function.
+type CreateTransferLimitTxParams struct {
+	CreateTransferLimitParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateTransferLimitTx creates a transfer limit and records its audit event in the same transaction.
+func (store *SQLStore) CreateTransferLimitTx(ctx context.Context, arg CreateTransferLimitTxParams) (TransferLimits, error) {
+	var result TransferLimits
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateTransferLimit(ctx, arg.CreateTransferLimitParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferLimitCreate,
+			TargetType: AuditTargetTransferLimit,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteTransferLimitTxParams contains the parameters for the DeleteTransferLimitTx function.
+type DeleteTransferLimitTxParams struct {
+	ID    int64     `json:"id"`
+	Audit AuditMeta `json:"-"`
+}
+
+// DeleteTransferLimitTx deletes a transfer limit and records its audit event, with the deleted limit, in the same transaction.
+// It returns sql.ErrNoRows if the limit doesn't exist.
+func (store *SQLStore) DeleteTransferLimitTx(ctx context.Context, arg DeleteTransferLimitTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetTransferLimit(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTransferLimit(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferLimitDelete,
+			TargetType: AuditTargetTransferLimit,
+			TargetID:   strconv.FormatInt(before.ID, 10),
+			Before:     before,
+		})
+		return err
+	})
+}
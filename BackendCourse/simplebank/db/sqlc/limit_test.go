@@ -0,0 +1,274 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createAccountTransferLimit(t *testing.T, account Accounts, arg CreateTransferLimitParams) TransferLimits {
+	arg.AccountID = sql.NullInt64{Int64: account.ID, Valid: true}
+	limit, err := testQueries.CreateTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.AccountID, limit.AccountID)
+	require.False(t, limit.Tier.Valid)
+
+	t.Cleanup(func() {
+		testQueries.DeleteTransferLimit(context.Background(), limit.ID)
+	})
+	return limit
+}
+
+func TestTransferTxSingleLimit(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	createAccountTransferLimit(t, account1, CreateTransferLimitParams{
+		SingleMax: sql.NullInt64{Int64: 50, Valid: true},
+	})
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        51,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitSingleTransfer, limitErr.Limit)
+	require.Equal(t, int64(50), limitErr.Max)
+	require.True(t, limitErr.ResetsAt.IsZero())
+}
+
+func TestTransferTxDailyLimits(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	createAccountTransferLimit(t, account1, CreateTransferLimitParams{
+		DailyMax: sql.NullInt64{Int64: 100, Valid: true},
+	})
+
+	first, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        60,
+	})
+	require.NoError(t, err)
+
+	// a hold counts too
+	_, err = store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(100), limitErr.Max)
+	require.WithinDuration(t, first.Transfer.CreatedAt.Add(24*time.Hour), limitErr.ResetsAt, time.Second)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTxLimitsDontCountPocketMovesOrPaymentsToTheBank(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	pocket := createRandomPocket(t, account1)
+	createAccountTransferLimit(t, account1, CreateTransferLimitParams{
+		DailyMax:      sql.NullInt64{Int64: 100, Valid: true},
+		DailyCountMax: sql.NullInt32{Int32: 1, Valid: true},
+		MonthlyMax:    sql.NullInt64{Int64: 100, Valid: true},
+	})
+
+	loanAccount, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountLoans,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+
+	// neither is limited, so neither uses up the limits
+	for _, toAccountID := range []int64{pocket.ID, loanAccount.AccountID} {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   toAccountID,
+			Amount:        80,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+}
+
+func TestTransferTxDailyLimitResetsWhenEnoughTransfersLeave(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	createAccountTransferLimit(t, account1, CreateTransferLimitParams{
+		DailyMax: sql.NullInt64{Int64: 100, Valid: true},
+	})
+
+	transfers := make([]TransferTxResult, 3)
+	for i := range transfers {
+		var err error
+		transfers[i], err = store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        30,
+		})
+		require.NoError(t, err)
+	}
+
+	// 50 only fits once the first two transfers have left the window
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.WithinDuration(t, transfers[1].Transfer.CreatedAt.Add(24*time.Hour), limitErr.ResetsAt, time.Second)
+
+	// 101 never fits
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        101,
+	})
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.True(t, limitErr.ResetsAt.IsZero())
+}
+
+func TestTransferTxDailyCountLimit(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	createAccountTransferLimit(t, account1, CreateTransferLimitParams{
+		DailyCountMax: sql.NullInt32{Int32: 2, Valid: true},
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        1,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDailyCount, limitErr.Limit)
+	require.Equal(t, int64(2), limitErr.Max)
+}
+
+func TestTransferTxMonthlyLimit(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	createAccountTransferLimit(t, account1, CreateTransferLimitParams{
+		MonthlyMax: sql.NullInt64{Int64: 20, Valid: true},
+	})
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        21,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitMonthlyAmount, limitErr.Limit)
+	require.Equal(t, 1, limitErr.ResetsAt.Day())
+	require.True(t, limitErr.ResetsAt.After(time.Now()))
+
+	// nothing was written
+	account, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+}
+
+func TestGetApplicableTransferLimit(t *testing.T) {
+	account := CreateRandomAccount(t)
+	user, err := testQueries.UpdateUserTier(context.Background(), UpdateUserTierParams{
+		Username: account.Owner,
+		Tier:     util.PremiumTier,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.PremiumTier, user.Tier)
+
+	tierLimit, err := testQueries.CreateTransferLimit(context.Background(), CreateTransferLimitParams{
+		Tier:      sql.NullString{String: util.PremiumTier, Valid: true},
+		Currency:  sql.NullString{String: account.Currency, Valid: true},
+		SingleMax: sql.NullInt64{Int64: 1000, Valid: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		testQueries.DeleteTransferLimit(context.Background(), tierLimit.ID)
+	})
+
+	arg := GetApplicableTransferLimitParams{
+		AccountID: account.ID,
+		Tier:      util.PremiumTier,
+		Currency:  account.Currency,
+	}
+	limit, err := testQueries.GetApplicableTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, tierLimit.ID, limit.ID)
+
+	// the account's own limits win
+	accountLimit := createAccountTransferLimit(t, account, CreateTransferLimitParams{
+		SingleMax: sql.NullInt64{Int64: 10, Valid: true},
+	})
+	limit, err = testQueries.GetApplicableTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, accountLimit.ID, limit.ID)
+}
@@ -0,0 +1,283 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// SystemAccountLoans is the purpose of the bank-owned accounts that loans are paid from and repaid to, one per currency.
+// Their balance is negative while more is lent out than was repaid.
+const SystemAccountLoans = "loans"
+
+// Loan statuses
+const (
+	LoanActive = "active"
+	LoanRepaid = "repaid"
+)
+
+// Loan installment statuses
+const (
+	LoanInstallmentDue  = "due"
+	LoanInstallmentPaid = "paid"
+)
+
+// EntryCategoryLoan is the category of the transfers paying out and repaying loans.
+const EntryCategoryLoan = "loan"
+
+// ErrLoanInstallmentNotDue is returned when collecting an installment that was already paid.
+var ErrLoanInstallmentNotDue = errors.New("loan installment is not due")
+
+// CreateLoanTxParams contains the parameters for the CreateLoanTx function.
+// The loan is paid into the account and repaid from it, by its owner.
+// The schedule starts on StartDate, the first installment being due a month later.
+// Audit tells who granted the loan, for its audit event.
+type CreateLoanTxParams struct {
+	AccountID     int64     `json:"account_id"`
+	Principal     int64     `json:"principal"`
+	AnnualRateBps int64     `json:"annual_rate_bps"`
+	TermMonths    int32     `json:"term_months"`
+	StartDate     time.Time `json:"start_date"`
+	CreatedBy     string    `json:"created_by"`
+	Audit         AuditMeta `json:"-"`
+}
+
+// CreateLoanTxResult contains the result of the CreateLoanTx function.
+type CreateLoanTxResult struct {
+	Loan         Loans              `json:"loan"`
+	Installments []LoanInstallments `json:"installments"`
+	Disbursement TransferTxResult   `json:"disbursement"`
+}
+
+// CreateLoanTx grants a loan: it pays the principal into the account from the bank's loan account for its currency,
+// like TransferTx, and stores the loan with its amortization schedule, see util.AmortizationSchedule.
+// The loan's audit event is recorded in the same transaction.
+func (store *SQLStore) CreateLoanTx(ctx context.Context, arg CreateLoanTxParams) (CreateLoanTxResult, error) {
+	var result CreateLoanTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		loanAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountLoans,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Disbursement, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: loanAccount.AccountID,
+			ToAccountID:   account.ID,
+			Amount:        arg.Principal,
+			Description:   "Loan disbursement",
+			Category:      EntryCategoryLoan,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Loan, err = q.CreateLoan(ctx, CreateLoanParams{
+			Borrower:               account.Owner,
+			AccountID:              account.ID,
+			Currency:               account.Currency,
+			Principal:              arg.Principal,
+			AnnualRateBps:          arg.AnnualRateBps,
+			TermMonths:             arg.TermMonths,
+			DisbursementTransferID: result.Disbursement.Transfer.ID,
+			CreatedBy:              arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		schedule := util.AmortizationSchedule(arg.Principal, arg.AnnualRateBps, int(arg.TermMonths), arg.StartDate)
+		result.Installments = make([]LoanInstallments, len(schedule))
+		for i, installment := range schedule {
+			result.Installments[i], err = q.CreateLoanInstallment(ctx, CreateLoanInstallmentParams{
+				LoanID:    result.Loan.ID,
+				Number:    int32(i + 1),
+				DueDate:   installment.DueDate,
+				Principal: installment.Principal,
+				Interest:  installment.Interest,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionLoanCreate,
+			TargetType: AuditTargetLoan,
+			TargetID:   strconv.FormatInt(result.Loan.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CollectLoanInstallmentTxResult contains the result of the CollectLoanInstallmentTx function.
+// Transfer repays the principal. ChargeEntry and ChargeJournal charge the interest and late fee,
+// and are zero if there were none. Account is the borrower's account after both.
+type CollectLoanInstallmentTxResult struct {
+	Installment   LoanInstallments `json:"installment"`
+	Loan          Loans            `json:"loan"`
+	Transfer      TransferTxResult `json:"transfer"`
+	ChargeEntry   Entries          `json:"charge_entry"`
+	ChargeJournal Journals         `json:"charge_journal"`
+	Account       Accounts         `json:"account"`
+}
+
+// CollectLoanInstallmentTx collects an installment of a loan, with its late fee if it was charged one,
+// from the borrower's account, and lowers the outstanding principal.
+// Only the principal was lent, so only it is transferred back into the bank's loan account, like TransferTx.
+// The interest and the late fee are charged from the account as one entry, posted to the bank's interest income
+// like overdraft interest.
+// The loan is repaid when its last installment is collected.
+// If the account can't pay it all, ErrInsufficientFunds is returned and the installment stays due.
+// The collection's audit event, made by the system, is recorded in the same transaction.
+func (store *SQLStore) CollectLoanInstallmentTx(ctx context.Context, installmentID int64) (CollectLoanInstallmentTxResult, error) {
+	var result CollectLoanInstallmentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		installment, err := q.GetLoanInstallmentForUpdate(ctx, installmentID)
+		if err != nil {
+			return err
+		}
+		if installment.Status != LoanInstallmentDue {
+			return ErrLoanInstallmentNotDue
+		}
+
+		loan, err := q.GetLoanForUpdate(ctx, installment.LoanID)
+		if err != nil {
+			return err
+		}
+
+		loanAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountLoans,
+			Currency: loan.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		description := fmt.Sprintf("Loan %d installment %d", loan.ID, installment.Number)
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: loan.AccountID,
+			ToAccountID:   loanAccount.AccountID,
+			Amount:        installment.Principal,
+			Description:   description,
+			Category:      EntryCategoryLoan,
+		})
+		if err != nil {
+			return err
+		}
+		result.Account = result.Transfer.FromAccount
+
+		// the interest and the late fee were never lent, they are the bank's interest income
+		if charge := installment.Interest + installment.LateFee; charge > 0 {
+			result.ChargeEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+				AccountID:   loan.AccountID,
+				Amount:      -charge,
+				Description: description,
+				Category:    EntryCategoryLoan,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:      loan.AccountID,
+				Ammount: -charge,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := checkOverdraft(result.Account); err != nil {
+				return err
+			}
+
+			result.ChargeJournal, err = postJournal(ctx, q, CreateJournalParams{
+				Category:    JournalCategoryLoanInterest,
+				Description: description,
+				Currency:    loan.Currency,
+			}, []Entries{result.ChargeEntry}, GLPosting{GLAccountCode: GLAccountInterestIncome, Amount: -charge})
+			if err != nil {
+				return err
+			}
+
+			err = writeEntryEvent(ctx, q, result.ChargeEntry, result.Account)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Installment, err = q.PayLoanInstallment(ctx, PayLoanInstallmentParams{
+			ID:         installment.ID,
+			TransferID: result.Transfer.Transfer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Loan, err = q.RepayLoanPrincipal(ctx, RepayLoanPrincipalParams{
+			ID:     loan.ID,
+			Amount: installment.Principal,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			Action:     AuditActionLoanCollectInstallment,
+			TargetType: AuditTargetLoan,
+			TargetID:   strconv.FormatInt(loan.ID, 10),
+			Before:     installment,
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ChargeLoanLateFeesTx adds the late fee to the installments still unpaid a grace period after their due date,
+// see ChargeLoanLateFees, and records an audit event, made by the system, for every installment charged
+// in the same transaction. It returns the installments that were charged.
+func (store *SQLStore) ChargeLoanLateFeesTx(ctx context.Context, arg ChargeLoanLateFeesParams) ([]LoanInstallments, error) {
+	var charged []LoanInstallments
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		charged, err = q.ChargeLoanLateFees(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, installment := range charged {
+			_, err = appendAuditEvent(ctx, q, AuditTxParams{
+				Action:     AuditActionLoanChargeLateFee,
+				TargetType: AuditTargetLoan,
+				TargetID:   strconv.FormatInt(installment.LoanID, 10),
+				After:      installment,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return charged, err
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateLoanAndCollectTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+	banker := CreateRandomUser(t)
+
+	start := time.Now().UTC().AddDate(0, -3, 0)
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	created, err := store.CreateLoanTx(context.Background(), CreateLoanTxParams{
+		AccountID:     account.ID,
+		Principal:     120000,
+		AnnualRateBps: 1200,
+		TermMonths:    2,
+		StartDate:     start,
+		CreatedBy:     banker.Username,
+	})
+	require.NoError(t, err)
+
+	// the principal is paid in, from the bank's loan account which may go negative
+	require.Equal(t, account.Balance+120000, created.Disbursement.ToAccount.Balance)
+	require.Equal(t, util.SystemAccount, created.Disbursement.FromAccount.Type)
+	require.Equal(t, EntryCategoryLoan, created.Disbursement.Transfer.Category)
+	require.Zero(t, created.Disbursement.Fee.Amount)
+
+	loan := created.Loan
+	require.Equal(t, account.Owner, loan.Borrower)
+	require.Equal(t, int64(120000), loan.OutstandingPrincipal)
+	require.Equal(t, LoanActive, loan.Status)
+	require.Len(t, created.Installments, 2)
+	require.Equal(t, int64(120000), created.Installments[0].Principal+created.Installments[1].Principal)
+
+	// both installments are overdue by now, and one was charged a late fee
+	charged, err := testQueries.ChargeLoanLateFees(context.Background(), ChargeLoanLateFeesParams{
+		LateFee: 2500,
+		Cutoff:  created.Installments[0].DueDate.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	var lateFee int64
+	for _, installment := range charged {
+		if installment.ID == created.Installments[0].ID {
+			lateFee = installment.LateFee
+		}
+	}
+	require.Equal(t, int64(2500), lateFee)
+
+	first := created.Installments[0]
+	collected, err := store.CollectLoanInstallmentTx(context.Background(), first.ID)
+	require.NoError(t, err)
+	require.Equal(t, LoanInstallmentPaid, collected.Installment.Status)
+	require.Equal(t, created.Disbursement.ToAccount.Balance-first.Principal-first.Interest-lateFee, collected.Account.Balance)
+
+	// only the principal goes back to the loan account, the interest and late fee are interest income
+	require.Equal(t, first.Principal, collected.Transfer.Transfer.Amount)
+	require.Equal(t, created.Disbursement.FromAccount.Balance+first.Principal, collected.Transfer.ToAccount.Balance)
+	require.Equal(t, -(first.Interest + lateFee), collected.ChargeEntry.Amount)
+	require.Equal(t, EntryCategoryLoan, collected.ChargeEntry.Category)
+	require.Equal(t, JournalCategoryLoanInterest, collected.ChargeJournal.Category)
+
+	// the borrower's deposits are debited and interest income credited
+	postings := requireBalancedJournal(t, collected.ChargeJournal)
+	require.Len(t, postings, 2)
+	require.Equal(t, first.Interest+lateFee, postings[0].Amount)
+	require.Equal(t, GLAccountInterestIncome, postings[1].GlAccountCode)
+	require.Equal(t, -(first.Interest + lateFee), postings[1].Amount)
+	require.Equal(t, int64(120000)-first.Principal, collected.Loan.OutstandingPrincipal)
+	require.Equal(t, LoanActive, collected.Loan.Status)
+
+	// an installment is collected only once
+	_, err = store.CollectLoanInstallmentTx(context.Background(), first.ID)
+	require.ErrorIs(t, err, ErrLoanInstallmentNotDue)
+
+	collected, err = store.CollectLoanInstallmentTx(context.Background(), created.Installments[1].ID)
+	require.NoError(t, err)
+	require.Zero(t, collected.Loan.OutstandingPrincipal)
+	require.Equal(t, LoanRepaid, collected.Loan.Status)
+}
+
+func TestCollectLoanInstallmentTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+	banker := CreateRandomUser(t)
+
+	created, err := store.CreateLoanTx(context.Background(), CreateLoanTxParams{
+		AccountID:  account.ID,
+		Principal:  1000,
+		TermMonths: 1,
+		StartDate:  time.Now().UTC(),
+		CreatedBy:  banker.Username,
+	})
+	require.NoError(t, err)
+
+	// spend the loan and everything else
+	_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:      account.ID,
+		Ammount: -created.Disbursement.ToAccount.Balance,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CollectLoanInstallmentTx(context.Background(), created.Installments[0].ID)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	installments, err := testQueries.ListLoanInstallments(context.Background(), created.Loan.ID)
+	require.NoError(t, err)
+	require.Equal(t, LoanInstallmentDue, installments[0].Status)
+}
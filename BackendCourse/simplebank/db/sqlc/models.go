@@ -5,15 +5,63 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type AccountApprovers struct {
+	AccountID int64     `json:"account_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Accounts struct {
 	ID        int64     `json:"id"`
 	Owner     string    `json:"owner"`
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// the balance may go down to -overdraft_limit
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	Type           string `json:"type"`
+	// balance minus the amounts held by pending transfers
+	AvailableBalance int64 `json:"available_balance"`
+	// transfers above it need a second user's approval, NULL means never
+	ApprovalThreshold sql.NullInt64 `json:"approval_threshold"`
+	// the account a pocket belongs to, NULL for other accounts
+	ParentAccountID sql.NullInt64 `json:"parent_account_id"`
+	Name            string        `json:"name"`
+	// what the owner saves up to in a pocket, NULL means no target
+	TargetAmount sql.NullInt64 `json:"target_amount"`
+	// the GL account the account is a sub-ledger of
+	GlAccountCode string `json:"gl_account_code"`
+}
+
+type AuditEvents struct {
+	ID int64 `json:"id"`
+	// the username of who made the change, anonymous or system
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	RequestID  string `json:"request_id"`
+	ClientIp   string `json:"client_ip"`
+	// json, not jsonb, so the text the hash was computed from is kept as is
+	Before   json.RawMessage `json:"before"`
+	After    json.RawMessage `json:"after"`
+	PrevHash string          `json:"prev_hash"`
+	// hex SHA-256 of the previous hash and the event, chaining every event to the ones before it
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BalanceSnapshots struct {
+	AccountID    int64     `json:"account_id"`
+	SnapshotDate time.Time `json:"snapshot_date"`
+	// the balance at the end of the UTC day
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Entries struct {
@@ -22,15 +70,343 @@ type Entries struct {
 	// can be negative or possitive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// copied from the transfer of the entry
+	Description string `json:"description"`
+	Reference   string `json:"reference"`
+	Category    string `json:"category"`
+	// the transfer that moved the money, NULL for fees, interest and other bank charges
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type FeeRules struct {
+	ID int64 `json:"id"`
+	// NULL matches any currency
+	Currency sql.NullString `json:"currency"`
+	// NULL matches any account type
+	AccountType sql.NullString `json:"account_type"`
+	FlatFee     int64          `json:"flat_fee"`
+	// percentage of the amount in basis points
+	PercentageBps int64 `json:"percentage_bps"`
+	MinFee        int64 `json:"min_fee"`
+	// NULL means no cap
+	MaxFee    sql.NullInt64 `json:"max_fee"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type GlAccounts struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InterestAccruals struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// the end-of-day balance the interest was computed on
+	Balance       int64 `json:"balance"`
+	AnnualRateBps int64 `json:"annual_rate_bps"`
+	Amount        int64 `json:"amount"`
+	// NULL until the interest is credited to the account
+	PostingID sql.NullInt64 `json:"posting_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type InterestPostings struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// the first day of the month the interest was accrued in
+	Period  time.Time `json:"period"`
+	EntryID int64     `json:"entry_id"`
+	// the entry on the bank's interest expense account
+	ExpenseEntryID int64     `json:"expense_entry_id"`
+	Amount         int64     `json:"amount"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type InterestRates struct {
+	ID            int64  `json:"id"`
+	AccountType   string `json:"account_type"`
+	Currency      string `json:"currency"`
+	AnnualRateBps int64  `json:"annual_rate_bps"`
+	// the rate applies from this day until the next rate of the product takes effect
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Journals struct {
+	ID int64 `json:"id"`
+	// what moved the money, e.g. transfer, fee or deposit
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	Currency    string `json:"currency"`
+	// the transfer the journal belongs to, if any
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type LoanInstallments struct {
+	ID        int64     `json:"id"`
+	LoanID    int64     `json:"loan_id"`
+	Number    int32     `json:"number"`
+	DueDate   time.Time `json:"due_date"`
+	Principal int64     `json:"principal"`
+	Interest  int64     `json:"interest"`
+	// added once the installment is overdue, collected with it
+	LateFee int64 `json:"late_fee"`
+	// due or paid
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	PaidAt     sql.NullTime  `json:"paid_at"`
+}
+
+type Loans struct {
+	ID       int64  `json:"id"`
+	Borrower string `json:"borrower"`
+	// the borrower's account the loan is paid into and repaid from
+	AccountID            int64  `json:"account_id"`
+	Currency             string `json:"currency"`
+	Principal            int64  `json:"principal"`
+	AnnualRateBps        int64  `json:"annual_rate_bps"`
+	TermMonths           int32  `json:"term_months"`
+	OutstandingPrincipal int64  `json:"outstanding_principal"`
+	// active or repaid
+	Status                 string `json:"status"`
+	DisbursementTransferID int64  `json:"disbursement_transfer_id"`
+	// the banker who granted the loan
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MoneyRequests struct {
+	ID        int64  `json:"id"`
+	Requester string `json:"requester"`
+	Payer     string `json:"payer"`
+	// the requester's account in the currency, paid when the request is accepted
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	// pending, accepted, declined or expired
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	DecidedAt  sql.NullTime  `json:"decided_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type OutboxEvents struct {
+	ID            int64  `json:"id"`
+	EventType     string `json:"event_type"`
+	AggregateType string `json:"aggregate_type"`
+	// the events of the same aggregate, e.g. of one account, are published in id order
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int32           `json:"attempts"`
+	LastError     string          `json:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	// set while a relay is publishing the event, so no other relay publishes it at the same time
+	ClaimedUntil sql.NullTime `json:"claimed_until"`
+	PublishedAt  sql.NullTime `json:"published_at"`
+}
+
+type OverdraftCharges struct {
+	ID         int64     `json:"id"`
+	AccountID  int64     `json:"account_id"`
+	EntryID    int64     `json:"entry_id"`
+	ChargeDate time.Time `json:"charge_date"`
+	// the negative balance the interest was charged on
+	Balance int64 `json:"balance"`
+	// must be positive
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Payees struct {
+	ID       int64  `json:"id"`
+	Owner    string `json:"owner"`
+	Nickname string `json:"nickname"`
+	// resolved when the payee is added, also when it was given by an alias
+	ToAccountID int64  `json:"to_account_id"`
+	Currency    string `json:"currency"`
+	// large amounts can't be paid to the payee before then
+	CoolingOffUntil time.Time `json:"cooling_off_until"`
+	CreatedAt       time.Time `json:"created_at"`
+	// the largest amount that can be paid to the payee's account before cooling_off_until, however it is addressed
+	CoolingOffMaxAmount int64 `json:"cooling_off_max_amount"`
+}
+
+type Postings struct {
+	ID            int64  `json:"id"`
+	JournalID     int64  `json:"journal_id"`
+	GlAccountCode string `json:"gl_account_code"`
+	// the bank account of the posting, NULL for postings straight to a GL account
+	AccountID sql.NullInt64 `json:"account_id"`
+	// the entry the posting records on the account
+	EntryID sql.NullInt64 `json:"entry_id"`
+	// debits are positive, credits negative; the postings of a journal sum to zero
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type StandingOrderExecutions struct {
+	ID              int64     `json:"id"`
+	StandingOrderID int64     `json:"standing_order_id"`
+	ScheduledAt     time.Time `json:"scheduled_at"`
+	// running, succeeded, failed or pending_approval
+	Status     string         `json:"status"`
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	Error      sql.NullString `json:"error"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt sql.NullTime   `json:"finished_at"`
+	// the transfer request made instead of the transfer, when the amount was above the approval threshold
+	TransferRequestID sql.NullInt64 `json:"transfer_request_id"`
+}
+
+type StandingOrders struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	// daily, weekly or monthly
+	Frequency string `json:"frequency"`
+	// run every interval_count periods of the frequency
+	IntervalCount int32     `json:"interval_count"`
+	StartAt       time.Time `json:"start_at"`
+	// NULL means the order runs until cancelled
+	EndAt     sql.NullTime `json:"end_at"`
+	NextRunAt time.Time    `json:"next_run_at"`
+	// active, cancelled or completed
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SystemAccounts struct {
+	Purpose   string `json:"purpose"`
+	Currency  string `json:"currency"`
+	AccountID int64  `json:"account_id"`
+}
+
+type TransferBatchLegs struct {
+	ID       int64 `json:"id"`
+	BatchID  int64 `json:"batch_id"`
+	LegIndex int32 `json:"leg_index"`
+	// not a foreign key, so a leg to a missing account can be recorded as failed
+	ToAccountID int64 `json:"to_account_id"`
+	Amount      int64 `json:"amount"`
+	// succeeded or failed
+	Status     string         `json:"status"`
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	Error      sql.NullString `json:"error"`
+}
+
+type TransferBatches struct {
+	ID            int64  `json:"id"`
+	InitiatedBy   string `json:"initiated_by"`
+	FromAccountID int64  `json:"from_account_id"`
+	Currency      string `json:"currency"`
+	// atomic or best_effort
+	Mode string `json:"mode"`
+	// processing, completed, partial or failed
+	Status         string         `json:"status"`
+	LegCount       int32          `json:"leg_count"`
+	TotalAmount    int64          `json:"total_amount"`
+	SucceededCount int32          `json:"succeeded_count"`
+	Error          sql.NullString `json:"error"`
+	CreatedAt      time.Time      `json:"created_at"`
+	FinishedAt     sql.NullTime   `json:"finished_at"`
+}
+
+type TransferFees struct {
+	TransferID int64 `json:"transfer_id"`
+	// not a foreign key, so rules can be deleted after they were applied
+	FeeRuleID     int64 `json:"fee_rule_id"`
+	FeeAccountID  int64 `json:"fee_account_id"`
+	FromEntryID   int64 `json:"from_entry_id"`
+	ToEntryID     int64 `json:"to_entry_id"`
+	FlatFee       int64 `json:"flat_fee"`
+	PercentageFee int64 `json:"percentage_fee"`
+	// the fee actually charged, after the min and max caps
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TransferLimits struct {
+	ID       int64          `json:"id"`
+	Tier     sql.NullString `json:"tier"`
+	Currency sql.NullString `json:"currency"`
+	// limits of a single account, which replace the limits of its owner's tier
+	AccountID sql.NullInt64 `json:"account_id"`
+	SingleMax sql.NullInt64 `json:"single_max"`
+	// total over a rolling 24 hours, NULL means no limit
+	DailyMax sql.NullInt64 `json:"daily_max"`
+	// total over the calendar month in UTC, NULL means no limit
+	MonthlyMax sql.NullInt64 `json:"monthly_max"`
+	// number of transfers over a rolling 24 hours, NULL means no limit
+	DailyCountMax sql.NullInt32 `json:"daily_count_max"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+type TransferRequestDecisions struct {
+	ID                int64  `json:"id"`
+	TransferRequestID int64  `json:"transfer_request_id"`
+	DecidedBy         string `json:"decided_by"`
+	// approved or rejected
+	Decision  string    `json:"decision"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TransferRequests struct {
+	ID            int64  `json:"id"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	InitiatedBy   string `json:"initiated_by"`
+	// pending_approval, approved or rejected
+	Status string `json:"status"`
+	// the transfer made once the request was approved
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Description string        `json:"description"`
+	Reference   string        `json:"reference"`
+	Category    string        `json:"category"`
+}
+
+type TransferReversals struct {
+	// the compensating transfer
+	TransferID         int64     `json:"transfer_id"`
+	OriginalTransferID int64     `json:"original_transfer_id"`
+	Amount             int64     `json:"amount"`
+	InitiatedBy        string    `json:"initiated_by"`
+	Reason             string    `json:"reason"`
+	CreatedAt          time.Time `json:"created_at"`
 }
 
 type Transfers struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	// must be positive
+	// must be positive, the authorized amount for holds
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// pending, captured, voided or expired
+	Status string `json:"status"`
+	// the amount actually moved, at most amount
+	CapturedAmount int64 `json:"captured_amount"`
+	// when a pending hold is released if not captured
+	ExpiresAt sql.NullTime `json:"expires_at"`
+	// sum of the reversals of this transfer, never more than the captured amount
+	ReversedAmount int64 `json:"reversed_amount"`
+	// free text memo of what the payment was for
+	Description string `json:"description"`
+	// external reference, e.g. an invoice number
+	Reference string `json:"reference"`
+	Category  string `json:"category"`
 }
 
 type Users struct {
@@ -40,4 +416,48 @@ type Users struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
+	// selects the transfer limits of the user's accounts
+	Tier string `json:"tier"`
+	// only a verified email can be used to address transfers to the user
+	IsEmailVerified bool `json:"is_email_verified"`
+}
+
+type WebhookDeliveries struct {
+	ID         int64  `json:"id"`
+	EndpointID int64  `json:"endpoint_id"`
+	EventID    int64  `json:"event_id"`
+	EventType  string `json:"event_type"`
+	// the event as it is posted to the endpoint
+	Payload json.RawMessage `json:"payload"`
+	// pending, succeeded or failed, once it ran out of attempts
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	// the HTTP status of the last attempt, 0 if the endpoint didn't answer
+	ResponseCode  int32        `json:"response_code"`
+	LastError     string       `json:"last_error"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	ClaimedUntil  sql.NullTime `json:"claimed_until"`
+	DeliveredAt   sql.NullTime `json:"delivered_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type WebhookDeliveryAttempts struct {
+	ID         int64 `json:"id"`
+	DeliveryID int64 `json:"delivery_id"`
+	// the HTTP status the endpoint answered, 0 if it didn't answer
+	ResponseCode int32     `json:"response_code"`
+	Error        string    `json:"error"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WebhookEndpoints struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// the key the deliveries to the endpoint are signed with
+	Secret string `json:"secret"`
+	// the types of the events delivered to the endpoint
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
 }
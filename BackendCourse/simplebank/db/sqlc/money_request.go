@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Money request statuses. A request waits for its payer, who accepts or declines it, or it expires.
+const (
+	MoneyRequestPending  = "pending"
+	MoneyRequestAccepted = "accepted"
+	MoneyRequestDeclined = "declined"
+	MoneyRequestExpired  = "expired"
+)
+
+var (
+	// ErrMoneyRequestNotPending is returned when deciding on a money request that was already decided or expired.
+	ErrMoneyRequestNotPending = errors.New("money request is not pending")
+	// ErrMoneyRequestExpired is returned when accepting a money request after its expiry, even if the sweeper hasn't expired it yet.
+	ErrMoneyRequestExpired = errors.New("money request has expired")
+)
+
+// AcceptMoneyRequestTxParams contains the parameters for the AcceptMoneyRequestTx function.
+// FromAccountID is the payer's account the request is paid from.
+// Audit tells who accepted the request, for its audit event.
+type AcceptMoneyRequestTxParams struct {
+	RequestID     int64     `json:"request_id"`
+	FromAccountID int64     `json:"from_account_id"`
+	Audit         AuditMeta `json:"-"`
+}
+
+// AcceptMoneyRequestTxResult contains the result of the AcceptMoneyRequestTx function.
+type AcceptMoneyRequestTxResult struct {
+	Request  MoneyRequests    `json:"request"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// AcceptMoneyRequestTx pays a pending money request from the payer's account to the requester's account,
+// like TransferTx, and marks it accepted and records its audit event in the same transaction.
+// If the transfer fails, for example for lack of funds, nothing is written and the request stays pending.
+func (store *SQLStore) AcceptMoneyRequestTx(ctx context.Context, arg AcceptMoneyRequestTxParams) (AcceptMoneyRequestTxResult, error) {
+	var result AcceptMoneyRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// the lock makes a concurrent decline or expiry wait, so only one of them wins
+		request, err := q.GetMoneyRequestForUpdate(ctx, arg.RequestID)
+		if err != nil {
+			return err
+		}
+
+		if request.Status != MoneyRequestPending {
+			return ErrMoneyRequestNotPending
+		}
+		if !request.ExpiresAt.After(time.Now()) {
+			return ErrMoneyRequestExpired
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   request.ToAccountID,
+			Amount:        request.Amount,
+			Description:   request.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Request, err = q.DecideMoneyRequest(ctx, DecideMoneyRequestParams{
+			ID:         request.ID,
+			Status:     MoneyRequestAccepted,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionMoneyRequestAccept,
+			TargetType: AuditTargetMoneyRequest,
+			TargetID:   strconv.FormatInt(request.ID, 10),
+			Before:     request,
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomMoneyRequest(t *testing.T, payer Users, to Accounts, expiresAt time.Time) MoneyRequests {
+	arg := CreateMoneyRequestParams{
+		Requester:   to.Owner,
+		Payer:       payer.Username,
+		ToAccountID: to.ID,
+		Amount:      10,
+		Currency:    to.Currency,
+		Description: "Dinner",
+		ExpiresAt:   expiresAt,
+	}
+
+	request, err := testQueries.CreateMoneyRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, request.ID)
+	require.Equal(t, arg.Requester, request.Requester)
+	require.Equal(t, arg.Payer, request.Payer)
+	require.Equal(t, arg.ToAccountID, request.ToAccountID)
+	require.Equal(t, arg.Amount, request.Amount)
+	require.Equal(t, MoneyRequestPending, request.Status)
+	require.False(t, request.TransferID.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, request.ExpiresAt, time.Second)
+
+	return request
+}
+
+func TestAcceptMoneyRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	to := CreateRandomAccount(t)
+	from := updateOverdraftLimit(t, CreateRandomAccount(t), 1000)
+	payer, err := testQueries.GetUser(context.Background(), from.Owner)
+	require.NoError(t, err)
+
+	request := createRandomMoneyRequest(t, payer, to, time.Now().Add(time.Hour))
+
+	result, err := store.AcceptMoneyRequestTx(context.Background(), AcceptMoneyRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: from.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, MoneyRequestAccepted, result.Request.Status)
+	require.True(t, result.Request.DecidedAt.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Request.TransferID.Int64)
+	require.Equal(t, request.Amount, result.Transfer.Transfer.Amount)
+	require.Equal(t, request.Description, result.Transfer.Transfer.Description)
+	require.Equal(t, from.Balance-request.Amount, result.Transfer.FromAccount.Balance)
+
+	// a request is paid only once
+	_, err = store.AcceptMoneyRequestTx(context.Background(), AcceptMoneyRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: from.ID,
+	})
+	require.ErrorIs(t, err, ErrMoneyRequestNotPending)
+}
+
+func TestExpireMoneyRequests(t *testing.T) {
+	store := NewStore(testDB)
+	to := CreateRandomAccount(t)
+	from := updateOverdraftLimit(t, CreateRandomAccount(t), 1000)
+	payer, err := testQueries.GetUser(context.Background(), from.Owner)
+	require.NoError(t, err)
+
+	request := createRandomMoneyRequest(t, payer, to, time.Now().Add(-time.Minute))
+
+	// the expiry is checked even before the sweeper runs
+	_, err = store.AcceptMoneyRequestTx(context.Background(), AcceptMoneyRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: from.ID,
+	})
+	require.ErrorIs(t, err, ErrMoneyRequestExpired)
+
+	expired, err := testQueries.ExpireMoneyRequests(context.Background(), ExpireMoneyRequestsParams{
+		Now:     time.Now(),
+		MaxRows: 1000,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, r := range expired {
+		require.Equal(t, MoneyRequestExpired, r.Status)
+		found = found || r.ID == request.ID
+	}
+	require.True(t, found)
+
+	// a decided request can't be declined
+	_, err = testQueries.DecideMoneyRequest(context.Background(), DecideMoneyRequestParams{
+		ID:     request.ID,
+		Status: MoneyRequestDeclined,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
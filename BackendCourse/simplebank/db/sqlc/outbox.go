@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Outbox event types, named after what happened and, for transfers, which side of it the account is on.
+const (
+	EventTypeUserCreated      = "user.created"
+	EventTypeAccountCreated   = "account.created"
+	EventTypeTransferSent     = "transfer.sent"
+	EventTypeTransferReceived = "transfer.received"
+	EventTypeEntryCreated     = "entry.created"
+)
+
+// Outbox aggregate types. The events of one aggregate are published in the order they were written.
+const (
+	OutboxAggregateUser    = "user"
+	OutboxAggregateAccount = "account"
+)
+
+// TransferEvent is the payload of the transfer events: the transfer, seen from one of its accounts.
+// Account is that account after the transfer, and Entry is its entry for the transfer.
+type TransferEvent struct {
+	Transfer Transfers `json:"transfer"`
+	Entry    Entries   `json:"entry"`
+	Account  Accounts  `json:"account"`
+}
+
+// EntryEvent is the payload of the entry.created event, written for the entries that are not
+// one side of a transfer, such as deposits, interest and fees. Account is the account after the entry.
+type EntryEvent struct {
+	Entry   Entries  `json:"entry"`
+	Account Accounts `json:"account"`
+}
+
+// writeOutboxEvent writes a domain event to the outbox with the given Queries, so it is committed
+// together with the change it tells about, or not at all. The outbox relay publishes it later.
+func writeOutboxEvent(ctx context.Context, q *Queries, eventType string, aggregateType string, aggregateID string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+	})
+	return err
+}
+
+// writeEntryEvent writes an entry.created event for an entry that is not one side of a transfer.
+func writeEntryEvent(ctx context.Context, q *Queries, entry Entries, account Accounts) error {
+	return writeOutboxEvent(ctx, q, EventTypeEntryCreated, OutboxAggregateAccount,
+		strconv.FormatInt(account.ID, 10), EntryEvent{
+			Entry:   entry,
+			Account: account,
+		})
+}
+
+// writeTransferEvents writes a transfer.sent event for the from account and a transfer.received event
+// for the to account, each of them ordered with the other events of its account, followed by
+// the entry.created events of the transfer's fee, if it was charged one.
+// Every path that moves money with a transfer calls it in its own transaction.
+func writeTransferEvents(ctx context.Context, q *Queries, result TransferTxResult) error {
+	err := writeOutboxEvent(ctx, q, EventTypeTransferSent, OutboxAggregateAccount,
+		strconv.FormatInt(result.FromAccount.ID, 10), TransferEvent{
+			Transfer: result.Transfer,
+			Entry:    result.FromEntry,
+			Account:  result.FromAccount,
+		})
+	if err != nil {
+		return err
+	}
+
+	err = writeOutboxEvent(ctx, q, EventTypeTransferReceived, OutboxAggregateAccount,
+		strconv.FormatInt(result.ToAccount.ID, 10), TransferEvent{
+			Transfer: result.Transfer,
+			Entry:    result.ToEntry,
+			Account:  result.ToAccount,
+		})
+	if err != nil || result.Fee.Amount == 0 {
+		return err
+	}
+
+	err = writeEntryEvent(ctx, q, result.Fee.FromEntry, result.FromAccount)
+	if err != nil {
+		return err
+	}
+	return writeEntryEvent(ctx, q, result.Fee.ToEntry, result.Fee.FeeAccount)
+}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func listAccountOutboxEvents(t *testing.T, accountID int64) []OutboxEvents {
+	events, err := testQueries.ListAggregateOutboxEvents(context.Background(), ListAggregateOutboxEventsParams{
+		AggregateType: OutboxAggregateAccount,
+		AggregateID:   strconv.FormatInt(accountID, 10),
+		MaxRows:       10,
+	})
+	require.NoError(t, err)
+	return events
+}
+
+func TestOutboxEvents(t *testing.T) {
+	store := NewStore(testDB)
+	account2 := CreateRandomAccount(t)
+
+	user := CreateRandomUser(t)
+	created, err := store.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  100,
+			Currency: account2.Currency,
+			Type:     util.CheckingAccount,
+		},
+	})
+	require.NoError(t, err)
+	account1 := created.Account
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// the events of an account are listed in the order they were written
+	events1 := listAccountOutboxEvents(t, account1.ID)
+	require.Len(t, events1, 2)
+	require.Equal(t, EventTypeAccountCreated, events1[0].EventType)
+	require.Equal(t, EventTypeTransferSent, events1[1].EventType)
+	require.False(t, events1[1].PublishedAt.Valid)
+
+	var sent TransferEvent
+	require.NoError(t, json.Unmarshal(events1[1].Payload, &sent))
+	require.Equal(t, result.Transfer.ID, sent.Transfer.ID)
+	require.Equal(t, result.FromEntry.ID, sent.Entry.ID)
+	require.Equal(t, result.FromAccount.Balance, sent.Account.Balance)
+
+	events2 := listAccountOutboxEvents(t, account2.ID)
+	require.Len(t, events2, 1)
+	require.Equal(t, EventTypeTransferReceived, events2[0].EventType)
+
+	var received TransferEvent
+	require.NoError(t, json.Unmarshal(events2[0].Payload, &received))
+	require.Equal(t, result.ToEntry.ID, received.Entry.ID)
+	require.Equal(t, result.ToAccount.Balance, received.Account.Balance)
+
+	nextAttemptAt := time.Now().Add(time.Minute)
+	failed, err := testQueries.MarkOutboxEventFailed(context.Background(), MarkOutboxEventFailedParams{
+		ID:            events1[0].ID,
+		LastError:     "unreachable",
+		NextAttemptAt: nextAttemptAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, "unreachable", failed.LastError)
+	require.WithinDuration(t, nextAttemptAt, failed.NextAttemptAt, time.Second)
+	require.False(t, failed.PublishedAt.Valid)
+
+	published, err := testQueries.MarkOutboxEventPublished(context.Background(), events1[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), published.Attempts)
+	require.Empty(t, published.LastError)
+	require.True(t, published.PublishedAt.Valid)
+}
+
+func TestDepositAndCaptureOutboxEvents(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	deposit, err := store.DepositTx(context.Background(), DepositTxParams{
+		AccountID: account1.ID,
+		Amount:    50,
+	})
+	require.NoError(t, err)
+
+	hold, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	capture, err := store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		TransferID: hold.Transfer.ID,
+	})
+	require.NoError(t, err)
+
+	// placing the hold writes no entries, so no events either
+	events1 := listAccountOutboxEvents(t, account1.ID)
+	require.GreaterOrEqual(t, len(events1), 2)
+	require.Equal(t, EventTypeEntryCreated, events1[0].EventType)
+	require.Equal(t, EventTypeTransferSent, events1[1].EventType)
+
+	var deposited EntryEvent
+	require.NoError(t, json.Unmarshal(events1[0].Payload, &deposited))
+	require.Equal(t, deposit.Entry.ID, deposited.Entry.ID)
+	require.Equal(t, deposit.Account.Balance, deposited.Account.Balance)
+
+	events2 := listAccountOutboxEvents(t, account2.ID)
+	require.Len(t, events2, 1)
+	require.Equal(t, EventTypeTransferReceived, events2[0].EventType)
+
+	var received TransferEvent
+	require.NoError(t, json.Unmarshal(events2[0].Payload, &received))
+	require.Equal(t, capture.Transfer.ID, received.Transfer.ID)
+	require.Equal(t, capture.ToEntry.ID, received.Entry.ID)
+}
+
+func TestCreateUserTxOutboxEvent(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashedPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	result, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomOwner(),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomOwner(),
+			Email:          util.RandomEmail(),
+		},
+	})
+	require.NoError(t, err)
+
+	events, err := testQueries.ListAggregateOutboxEvents(context.Background(), ListAggregateOutboxEventsParams{
+		AggregateType: OutboxAggregateUser,
+		AggregateID:   result.User.Username,
+		MaxRows:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, EventTypeUserCreated, events[0].EventType)
+	require.Contains(t, string(events[0].Payload), result.User.Email)
+	require.NotContains(t, string(events[0].Payload), hashedPassword)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// ChargeOverdraftInterestTxParams contains the parameters for the ChargeOverdraftInterestTx function.
+type ChargeOverdraftInterestTxParams struct {
+	AccountID     int64     `json:"account_id"`
+	ChargeDate    time.Time `json:"charge_date"`
+	AnnualRateBps int64     `json:"annual_rate_bps"`
+}
+
+// ChargeOverdraftInterestTxResult contains the result of the ChargeOverdraftInterestTx function.
+// Charged is false when there was nothing to charge: the account is not overdrawn,
+// the interest rounds to zero, or it was already charged for that day.
+type ChargeOverdraftInterestTxResult struct {
+	Charged bool             `json:"charged"`
+	Account Accounts         `json:"account"`
+	Entry   Entries          `json:"entry"`
+	Charge  OverdraftCharges `json:"charge"`
+}
+
+// ChargeOverdraftInterestTx charges one day of overdraft interest to an account with a negative balance.
+// The interest is written as a fee entry on the account and recorded in overdraft_charges,
+// which allows at most one charge per account per day, so running the job twice on the same day is harmless.
+// The fee is charged even if it takes the balance below the overdraft limit.
+// Its entry.created outbox event is written in the same transaction.
+func (store *SQLStore) ChargeOverdraftInterestTx(ctx context.Context, arg ChargeOverdraftInterestTxParams) (ChargeOverdraftInterestTxResult, error) {
+	var result ChargeOverdraftInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// lock the account so the balance can't change under us and concurrent
+		// runs of the job for the same account are serialized
+		result.Account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetOverdraftCharge(ctx, GetOverdraftChargeParams{
+			AccountID:  arg.AccountID,
+			ChargeDate: arg.ChargeDate,
+		})
+		if err == nil {
+			// already charged for this day
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		balance := result.Account.Balance
+		if balance >= 0 {
+			return nil
+		}
+
+		// balance is negative, so is the interest
+		fee := -util.DailyInterest(balance, arg.AnnualRateBps)
+		if fee <= 0 {
+			return nil
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: arg.AccountID,
+			Amount:    -fee,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:      arg.AccountID,
+			Ammount: -fee,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the charge is the bank's interest income
+		_, err = postJournal(ctx, q, CreateJournalParams{
+			Category: JournalCategoryOverdraftInterest,
+			Currency: result.Account.Currency,
+		}, []Entries{result.Entry}, GLPosting{GLAccountCode: GLAccountInterestIncome, Amount: -fee})
+		if err != nil {
+			return err
+		}
+
+		result.Charge, err = q.CreateOverdraftCharge(ctx, CreateOverdraftChargeParams{
+			AccountID:  arg.AccountID,
+			EntryID:    result.Entry.ID,
+			ChargeDate: arg.ChargeDate,
+			Balance:    balance,
+			Amount:     fee,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeEntryEvent(ctx, q, result.Entry, result.Account)
+		if err != nil {
+			return err
+		}
+
+		result.Charged = true
+		return nil
+	})
+
+	return result, err
+}
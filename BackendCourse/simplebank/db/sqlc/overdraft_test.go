@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargeOverdraftInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+
+	balance := -util.RandomInt(100000, 1000000)
+	account, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: balance,
+	})
+	require.NoError(t, err)
+
+	arg := ChargeOverdraftInterestTxParams{
+		AccountID:     account.ID,
+		ChargeDate:    time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+		AnnualRateBps: 1825,
+	}
+	fee := -util.DailyInterest(balance, arg.AnnualRateBps)
+
+	result, err := store.ChargeOverdraftInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Charged)
+
+	require.Equal(t, balance-fee, result.Account.Balance)
+	require.Equal(t, account.ID, result.Entry.AccountID)
+	require.Equal(t, -fee, result.Entry.Amount)
+
+	require.Equal(t, account.ID, result.Charge.AccountID)
+	require.Equal(t, result.Entry.ID, result.Charge.EntryID)
+	require.Equal(t, balance, result.Charge.Balance)
+	require.Equal(t, fee, result.Charge.Amount)
+	require.True(t, arg.ChargeDate.Equal(result.Charge.ChargeDate))
+
+	charge, err := testQueries.GetOverdraftCharge(context.Background(), GetOverdraftChargeParams{
+		AccountID:  account.ID,
+		ChargeDate: arg.ChargeDate,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Charge.ID, charge.ID)
+
+	// a second run on the same day doesn't charge again
+	result, err = store.ChargeOverdraftInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.Charged)
+	require.Equal(t, balance-fee, result.Account.Balance)
+
+	// the next day does
+	arg.ChargeDate = arg.ChargeDate.AddDate(0, 0, 1)
+	result, err = store.ChargeOverdraftInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Charged)
+}
+
+func TestChargeOverdraftInterestTxPositiveBalance(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+
+	result, err := store.ChargeOverdraftInterestTx(context.Background(), ChargeOverdraftInterestTxParams{
+		AccountID:     account.ID,
+		ChargeDate:    time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+		AnnualRateBps: 1825,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Charged)
+	require.Equal(t, account.Balance, result.Account.Balance)
+}
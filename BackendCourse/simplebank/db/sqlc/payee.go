@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// PayeeCoolingOffError is returned when a transfer pays more than a payee's cooling-off max amount
+// to its account before its cooling-off period ends.
+type PayeeCoolingOffError struct {
+	CoolingOffUntil time.Time `json:"cooling_off_until"`
+	MaxAmount       int64     `json:"max_amount"`
+}
+
+func (e *PayeeCoolingOffError) Error() string {
+	return "payee was added recently, only small amounts can be paid to it until its cooling-off period ends"
+}
+
+// checkPayeeCoolingOff returns a *PayeeCoolingOffError if the owner of the from account has a payee for
+// the to account that is still cooling off and amount is above its max amount. It applies however the
+// account is addressed, so paying a new payee's account by its ID or an alias, in a batch or by a standing
+// order doesn't skip it.
+func checkPayeeCoolingOff(ctx context.Context, q *Queries, from Accounts, toAccountID int64, amount int64) error {
+	payee, err := q.GetCoolingOffPayee(ctx, GetCoolingOffPayeeParams{
+		Owner:       from.Owner,
+		ToAccountID: toAccountID,
+		Amount:      amount,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return &PayeeCoolingOffError{CoolingOffUntil: payee.CoolingOffUntil, MaxAmount: payee.CoolingOffMaxAmount}
+}
+
+// CreatePayeeTxParams contains the parameters for the CreatePayeeTx function.
+type CreatePayeeTxParams struct {
+	CreatePayeeParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreatePayeeTx saves a payee and records its audit event in the same transaction.
+func (store *SQLStore) CreatePayeeTx(ctx context.Context, arg CreatePayeeTxParams) (Payees, error) {
+	var result Payees
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreatePayee(ctx, arg.CreatePayeeParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionPayeeCreate,
+			TargetType: AuditTargetPayee,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UpdatePayeeNicknameTxParams contains the parameters for the UpdatePayeeNicknameTx function.
+type UpdatePayeeNicknameTxParams struct {
+	UpdatePayeeNicknameParams
+	Audit AuditMeta `json:"-"`
+}
+
+// UpdatePayeeNicknameTx renames a payee and records its audit event, with the payee before and after,
+// in the same transaction. It returns sql.ErrNoRows if the payee doesn't exist.
+func (store *SQLStore) UpdatePayeeNicknameTx(ctx context.Context, arg UpdatePayeeNicknameTxParams) (Payees, error) {
+	var result Payees
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetPayee(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.UpdatePayeeNickname(ctx, arg.UpdatePayeeNicknameParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionPayeeUpdate,
+			TargetType: AuditTargetPayee,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			Before:     before,
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeletePayeeTxParams contains the parameters for the DeletePayeeTx function.
+type DeletePayeeTxParams struct {
+	ID    int64     `json:"id"`
+	Audit AuditMeta `json:"-"`
+}
+
+// DeletePayeeTx deletes a payee and records its audit event, with the deleted payee, in the same transaction.
+// It returns sql.ErrNoRows if the payee doesn't exist.
+func (store *SQLStore) DeletePayeeTx(ctx context.Context, arg DeletePayeeTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetPayee(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeletePayee(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionPayeeDelete,
+			TargetType: AuditTargetPayee,
+			TargetID:   strconv.FormatInt(before.ID, 10),
+			Before:     before,
+		})
+		return err
+	})
+}
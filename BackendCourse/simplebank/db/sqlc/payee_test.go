@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPayee(t *testing.T, owner Users, to Accounts) Payees {
+	arg := CreatePayeeParams{
+		Owner:               owner.Username,
+		Nickname:            util.RandomString(8),
+		ToAccountID:         to.ID,
+		Currency:            to.Currency,
+		CoolingOffUntil:     time.Now().Add(time.Hour),
+		CoolingOffMaxAmount: 10,
+	}
+
+	payee, err := testQueries.CreatePayee(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, payee.ID)
+	require.Equal(t, arg.Owner, payee.Owner)
+	require.Equal(t, arg.Nickname, payee.Nickname)
+	require.Equal(t, arg.ToAccountID, payee.ToAccountID)
+	require.Equal(t, arg.Currency, payee.Currency)
+	require.WithinDuration(t, arg.CoolingOffUntil, payee.CoolingOffUntil, time.Second)
+	require.Equal(t, arg.CoolingOffMaxAmount, payee.CoolingOffMaxAmount)
+
+	return payee
+}
+
+func TestPayees(t *testing.T) {
+	owner := CreateRandomUser(t)
+	to := CreateRandomAccount(t)
+	payee1 := createRandomPayee(t, owner, to)
+	payee2 := createRandomPayee(t, owner, to)
+
+	// nicknames are unique per owner
+	_, err := testQueries.UpdatePayeeNickname(context.Background(), UpdatePayeeNicknameParams{
+		ID:       payee2.ID,
+		Nickname: payee1.Nickname,
+	})
+	require.Error(t, err)
+
+	payee2, err = testQueries.UpdatePayeeNickname(context.Background(), UpdatePayeeNicknameParams{
+		ID:       payee2.ID,
+		Nickname: "aaa " + payee1.Nickname,
+	})
+	require.NoError(t, err)
+
+	payees, err := testQueries.ListPayees(context.Background(), ListPayeesParams{
+		Owner: owner.Username,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Payees{payee2, payee1}, payees)
+
+	err = testQueries.DeletePayee(context.Background(), payee1.ID)
+	require.NoError(t, err)
+
+	payees, err = testQueries.ListPayees(context.Background(), ListPayeesParams{
+		Owner: owner.Username,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Payees{payee2}, payees)
+}
+
+func TestTransferTxPayeeCoolingOff(t *testing.T) {
+	store := NewStore(testDB)
+	from, to := createBatchAccounts(t, 1)
+	owner, err := testQueries.GetUser(context.Background(), from.Owner)
+	require.NoError(t, err)
+	payee := createRandomPayee(t, owner, to[0])
+
+	// the payee's account is limited however it is paid, not only when addressed by the payee
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to[0].ID,
+		Amount:        payee.CoolingOffMaxAmount + 1,
+	})
+	var coolingOffErr *PayeeCoolingOffError
+	require.True(t, errors.As(err, &coolingOffErr))
+	require.Equal(t, payee.CoolingOffMaxAmount, coolingOffErr.MaxAmount)
+	require.WithinDuration(t, payee.CoolingOffUntil, coolingOffErr.CoolingOffUntil, time.Second)
+
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		InitiatedBy:   from.Owner,
+		FromAccountID: from.ID,
+		Currency:      from.Currency,
+		Mode:          BatchModeBestEffort,
+		Legs:          []BatchLeg{{ToAccountID: to[0].ID, Amount: payee.CoolingOffMaxAmount + 1}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, BatchLegStatusFailed, result.Legs[0].Status)
+	require.Equal(t, coolingOffErr.Error(), result.Legs[0].Error.String)
+
+	// up to the max amount can be paid
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to[0].ID,
+		Amount:        payee.CoolingOffMaxAmount,
+	})
+	require.NoError(t, err)
+}
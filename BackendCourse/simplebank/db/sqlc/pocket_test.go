@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPocket(t *testing.T, parent Accounts) Accounts {
+	arg := CreatePocketParams{
+		Owner:           parent.Owner,
+		Currency:        parent.Currency,
+		ParentAccountID: parent.ID,
+		Name:            util.RandomString(8),
+		TargetAmount:    sql.NullInt64{Int64: 1000, Valid: true},
+	}
+
+	pocket, err := testQueries.CreatePocket(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, pocket.ID)
+	require.Equal(t, util.PocketAccount, pocket.Type)
+	require.Equal(t, arg.Owner, pocket.Owner)
+	require.Equal(t, arg.Currency, pocket.Currency)
+	require.Equal(t, sql.NullInt64{Int64: parent.ID, Valid: true}, pocket.ParentAccountID)
+	require.Equal(t, arg.Name, pocket.Name)
+	require.Equal(t, arg.TargetAmount, pocket.TargetAmount)
+	require.Zero(t, pocket.Balance)
+
+	return pocket
+}
+
+func TestPockets(t *testing.T) {
+	parent := CreateRandomAccount(t)
+	pocket1 := createRandomPocket(t, parent)
+	pocket2 := createRandomPocket(t, parent)
+
+	// names are unique per account
+	_, err := testQueries.UpdatePocket(context.Background(), UpdatePocketParams{
+		ID:   pocket2.ID,
+		Name: sql.NullString{String: pocket1.Name, Valid: true},
+	})
+	require.Error(t, err)
+
+	pocket2, err = testQueries.UpdatePocket(context.Background(), UpdatePocketParams{
+		ID:           pocket2.ID,
+		TargetAmount: sql.NullInt64{Int64: 5000, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), pocket2.TargetAmount.Int64)
+
+	// only pockets can be updated
+	_, err = testQueries.UpdatePocket(context.Background(), UpdatePocketParams{
+		ID:   parent.ID,
+		Name: sql.NullString{String: "parent", Valid: true},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	pockets, err := testQueries.ListPockets(context.Background(), parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Accounts{pocket1, pocket2}, pockets)
+}
+
+func TestPocketMoves(t *testing.T) {
+	store := NewStore(testDB)
+	parent := CreateRandomAccount(t)
+	pocket := createRandomPocket(t, parent)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: parent.ID,
+		ToAccountID:   pocket.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Fee)
+	require.Equal(t, parent.Balance-10, result.FromAccount.Balance)
+	require.Equal(t, int64(10), result.ToAccount.Balance)
+
+	total, err := testQueries.GetPocketsBalance(context.Background(), parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), total)
+
+	// a pocket has no overdraft
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: pocket.ID,
+		ToAccountID:   parent.ID,
+		Amount:        11,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: pocket.ID,
+		ToAccountID:   parent.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.FromAccount.Balance)
+	require.Equal(t, parent.Balance, result.ToAccount.Balance)
+}
@@ -6,22 +6,165 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	// Places (negative amount) or releases (positive amount) a hold without touching the ledger balance.
+	AddAccountAvailableBalance(ctx context.Context, arg AddAccountAvailableBalanceParams) (Accounts, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Accounts, error)
+	AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfers, error)
+	AdvanceStandingOrder(ctx context.Context, arg AdvanceStandingOrderParams) (StandingOrders, error)
+	CancelStandingOrder(ctx context.Context, id int64) (StandingOrders, error)
+	CaptureTransfer(ctx context.Context, arg CaptureTransferParams) (Transfers, error)
+	// Adds the late fee to the unpaid installments that were due before the cutoff and weren't charged one yet.
+	ChargeLoanLateFees(ctx context.Context, arg ChargeLoanLateFeesParams) ([]LoanInstallments, error)
+	// Locks one due order, skipping those already claimed by another replica.
+	ClaimDueStandingOrder(ctx context.Context, now time.Time) (StandingOrders, error)
+	// Claims the oldest unpublished event of each aggregate that is due, until the claim expires.
+	// An event is only claimed once every earlier event of its aggregate is published,
+	// so the events of an aggregate are published one at a time and in order.
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvents, error)
+	// Claims pending deliveries that are due, until the claim expires.
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDeliveries, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Accounts, error)
+	CreateAccountApprover(ctx context.Context, arg CreateAccountApproverParams) (AccountApprovers, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvents, error)
+	// Snapshots the end of day balance of every account that existed at the end of the day,
+	// by undoing the entries made since. Accounts already snapshotted for the day are skipped.
+	CreateBalanceSnapshots(ctx context.Context, arg CreateBalanceSnapshotsParams) (int64, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entries, error)
+	// Posts an entry to the GL account of its bank account. The entry's amount is what the bank owes
+	// the account, so crediting the account is a negative posting.
+	CreateEntryPosting(ctx context.Context, arg CreateEntryPostingParams) (Postings, error)
+	CreateFeeRule(ctx context.Context, arg CreateFeeRuleParams) (FeeRules, error)
+	// Posts straight to a GL account, without a bank account, e.g. to cash.
+	CreateGLPosting(ctx context.Context, arg CreateGLPostingParams) (Postings, error)
+	// Returns no rows if the account already accrued interest for that day.
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccruals, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPostings, error)
+	CreateInterestRate(ctx context.Context, arg CreateInterestRateParams) (InterestRates, error)
+	CreateJournal(ctx context.Context, arg CreateJournalParams) (Journals, error)
+	CreateLoan(ctx context.Context, arg CreateLoanParams) (Loans, error)
+	CreateLoanInstallment(ctx context.Context, arg CreateLoanInstallmentParams) (LoanInstallments, error)
+	CreateMoneyRequest(ctx context.Context, arg CreateMoneyRequestParams) (MoneyRequests, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvents, error)
+	CreateOverdraftCharge(ctx context.Context, arg CreateOverdraftChargeParams) (OverdraftCharges, error)
+	CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payees, error)
+	CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (Transfers, error)
+	CreatePocket(ctx context.Context, arg CreatePocketParams) (Accounts, error)
+	CreateStandingOrder(ctx context.Context, arg CreateStandingOrderParams) (StandingOrders, error)
+	CreateStandingOrderExecution(ctx context.Context, arg CreateStandingOrderExecutionParams) (StandingOrderExecutions, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfers, error)
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatches, error)
+	CreateTransferBatchLeg(ctx context.Context, arg CreateTransferBatchLegParams) (TransferBatchLegs, error)
+	CreateTransferFee(ctx context.Context, arg CreateTransferFeeParams) (TransferFees, error)
+	CreateTransferLimit(ctx context.Context, arg CreateTransferLimitParams) (TransferLimits, error)
+	CreateTransferRequest(ctx context.Context, arg CreateTransferRequestParams) (TransferRequests, error)
+	CreateTransferRequestDecision(ctx context.Context, arg CreateTransferRequestDecisionParams) (TransferRequestDecisions, error)
+	CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (TransferReversals, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
+	// An event is delivered to an endpoint once, however many times it is published.
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempts, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoints, error)
+	// Only pending requests can be decided, so no rows means it was already decided or expired.
+	DecideMoneyRequest(ctx context.Context, arg DecideMoneyRequestParams) (MoneyRequests, error)
+	DecideTransferRequest(ctx context.Context, arg DecideTransferRequestParams) (TransferRequests, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountApprover(ctx context.Context, arg DeleteAccountApproverParams) error
+	DeleteFeeRule(ctx context.Context, id int64) error
+	DeletePayee(ctx context.Context, id int64) error
+	DeleteTransferLimit(ctx context.Context, id int64) error
+	DeleteWebhookEndpoint(ctx context.Context, id int64) error
+	ExpireMoneyRequests(ctx context.Context, arg ExpireMoneyRequestsParams) ([]MoneyRequests, error)
+	FinishStandingOrderExecution(ctx context.Context, arg FinishStandingOrderExecutionParams) (StandingOrderExecutions, error)
+	FinishTransferBatch(ctx context.Context, arg FinishTransferBatchParams) (TransferBatches, error)
 	GetAccount(ctx context.Context, id int64) (Accounts, error)
+	GetAccountApprover(ctx context.Context, arg GetAccountApproverParams) (AccountApprovers, error)
+	// Returns the balance the account had at the given time, by undoing the entries made since.
+	GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error)
+	// Returns the owner's checking account in the currency, which is the one paid when the owner is paid by an alias.
+	// An owner has at most one account of each type per currency, see the accounts_owner_currency_type_key index.
+	GetAccountByOwnerCurrency(ctx context.Context, arg GetAccountByOwnerCurrencyParams) (Accounts, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Accounts, error)
+	// The most specific rule wins: a rule for the exact currency beats a rule for any currency,
+	// and then a rule for the exact account type beats a rule for any type.
+	// Among equally specific rules the newest one is used.
+	GetApplicableFeeRule(ctx context.Context, arg GetApplicableFeeRuleParams) (FeeRules, error)
+	// The limits of the account itself win over the limits of its owner's tier.
+	GetApplicableTransferLimit(ctx context.Context, arg GetApplicableTransferLimitParams) (TransferLimits, error)
+	GetAuditEvent(ctx context.Context, id int64) (AuditEvents, error)
+	GetCoolingOffPayee(ctx context.Context, arg GetCoolingOffPayeeParams) (Payees, error)
+	// Counts the settled transfers and sums the amounts they moved per UTC day and currency.
+	GetDailyTransferVolume(ctx context.Context, arg GetDailyTransferVolumeParams) ([]GetDailyTransferVolumeRow, error)
+	// Sums the balances of the customers' accounts, pockets included, per currency.
+	GetDepositTotals(ctx context.Context) ([]GetDepositTotalsRow, error)
 	GetEntry(ctx context.Context, id int64) (Entries, error)
+	GetFeeRule(ctx context.Context, id int64) (FeeRules, error)
+	GetInterestPosting(ctx context.Context, arg GetInterestPostingParams) (InterestPostings, error)
+	// Returns the rate of the product in effect on the day, the latest one that took effect by then.
+	GetInterestRate(ctx context.Context, arg GetInterestRateParams) (InterestRates, error)
+	GetJournal(ctx context.Context, id int64) (Journals, error)
+	// Returns the ID of the last event of an aggregate, 0 if it has none.
+	GetLastAggregateOutboxEventID(ctx context.Context, arg GetLastAggregateOutboxEventIDParams) (int64, error)
+	GetLastAuditEvent(ctx context.Context) (AuditEvents, error)
+	// Returns the account's last snapshot of a day before the given date.
+	GetLatestBalanceSnapshot(ctx context.Context, arg GetLatestBalanceSnapshotParams) (BalanceSnapshots, error)
+	GetLoan(ctx context.Context, id int64) (Loans, error)
+	GetLoanForUpdate(ctx context.Context, id int64) (Loans, error)
+	GetLoanInstallmentForUpdate(ctx context.Context, id int64) (LoanInstallments, error)
+	GetMoneyRequest(ctx context.Context, id int64) (MoneyRequests, error)
+	GetMoneyRequestForUpdate(ctx context.Context, id int64) (MoneyRequests, error)
+	// Sums the money that came into and left the customers' accounts per currency and category.
+	GetNetFlows(ctx context.Context, arg GetNetFlowsParams) ([]GetNetFlowsRow, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvents, error)
+	// Sums what left the account since the given time: captured transfers count what was captured
+	// and pending holds what they hold. Reversals are corrections by the bank, not payments, so they don't count.
+	// Neither do payments to the bank's own accounts nor moves between the account and its pockets or parent,
+	// which aren't limited either.
+	GetOutgoingTransferTotals(ctx context.Context, arg GetOutgoingTransferTotalsParams) (GetOutgoingTransferTotalsRow, error)
+	GetOverdraftCharge(ctx context.Context, arg GetOverdraftChargeParams) (OverdraftCharges, error)
+	GetPayee(ctx context.Context, id int64) (Payees, error)
+	// Returns the total balance of the pockets of the account.
+	GetPocketsBalance(ctx context.Context, parentAccountID int64) (int64, error)
+	GetStandingOrder(ctx context.Context, id int64) (StandingOrders, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (SystemAccounts, error)
 	GetTransfer(ctx context.Context, id int64) (Transfers, error)
+	GetTransferBatch(ctx context.Context, id int64) (TransferBatches, error)
+	GetTransferFee(ctx context.Context, transferID int64) (TransferFees, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfers, error)
+	GetTransferLimit(ctx context.Context, id int64) (TransferLimits, error)
+	GetTransferRequest(ctx context.Context, id int64) (TransferRequests, error)
+	GetTransferRequestForUpdate(ctx context.Context, id int64) (TransferRequests, error)
+	GetTransferReversal(ctx context.Context, transferID int64) (TransferReversals, error)
+	// Sums the postings of every GL account per currency; debits are positive and credits negative,
+	// so the balances of a currency sum to zero.
+	GetTrialBalance(ctx context.Context) ([]GetTrialBalanceRow, error)
 	GetUser(ctx context.Context, username string) (Users, error)
+	GetUserByEmail(ctx context.Context, email string) (Users, error)
+	GetUserForUpdate(ctx context.Context, username string) (Users, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDeliveries, error)
+	GetWebhookDeliveryForUpdate(ctx context.Context, id int64) (WebhookDeliveries, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoints, error)
+	ListAccountApprovers(ctx context.Context, accountID int64) ([]AccountApprovers, error)
 	// Tell SQL that Key is not updated in this transaction
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Accounts, error)
+	// Lists the events of an aggregate in order, from the one after the given ID.
+	ListAggregateOutboxEvents(ctx context.Context, arg ListAggregateOutboxEventsParams) ([]OutboxEvents, error)
+	// Lists the pending requests the approver can decide on, which excludes the ones they initiated.
+	ListApprovableTransferRequests(ctx context.Context, arg ListApprovableTransferRequestsParams) ([]TransferRequests, error)
+	// Lists the audit events matching the filters that are given, newest first.
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvents, error)
+	// Lists the audit events in chain order, from the one after the given ID.
+	ListAuditEventsAfter(ctx context.Context, arg ListAuditEventsAfterParams) ([]AuditEvents, error)
+	// Lists the accounts whose balance differs from the sum of their entries.
+	ListBalanceDrifts(ctx context.Context) ([]ListBalanceDriftsRow, error)
+	// Sums the account's entries per UTC day.
+	ListDailyEntryTotals(ctx context.Context, arg ListDailyEntryTotalsParams) ([]ListDailyEntryTotalsRow, error)
+	// Lists the unpaid installments due by the day in order, from the one after the given ID,
+	// so the ones that can't be collected don't keep being listed before the others.
+	ListDueLoanInstallments(ctx context.Context, arg ListDueLoanInstallmentsParams) ([]LoanInstallments, error)
 	// This query retrieves a list of entries from the "entries" table that belong to a specific account (filtered by account_id).
 	// The results are ordered by the "id" column in ascending order.
 	// The "LIMIT $2" clause restricts the number of rows returned to the value specified by the second parameter.
@@ -33,8 +176,80 @@ type Querier interface {
 	// OFFSET $3: Skips the first $3 rows, useful for implementing pagination.
 	// This query is commonly used in applications to fetch a subset of data for a specific account, often for displaying paginated results in a UI.
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entries, error)
+	// Lists the entries of the account made in the time range, oldest first.
+	ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entries, error)
+	ListExpiredPendingTransfers(ctx context.Context, arg ListExpiredPendingTransfersParams) ([]Transfers, error)
+	ListFeeRules(ctx context.Context, arg ListFeeRulesParams) ([]FeeRules, error)
+	ListGLAccounts(ctx context.Context) ([]GlAccounts, error)
+	ListIncomingMoneyRequests(ctx context.Context, arg ListIncomingMoneyRequestsParams) ([]MoneyRequests, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccruals, error)
+	// Lists the accounts of the products that had an interest rate in effect on the day.
+	ListInterestBearingAccounts(ctx context.Context, onDate time.Time) ([]Accounts, error)
+	ListInterestRates(ctx context.Context, arg ListInterestRatesParams) ([]InterestRates, error)
+	ListJournalPostings(ctx context.Context, journalID int64) ([]Postings, error)
+	ListLoanInstallments(ctx context.Context, loanID int64) ([]LoanInstallments, error)
+	ListLoans(ctx context.Context, arg ListLoansParams) ([]Loans, error)
+	ListOutgoingMoneyRequests(ctx context.Context, arg ListOutgoingMoneyRequestsParams) ([]MoneyRequests, error)
+	// Lists what left the account since the given time, oldest first, counted like GetOutgoingTransferTotals.
+	ListOutgoingTransferAmounts(ctx context.Context, arg ListOutgoingTransferAmountsParams) ([]ListOutgoingTransferAmountsRow, error)
+	ListOverdrawnAccounts(ctx context.Context) ([]Accounts, error)
+	// Lists all the accounts of the owner, pockets included.
+	ListOwnerAccounts(ctx context.Context, owner string) ([]Accounts, error)
+	ListPayees(ctx context.Context, arg ListPayeesParams) ([]Payees, error)
+	ListPockets(ctx context.Context, parentAccountID int64) ([]Accounts, error)
+	ListStandingOrderExecutions(ctx context.Context, arg ListStandingOrderExecutionsParams) ([]StandingOrderExecutions, error)
+	ListStandingOrders(ctx context.Context, arg ListStandingOrdersParams) ([]StandingOrders, error)
+	// Lists the endpoints of the owner that the events of the type are delivered to.
+	ListSubscribedWebhookEndpoints(ctx context.Context, arg ListSubscribedWebhookEndpointsParams) ([]WebhookEndpoints, error)
+	ListTopAccountsByBalance(ctx context.Context, arg ListTopAccountsByBalanceParams) ([]ListTopAccountsByBalanceRow, error)
+	ListTransferBatchLegs(ctx context.Context, batchID int64) ([]TransferBatchLegs, error)
+	// Lists the transfers that don't have exactly one entry taking the captured amount
+	// from the from account and one giving it to the to account, netting to zero.
+	// Transfers that didn't move money, i.e. pending, voided or expired holds, must have no entries.
+	ListTransferEntryMismatches(ctx context.Context) ([]ListTransferEntryMismatchesRow, error)
+	// Lists the journals of a transfer: the transfer itself, its fee and its captures.
+	ListTransferJournals(ctx context.Context, transferID int64) ([]Journals, error)
+	ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]TransferLimits, error)
+	ListTransferRequestDecisions(ctx context.Context, transferRequestID int64) ([]TransferRequestDecisions, error)
+	ListTransferReversals(ctx context.Context, originalTransferID int64) ([]TransferReversals, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfers, error)
+	// Lists the accounts with interest accrued between the two days, from inclusive and to exclusive, that wasn't posted yet.
+	ListUnpostedInterestAccounts(ctx context.Context, arg ListUnpostedInterestAccountsParams) ([]int64, error)
+	// Lists the deliveries to an endpoint, newest first, of any status unless one is given.
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDeliveries, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]WebhookDeliveryAttempts, error)
+	ListWebhookEndpoints(ctx context.Context, owner string) ([]WebhookEndpoints, error)
+	// Serializes the appends to the audit chain until the transaction ends,
+	// so every event is chained to the one committed right before it.
+	LockAuditChain(ctx context.Context) error
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) error
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) (OutboxEvents, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) (OutboxEvents, error)
+	// Returns no rows if the installment was already paid.
+	PayLoanInstallment(ctx context.Context, arg PayLoanInstallmentParams) (LoanInstallments, error)
+	// Marks the loan repaid once no principal is left.
+	RepayLoanPrincipal(ctx context.Context, arg RepayLoanPrincipalParams) (Loans, error)
+	// Only failed deliveries are replayed, so no rows means it is still pending or it succeeded.
+	ReplayWebhookDelivery(ctx context.Context, id int64) (WebhookDeliveries, error)
+	// Lists the entries of the account, optionally only the ones of a category
+	// and the ones whose description or reference contain the search text, ignoring case.
+	SearchEntries(ctx context.Context, arg SearchEntriesParams) ([]Entries, error)
+	// Lists the transfers into or out of the account, optionally only the ones of a category
+	// and the ones whose description or reference contain the search text, ignoring case.
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfers, error)
+	SumEntriesBetween(ctx context.Context, arg SumEntriesBetweenParams) (int64, error)
+	SumUnpostedInterestAccruals(ctx context.Context, arg SumUnpostedInterestAccrualsParams) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Accounts, error)
+	UpdateAccountApprovalThreshold(ctx context.Context, arg UpdateAccountApprovalThresholdParams) (Accounts, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Accounts, error)
+	UpdatePayeeNickname(ctx context.Context, arg UpdatePayeeNicknameParams) (Payees, error)
+	// Fields left NULL are not changed.
+	UpdatePocket(ctx context.Context, arg UpdatePocketParams) (Accounts, error)
+	UpdateStandingOrder(ctx context.Context, arg UpdateStandingOrderParams) (StandingOrders, error)
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfers, error)
+	UpdateUserTier(ctx context.Context, arg UpdateUserTierParams) (Users, error)
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg UpdateWebhookDeliveryAttemptParams) (WebhookDeliveries, error)
+	VerifyUserEmail(ctx context.Context, username string) (Users, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliation(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := updateOverdraftLimit(t, CreateRandomAccount(t), 1000)
+	account2 := CreateRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, result.Transfer.ID, result.ToEntry.TransferID.Int64)
+
+	// setting the balance directly makes it drift from the entries
+	_, err = testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account1.ID,
+		Balance: 12345,
+	})
+	require.NoError(t, err)
+
+	drifts, err := testQueries.ListBalanceDrifts(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, drifts, ListBalanceDriftsRow{
+		ID:             account1.ID,
+		Owner:          account1.Owner,
+		Currency:       account1.Currency,
+		Balance:        12345,
+		EntriesBalance: -10,
+	})
+
+	// the transfer's entries match it
+	mismatches, err := testQueries.ListTransferEntryMismatches(context.Background())
+	require.NoError(t, err)
+	for _, mismatch := range mismatches {
+		require.NotEqual(t, result.Transfer.ID, mismatch.ID)
+	}
+}
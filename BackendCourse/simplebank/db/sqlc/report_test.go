@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReports(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+
+	_, err := store.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 300})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	flows, err := testQueries.GetNetFlows(context.Background(), GetNetFlowsParams{
+		FromTime: now.Add(-time.Hour),
+		ToTime:   now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	var deposits *GetNetFlowsRow
+	for i := range flows {
+		if flows[i].Currency == account.Currency && flows[i].Category == EntryCategoryDeposit {
+			deposits = &flows[i]
+		}
+	}
+	require.NotNil(t, deposits)
+	require.GreaterOrEqual(t, deposits.Inflow, int64(300))
+	require.Equal(t, deposits.Inflow-deposits.Outflow, deposits.NetFlow)
+
+	top, err := testQueries.ListTopAccountsByBalance(context.Background(), ListTopAccountsByBalanceParams{
+		Currency: account.Currency,
+		MaxRows:  5,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, top)
+	for i := 1; i < len(top); i++ {
+		require.GreaterOrEqual(t, top[i-1].Balance, top[i].Balance)
+	}
+
+	totals, err := testQueries.GetDepositTotals(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, totals)
+
+	// every currency of the trial balance sums to zero
+	balances, err := testQueries.GetTrialBalance(context.Background())
+	require.NoError(t, err)
+	sums := map[string]int64{}
+	for _, balance := range balances {
+		sums[balance.Currency] += balance.Balance
+	}
+	for currency, sum := range sums {
+		require.Zero(t, sum, currency)
+	}
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrTransferNotReversible is returned when reversing a transfer that didn't move money,
+	// such as a pending or voided hold, or that is itself a reversal.
+	ErrTransferNotReversible = errors.New("transfer can't be reversed")
+	// ErrReversalExceedsTransfer is returned when a reversal is larger than the part of the transfer not yet reversed.
+	ErrReversalExceedsTransfer = errors.New("reversal amount exceeds the unreversed amount of the transfer")
+)
+
+// ReverseTransferTxParams contains the parameters for the ReverseTransferTx function.
+// An Amount of 0 reverses whatever is left of the transfer.
+type ReverseTransferTxParams struct {
+	TransferID  int64     `json:"transfer_id"`
+	Amount      int64     `json:"amount"`
+	InitiatedBy string    `json:"initiated_by"`
+	Reason      string    `json:"reason"`
+	Audit       AuditMeta `json:"-"`
+}
+
+// ReverseTransferTxResult contains the result of the ReverseTransferTx function.
+// Transfer is the compensating transfer, going from the original to account back to the original from account.
+type ReverseTransferTxResult struct {
+	OriginalTransfer Transfers         `json:"original_transfer"`
+	Reversal         TransferReversals `json:"reversal"`
+	Transfer         Transfers         `json:"transfer"`
+	FromAccount      Accounts          `json:"from_account"`
+	ToAccount        Accounts          `json:"to_account"`
+	FromEntry        Entries           `json:"from_entry"`
+	ToEntry          Entries           `json:"to_entry"`
+}
+
+// ReverseTransferTx undoes a settled transfer, in full or in part, with a compensating transfer
+// that has its own entries and is linked to the original by a transfer_reversals row.
+// The original transfer row is locked first, so concurrent reversals can't reverse more than was captured.
+// Fees charged on the original transfer are not refunded.
+// If the original recipient can't cover the reversal within its overdraft limit, it returns ErrInsufficientFunds.
+// The compensating transfer's outbox events and the reversal's audit event, on the original transfer,
+// are recorded in the same transaction.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if original.Status != TransferStatusCaptured {
+			return ErrTransferNotReversible
+		}
+		_, err = q.GetTransferReversal(ctx, original.ID)
+		if err == nil {
+			return ErrTransferNotReversible
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		remaining := original.CapturedAmount - original.ReversedAmount
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount <= 0 || amount > remaining {
+			return ErrReversalExceedsTransfer
+		}
+
+		result.OriginalTransfer, err = q.AddTransferReversedAmount(ctx, AddTransferReversedAmountParams{
+			ID:     original.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		fromAccountID, toAccountID := original.ToAccountID, original.FromAccountID
+
+		// the reversal keeps the original reference and category, so it can be matched with the original payment
+		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Amount:        amount,
+			Reference:     original.Reference,
+			Category:      original.Category,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  fromAccountID,
+			Amount:     -amount,
+			Reference:  original.Reference,
+			Category:   original.Category,
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  toAccountID,
+			Amount:     amount,
+			Reference:  original.Reference,
+			Category:   original.Category,
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// same lock order as TransferTx
+		if fromAccountID < toAccountID {
+			result.FromAccount, result.ToAccount, err = addMonney(ctx, q, fromAccountID, -amount, toAccountID, amount)
+		} else {
+			result.ToAccount, result.FromAccount, err = addMonney(ctx, q, toAccountID, amount, fromAccountID, -amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = postJournal(ctx, q, transferJournal(JournalCategoryReversal, result.Transfer, result.FromAccount.Currency),
+			[]Entries{result.FromEntry, result.ToEntry})
+		if err != nil {
+			return err
+		}
+
+		result.Reversal, err = q.CreateTransferReversal(ctx, CreateTransferReversalParams{
+			TransferID:         result.Transfer.ID,
+			OriginalTransferID: original.ID,
+			Amount:             amount,
+			InitiatedBy:        arg.InitiatedBy,
+			Reason:             arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := checkOverdraft(result.FromAccount); err != nil {
+			return err
+		}
+
+		err = writeTransferEvents(ctx, q, TransferTxResult{
+			Transfer:    result.Transfer,
+			FromAccount: result.FromAccount,
+			ToAccount:   result.ToAccount,
+			FromEntry:   result.FromEntry,
+			ToEntry:     result.ToEntry,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferReverse,
+			TargetType: AuditTargetTransfer,
+			TargetID:   strconv.FormatInt(original.ID, 10),
+			Before:     original,
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	banker := CreateRandomUser(t)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// partial reversal
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  original.Transfer.ID,
+		Amount:      30,
+		InitiatedBy: banker.Username,
+		Reason:      "partial refund",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(30), result.OriginalTransfer.ReversedAmount)
+	require.Equal(t, account2.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, result.Transfer.ToAccountID)
+	require.Equal(t, int64(30), result.Transfer.Amount)
+	require.Equal(t, -int64(30), result.FromEntry.Amount)
+	require.Equal(t, int64(30), result.ToEntry.Amount)
+	require.Equal(t, original.FromAccount.Balance+30, result.ToAccount.Balance)
+	require.Equal(t, original.ToAccount.Balance-30, result.FromAccount.Balance)
+
+	require.Equal(t, result.Transfer.ID, result.Reversal.TransferID)
+	require.Equal(t, original.Transfer.ID, result.Reversal.OriginalTransferID)
+	require.Equal(t, banker.Username, result.Reversal.InitiatedBy)
+	require.Equal(t, "partial refund", result.Reversal.Reason)
+
+	// can't reverse more than what's left
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  original.Transfer.ID,
+		Amount:      71,
+		InitiatedBy: banker.Username,
+		Reason:      "too much",
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// the rest
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  original.Transfer.ID,
+		InitiatedBy: banker.Username,
+		Reason:      "full refund",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), result.Transfer.Amount)
+	require.Equal(t, int64(100), result.OriginalTransfer.ReversedAmount)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  original.Transfer.ID,
+		InitiatedBy: banker.Username,
+		Reason:      "again",
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// a reversal can't be reversed
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  result.Transfer.ID,
+		InitiatedBy: banker.Username,
+		Reason:      "undo the refund",
+	})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+
+	reversals, err := store.ListTransferReversals(context.Background(), original.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, reversals, 2)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	banker := CreateRandomUser(t)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+	updateOverdraftLimit(t, account2, 1000)
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// five reversals of 30 race for 100, only three can succeed
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID:  original.Transfer.ID,
+				Amount:      30,
+				InitiatedBy: banker.Username,
+				Reason:      "concurrent",
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+	}
+	require.Equal(t, 3, succeeded)
+
+	transfer, err := store.GetTransfer(context.Background(), original.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), transfer.ReversedAmount)
+}
+
+func TestReverseTransferTxPendingHold(t *testing.T) {
+	store := NewStore(testDB)
+	banker := CreateRandomUser(t)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	account1 = updateOverdraftLimit(t, account1, 1000)
+
+	hold := createRandomHold(t, store, account1, account2, 100)
+
+	_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  hold.Transfer.ID,
+		InitiatedBy: banker.Username,
+		Reason:      "not settled yet",
+	})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+}
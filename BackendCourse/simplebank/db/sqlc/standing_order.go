@@ -0,0 +1,232 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Standing order frequencies. An order runs every IntervalCount periods of its frequency.
+const (
+	StandingOrderDaily   = "daily"
+	StandingOrderWeekly  = "weekly"
+	StandingOrderMonthly = "monthly"
+)
+
+// Standing order statuses. Only active orders are run.
+const (
+	StandingOrderStatusActive    = "active"
+	StandingOrderStatusCancelled = "cancelled"
+	StandingOrderStatusCompleted = "completed"
+)
+
+// Standing order execution statuses. An execution stays running if the server stopped
+// between claiming the order and recording the outcome of its transfer. An execution whose
+// amount was above the approval threshold is pending approval, with the transfer request made instead.
+const (
+	ExecutionStatusRunning         = "running"
+	ExecutionStatusSucceeded       = "succeeded"
+	ExecutionStatusFailed          = "failed"
+	ExecutionStatusPendingApproval = "pending_approval"
+)
+
+// standingOrderRun returns the k-th run of the order, counting the start as run 0.
+// Runs are always computed from the start, so monthly orders don't drift after a short month:
+// an order starting on Jan 31 runs on Feb 28 (or 29) and then on Mar 31.
+func standingOrderRun(order StandingOrders, k int) time.Time {
+	n := int(order.IntervalCount) * k
+	switch order.Frequency {
+	case StandingOrderDaily:
+		return order.StartAt.AddDate(0, 0, n)
+	case StandingOrderWeekly:
+		return order.StartAt.AddDate(0, 0, 7*n)
+	default: // StandingOrderMonthly, enforced by a check constraint
+		start := order.StartAt
+		year, month, day := start.Date()
+		first := time.Date(year, month+time.Month(n), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return first.AddDate(0, 0, day-1)
+	}
+}
+
+// NextStandingOrderRun returns the first run of the order strictly after the given time.
+func NextStandingOrderRun(order StandingOrders, after time.Time) time.Time {
+	// start close to the answer instead of walking every run since the start
+	k := 0
+	if elapsed := after.Sub(order.StartAt); elapsed > 0 {
+		var periods int
+		switch order.Frequency {
+		case StandingOrderDaily:
+			periods = int(elapsed / (24 * time.Hour))
+		case StandingOrderWeekly:
+			periods = int(elapsed / (7 * 24 * time.Hour))
+		default:
+			periods = int(elapsed / (31 * 24 * time.Hour))
+		}
+		// back off one run, in case of a DST change
+		k = max(periods/int(order.IntervalCount)-1, 0)
+	}
+
+	for {
+		run := standingOrderRun(order, k)
+		if run.After(after) {
+			return run
+		}
+		k++
+	}
+}
+
+// ClaimStandingOrderTxResult contains the result of the ClaimStandingOrderTx function.
+type ClaimStandingOrderTxResult struct {
+	Order     StandingOrders          `json:"order"`
+	Execution StandingOrderExecutions `json:"execution"`
+}
+
+// ClaimStandingOrderTx claims one active standing order that is due at the given time.
+// The order row is locked with SKIP LOCKED, so replicas running the scheduler at the same
+// time claim different orders, and its next run is moved forward before the lock is released,
+// so an order is claimed once per run. Runs missed while the server was down are skipped,
+// the order runs once and then on its next scheduled run after now.
+// Orders whose end has passed are marked completed instead of being claimed.
+// It records a running execution, which the caller finishes once the transfer is done,
+// and returns sql.ErrNoRows when no order is due.
+func (store *SQLStore) ClaimStandingOrderTx(ctx context.Context, now time.Time) (ClaimStandingOrderTxResult, error) {
+	var result ClaimStandingOrderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for {
+			order, err := q.ClaimDueStandingOrder(ctx, now)
+			if err != nil {
+				return err
+			}
+
+			scheduledAt := order.NextRunAt
+			if order.EndAt.Valid && scheduledAt.After(order.EndAt.Time) {
+				_, err = q.AdvanceStandingOrder(ctx, AdvanceStandingOrderParams{
+					ID:        order.ID,
+					NextRunAt: order.NextRunAt,
+					Status:    StandingOrderStatusCompleted,
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			status := StandingOrderStatusActive
+			nextRunAt := NextStandingOrderRun(order, now)
+			if order.EndAt.Valid && nextRunAt.After(order.EndAt.Time) {
+				status = StandingOrderStatusCompleted
+			}
+
+			result.Order, err = q.AdvanceStandingOrder(ctx, AdvanceStandingOrderParams{
+				ID:        order.ID,
+				NextRunAt: nextRunAt,
+				Status:    status,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Execution, err = q.CreateStandingOrderExecution(ctx, CreateStandingOrderExecutionParams{
+				StandingOrderID: order.ID,
+				ScheduledAt:     scheduledAt,
+			})
+			return err
+		}
+	})
+
+	return result, err
+}
+
+// CreateStandingOrderTxParams contains the parameters for the CreateStandingOrderTx function.
+type CreateStandingOrderTxParams struct {
+	CreateStandingOrderParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateStandingOrderTx creates a standing order and records its audit event in the same transaction.
+func (store *SQLStore) CreateStandingOrderTx(ctx context.Context, arg CreateStandingOrderTxParams) (StandingOrders, error) {
+	var result StandingOrders
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateStandingOrder(ctx, arg.CreateStandingOrderParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionStandingOrderCreate,
+			TargetType: AuditTargetStandingOrder,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			After:      result,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateStandingOrderTxParams contains the parameters for the UpdateStandingOrderTx function.
+type UpdateStandingOrderTxParams struct {
+	UpdateStandingOrderParams
+	Audit AuditMeta `json:"-"`
+}
+
+// UpdateStandingOrderTx changes an active standing order and records its audit event, with the order
+// before and after, in the same transaction. Like UpdateStandingOrder, it returns sql.ErrNoRows
+// if the order doesn't exist or is no longer active.
+func (store *SQLStore) UpdateStandingOrderTx(ctx context.Context, arg UpdateStandingOrderTxParams) (StandingOrders, error) {
+	return store.changeStandingOrderTx(ctx, arg.ID, arg.Audit, AuditActionStandingOrderUpdate, func(q *Queries) (StandingOrders, error) {
+		return q.UpdateStandingOrder(ctx, arg.UpdateStandingOrderParams)
+	})
+}
+
+// CancelStandingOrderTxParams contains the parameters for the CancelStandingOrderTx function.
+type CancelStandingOrderTxParams struct {
+	ID    int64     `json:"id"`
+	Audit AuditMeta `json:"-"`
+}
+
+// CancelStandingOrderTx cancels an active standing order and records its audit event, with the order
+// before and after, in the same transaction. Like CancelStandingOrder, it returns sql.ErrNoRows
+// if the order doesn't exist or is no longer active.
+func (store *SQLStore) CancelStandingOrderTx(ctx context.Context, arg CancelStandingOrderTxParams) (StandingOrders, error) {
+	return store.changeStandingOrderTx(ctx, arg.ID, arg.Audit, AuditActionStandingOrderCancel, func(q *Queries) (StandingOrders, error) {
+		return q.CancelStandingOrder(ctx, arg.ID)
+	})
+}
+
+// changeStandingOrderTx applies the change to the order and records the audit event of the change.
+func (store *SQLStore) changeStandingOrderTx(ctx context.Context, orderID int64, audit AuditMeta, action string,
+	change func(q *Queries) (StandingOrders, error)) (StandingOrders, error) {
+	var order StandingOrders
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetStandingOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		order, err = change(q)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  audit,
+			Action:     action,
+			TargetType: AuditTargetStandingOrder,
+			TargetID:   strconv.FormatInt(orderID, 10),
+			Before:     before,
+			After:      order,
+		})
+		return err
+	})
+
+	return order, err
+}
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomStandingOrder(t *testing.T, from, to Accounts, startAt time.Time, endAt sql.NullTime) StandingOrders {
+	arg := CreateStandingOrderParams{
+		Owner:         from.Owner,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        10,
+		Currency:      from.Currency,
+		Frequency:     StandingOrderDaily,
+		IntervalCount: 1,
+		StartAt:       startAt,
+		EndAt:         endAt,
+	}
+
+	order, err := testQueries.CreateStandingOrder(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, order.ID)
+	require.Equal(t, arg.Owner, order.Owner)
+	require.Equal(t, arg.Amount, order.Amount)
+	require.Equal(t, StandingOrderStatusActive, order.Status)
+	require.WithinDuration(t, startAt, order.NextRunAt, time.Second)
+
+	return order
+}
+
+func TestNextStandingOrderRun(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		frequency string
+		interval  int32
+		after     time.Time
+		want      time.Time
+	}{
+		{
+			name:      "BeforeStart",
+			frequency: StandingOrderDaily,
+			interval:  1,
+			after:     start.Add(-time.Hour),
+			want:      start,
+		},
+		{
+			name:      "Daily",
+			frequency: StandingOrderDaily,
+			interval:  1,
+			after:     start,
+			want:      start.AddDate(0, 0, 1),
+		},
+		{
+			name:      "EveryTwoWeeks",
+			frequency: StandingOrderWeekly,
+			interval:  2,
+			after:     start.AddDate(0, 0, 20),
+			want:      start.AddDate(0, 0, 28),
+		},
+		{
+			name:      "MonthlyShortMonth",
+			frequency: StandingOrderMonthly,
+			interval:  1,
+			after:     start,
+			want:      time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "MonthlyDoesNotDrift",
+			frequency: StandingOrderMonthly,
+			interval:  1,
+			after:     time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+			want:      time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "SkipsMissedRuns",
+			frequency: StandingOrderMonthly,
+			interval:  3,
+			after:     time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+			want:      time.Date(2025, time.July, 31, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			order := StandingOrders{
+				Frequency:     tc.frequency,
+				IntervalCount: tc.interval,
+				StartAt:       start,
+			}
+			require.Equal(t, tc.want, NextStandingOrderRun(order, tc.after))
+		})
+	}
+}
+
+func TestClaimStandingOrderTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	// a few runs behind, in the past so that it's due
+	startAt := time.Now().Add(-50 * time.Hour).Add(-time.Minute)
+	order := createRandomStandingOrder(t, account1, account2, startAt, sql.NullTime{})
+
+	// claim until this order comes up, other tests may have left due orders behind
+	var result ClaimStandingOrderTxResult
+	for {
+		var err error
+		result, err = store.ClaimStandingOrderTx(context.Background(), time.Now())
+		require.NoError(t, err)
+		if result.Order.ID == order.ID {
+			break
+		}
+	}
+
+	require.Equal(t, order.ID, result.Execution.StandingOrderID)
+	require.Equal(t, ExecutionStatusRunning, result.Execution.Status)
+	require.WithinDuration(t, startAt, result.Execution.ScheduledAt, time.Second)
+
+	// missed runs are skipped, the next run is in the future
+	require.Equal(t, StandingOrderStatusActive, result.Order.Status)
+	require.True(t, result.Order.NextRunAt.After(time.Now()))
+	require.WithinDuration(t, startAt.Add(72*time.Hour), result.Order.NextRunAt, time.Second)
+
+	execution, err := store.FinishStandingOrderExecution(context.Background(), FinishStandingOrderExecutionParams{
+		ID:     result.Execution.ID,
+		Status: ExecutionStatusFailed,
+		Error:  sql.NullString{String: ErrInsufficientFunds.Error(), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExecutionStatusFailed, execution.Status)
+	require.True(t, execution.FinishedAt.Valid)
+
+	executions, err := store.ListStandingOrderExecutions(context.Background(), ListStandingOrderExecutionsParams{
+		StandingOrderID: order.ID,
+		Limit:           5,
+	})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+}
+
+func TestClaimStandingOrderTxEnded(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	startAt := time.Now().Add(-time.Minute)
+	endAt := sql.NullTime{Time: startAt.Add(time.Hour), Valid: true}
+	order := createRandomStandingOrder(t, account1, account2, startAt, endAt)
+
+	for {
+		result, err := store.ClaimStandingOrderTx(context.Background(), time.Now())
+		require.NoError(t, err)
+		if result.Order.ID == order.ID {
+			// the only run, so the order is completed right away
+			require.Equal(t, StandingOrderStatusCompleted, result.Order.Status)
+			break
+		}
+	}
+
+	_, err := store.CancelStandingOrder(context.Background(), order.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
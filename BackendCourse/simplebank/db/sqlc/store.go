@@ -5,14 +5,64 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
 )
 
+// ErrInsufficientFunds is returned when a debit would take an account's balance
+// below the negative of its overdraft limit.
+var ErrInsufficientFunds = errors.New("insufficient funds: overdraft limit exceeded")
+
 type Store interface {
 	// Embedding the Querier interface from mockgen package to access all its methods
 	// The Store interface embeds the Querier interface, which means it inherits all the methods defined in the Querier interface.
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	TransferBatchTx(ctx context.Context, arg TransferBatchTxParams) (TransferBatchTxResult, error)
+	AuthorizeTransferTx(ctx context.Context, arg AuthorizeTransferTxParams) (AuthorizeTransferTxResult, error)
+	CaptureTransferTx(ctx context.Context, arg CaptureTransferTxParams) (TransferTxResult, error)
+	ReleaseTransferTx(ctx context.Context, arg ReleaseTransferTxParams) (ReleaseTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	CreateTransferRequestTx(ctx context.Context, arg CreateTransferRequestTxParams) (TransferRequests, error)
+	ApproveTransferRequestTx(ctx context.Context, arg DecideTransferRequestTxParams) (DecideTransferRequestTxResult, error)
+	RejectTransferRequestTx(ctx context.Context, arg DecideTransferRequestTxParams) (DecideTransferRequestTxResult, error)
+	AcceptMoneyRequestTx(ctx context.Context, arg AcceptMoneyRequestTxParams) (AcceptMoneyRequestTxResult, error)
+	ClaimStandingOrderTx(ctx context.Context, now time.Time) (ClaimStandingOrderTxResult, error)
+	ChargeOverdraftInterestTx(ctx context.Context, arg ChargeOverdraftInterestTxParams) (ChargeOverdraftInterestTxResult, error)
+	AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (AccrueInterestTxResult, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
+	CreateLoanTx(ctx context.Context, arg CreateLoanTxParams) (CreateLoanTxResult, error)
+	CollectLoanInstallmentTx(ctx context.Context, installmentID int64) (CollectLoanInstallmentTxResult, error)
+	ChargeLoanLateFeesTx(ctx context.Context, arg ChargeLoanLateFeesParams) ([]LoanInstallments, error)
+	DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error)
+	BalanceAt(ctx context.Context, accountID int64, at time.Time) (int64, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error)
+	UpdateAccountOverdraftLimitTx(ctx context.Context, arg UpdateAccountOverdraftLimitTxParams) (Accounts, error)
+	UpdateAccountApprovalThresholdTx(ctx context.Context, arg UpdateAccountApprovalThresholdTxParams) (Accounts, error)
+	AddAccountApproverTx(ctx context.Context, arg AccountApproverTxParams) (AccountApprovers, error)
+	RemoveAccountApproverTx(ctx context.Context, arg AccountApproverTxParams) error
+	UpdateUserTierTx(ctx context.Context, arg UpdateUserTierTxParams) (Users, error)
+	CreateFeeRuleTx(ctx context.Context, arg CreateFeeRuleTxParams) (FeeRules, error)
+	DeleteFeeRuleTx(ctx context.Context, arg DeleteFeeRuleTxParams) error
+	CreateStandingOrderTx(ctx context.Context, arg CreateStandingOrderTxParams) (StandingOrders, error)
+	UpdateStandingOrderTx(ctx context.Context, arg UpdateStandingOrderTxParams) (StandingOrders, error)
+	CancelStandingOrderTx(ctx context.Context, arg CancelStandingOrderTxParams) (StandingOrders, error)
+	CreateTransferLimitTx(ctx context.Context, arg CreateTransferLimitTxParams) (TransferLimits, error)
+	DeleteTransferLimitTx(ctx context.Context, arg DeleteTransferLimitTxParams) error
+	CreateInterestRateTx(ctx context.Context, arg CreateInterestRateTxParams) (InterestRates, error)
+	CreatePayeeTx(ctx context.Context, arg CreatePayeeTxParams) (Payees, error)
+	UpdatePayeeNicknameTx(ctx context.Context, arg UpdatePayeeNicknameTxParams) (Payees, error)
+	DeletePayeeTx(ctx context.Context, arg DeletePayeeTxParams) error
+	CreateWebhookEndpointTx(ctx context.Context, arg CreateWebhookEndpointTxParams) (WebhookEndpoints, error)
+	DeleteWebhookEndpointTx(ctx context.Context, arg DeleteWebhookEndpointTxParams) error
+	AuditTx(ctx context.Context, arg AuditTxParams) (AuditEvents, error)
+	VerifyAuditChain(ctx context.Context) (AuditChainReport, error)
+	RecordWebhookAttemptTx(ctx context.Context, arg RecordWebhookAttemptTxParams) (RecordWebhookAttemptTxResult, error)
 }
 
 type SQLStore struct {
@@ -54,10 +104,16 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 }
 
 // TransferTxParams contains the parameters for the TransferTx function.
+// The description, reference and category are optional; they are stored on the transfer and on both its entries.
+// Audit tells who asked for the transfer, for its audit event.
 type TransferTxParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Description   string    `json:"description"`
+	Reference     string    `json:"reference"`
+	Category      string    `json:"category"`
+	Audit         AuditMeta `json:"-"`
 }
 
 // TransferTxResult contains the result of the TransferTx function.
@@ -67,6 +123,8 @@ type TransferTxResult struct {
 	ToAccount   Accounts  `json:"to_account"`
 	FromEntry   Entries   `json:"from_entry"`
 	ToEntry     Entries   `json:"to_entry"`
+	// Fee is charged on top of the amount, from the from account
+	Fee FeeBreakdown `json:"fee"`
 }
 
 // this is used to transfer the transaction name to the context
@@ -74,8 +132,15 @@ type TransferTxResult struct {
 
 // TransferTx performs a money transfer from one account to another.
 // It creates a transfer record, updates the account balances, and creates entry records for both accounts.
+// If a fee rule applies, the fee is charged from the from account into the bank's fee account in the same transaction.
 // It uses a transaction to ensure atomicity, meaning that either all operations succeed or none do.
+// If the from account would end up below its overdraft limit, counting the fee, it returns ErrInsufficientFunds and nothing is written.
+// If the transfer goes over one of the from account's transfer limits, it returns a *LimitExceededError and nothing is written.
+// If it pays more than the cooling-off max amount of a payee of the from account's owner that is still cooling off,
+// it returns a *PayeeCoolingOffError and nothing is written.
 // The function returns a TransferTxResult containing the details of the transfer and the updated account balances.
+// The transfer's audit event, with both accounts before and after it, is recorded in the same transaction,
+// as are its transfer.sent and transfer.received outbox events.
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
@@ -84,56 +149,153 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 	// and will be used to perform all database operations within the transaction.
 	// All the transaction operations are executed in the function passed to execTx.
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		// txName := ctx.Value(txKey)
-
-		// Create a transfer record
-		// fmt.Println(txName, "CreateTransfer")
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		before, err := lockTransferAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
 		if err != nil {
 			return err
 		}
 
-		// Create an entry record for the from account
-		// fmt.Println(txName, "CreateEntry1")
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+		result, err = transfer(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		// Create an entry record for the to account
-		// fmt.Println(txName, "CreateEntry2")
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionTransferCreate,
+			TargetType: AuditTargetTransfer,
+			TargetID:   strconv.FormatInt(result.Transfer.ID, 10),
+			Before:     before,
+			After:      result,
 		})
-		if err != nil {
-			return err
+		return err
+	})
+
+	return result, err
+}
+
+// transferAccounts are the two accounts of a transfer, as recorded in its audit event.
+type transferAccounts struct {
+	FromAccount Accounts `json:"from_account"`
+	ToAccount   Accounts `json:"to_account"`
+}
+
+// lockTransferAccounts locks the accounts of a transfer in ascending ID order, the order transfer
+// updates them in, and returns them as they are before the transfer.
+func lockTransferAccounts(ctx context.Context, q *Queries, fromAccountID int64, toAccountID int64) (transferAccounts, error) {
+	var accounts transferAccounts
+	var err error
+
+	first, second := &accounts.FromAccount, &accounts.ToAccount
+	firstID, secondID := fromAccountID, toAccountID
+	if toAccountID < fromAccountID {
+		first, second = second, first
+		firstID, secondID = secondID, firstID
+	}
+
+	if *first, err = q.GetAccountForUpdate(ctx, firstID); err != nil {
+		return accounts, err
+	}
+	*second, err = q.GetAccountForUpdate(ctx, secondID)
+	return accounts, err
+}
+
+// transfer does the work of TransferTx with the given Queries, so that it can also be
+// run as one step of a larger transaction. Every transfer writes its outbox events,
+// whichever transaction it is a step of.
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+	// txName := ctx.Value(txKey)
+
+	// Create a transfer record
+	// fmt.Println(txName, "CreateTransfer")
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Description:   arg.Description,
+		Reference:     arg.Reference,
+		Category:      arg.Category,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Create an entry record for the from account
+	// fmt.Println(txName, "CreateEntry1")
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:   arg.FromAccountID,
+		Amount:      -arg.Amount,
+		Description: arg.Description,
+		Reference:   arg.Reference,
+		Category:    arg.Category,
+		TransferID:  sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Create an entry record for the to account
+	// fmt.Println(txName, "CreateEntry2")
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:   arg.ToAccountID,
+		Amount:      arg.Amount,
+		Description: arg.Description,
+		Reference:   arg.Reference,
+		Category:    arg.Category,
+		TransferID:  sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// to avoid deadlock, we need to update the account balance in the same order. We will always
+	// update the account with the smaller ID first.
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMonney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMonney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+	// fmt.Println(txName, "UpdateAccount1")	}
+
+	_, err = postJournal(ctx, q, transferJournal(JournalCategoryTransfer, result.Transfer, result.FromAccount.Currency),
+		[]Entries{result.FromEntry, result.ToEntry})
+	if err != nil {
+		return result, err
+	}
+
+	// the bank's own accounts, e.g. the one loans are paid from, may go negative
+	if result.FromAccount.Type == util.SystemAccount {
+		return result, writeTransferEvents(ctx, q, result)
+	}
+
+	// paying the bank, e.g. a loan repayment, or moving money between an account
+	// and its pockets is neither limited nor charged a fee
+	if result.ToAccount.Type != util.SystemAccount && !isPocketMove(result.FromAccount, result.ToAccount) {
+		if err := checkTransferLimits(ctx, q, result.FromAccount, arg.Amount); err != nil {
+			return result, err
 		}
 
-		// to avoid deadlock, we need to update the account balance in the same order. We will always
-		// update the account with the smaller ID first.
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMonney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMonney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+		if err := checkPayeeCoolingOff(ctx, q, result.FromAccount, arg.ToAccountID, arg.Amount); err != nil {
+			return result, err
 		}
+
+		result.Fee, result.FromAccount, err = chargeTransferFee(ctx, q, result.FromAccount, result.Transfer.ID, arg.Amount)
 		if err != nil {
-			return err
+			return result, err
 		}
-		// fmt.Println(txName, "UpdateAccount1")	}
+	}
 
-		return nil
-	})
+	// the balance update above holds the row lock, so the check can't race with
+	// another transfer from the same account
+	if err := checkOverdraft(result.FromAccount); err != nil {
+		return result, err
+	}
 
-	return result, err
+	return result, writeTransferEvents(ctx, q, result)
 }
 
 func addMonney(ctx context.Context,
@@ -158,3 +320,18 @@ func addMonney(ctx context.Context,
 
 	return
 }
+
+// isPocketMove reports whether a transfer moves money between an account and one of its pockets.
+func isPocketMove(from, to Accounts) bool {
+	return (from.ParentAccountID.Valid && from.ParentAccountID.Int64 == to.ID) ||
+		(to.ParentAccountID.Valid && to.ParentAccountID.Int64 == from.ID)
+}
+
+// checkOverdraft returns ErrInsufficientFunds if the account's available balance,
+// which also counts pending holds, is below the negative of its overdraft limit.
+func checkOverdraft(account Accounts) error {
+	if account.AvailableBalance < -account.OverdraftLimit {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
@@ -18,6 +18,9 @@ func TestTransferTx(t *testing.T) {
 	n := 5
 	amount := int64(10)
 
+	// the random balance may be smaller than the total transferred
+	account1 = updateOverdraftLimit(t, account1, int64(n)*amount)
+
 	// create channels to handle errors and results
 	errsChannel := make(chan error)
 	resultsChannel := make(chan TransferTxResult)
@@ -133,6 +136,10 @@ func TestTransferTxDeadLock(t *testing.T) {
 	n := 10
 	amount := int64(10)
 
+	// depending on scheduling either account may temporarily pay out more than it received
+	account1 = updateOverdraftLimit(t, account1, int64(n)*amount)
+	account2 = updateOverdraftLimit(t, account2, int64(n)*amount)
+
 	// create channels to handle errors and results
 	errsChannel := make(chan error)
 
@@ -183,3 +190,38 @@ func TestTransferTxDeadLock(t *testing.T) {
 
 	// fmt.Println("After Tx: ", updatedAccount1.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	limit := int64(100)
+	account1 = updateOverdraftLimit(t, account1, limit)
+
+	// going exactly down to -limit is allowed
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + limit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -limit, result.FromAccount.Balance)
+
+	// one more unit is not
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// and the failed transfer was rolled back
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, -limit, updatedAccount1.Balance)
+
+	updatedAccount2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.ToAccount.Balance, updatedAccount2.Balance)
+}
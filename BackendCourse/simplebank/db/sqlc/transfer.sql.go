@@ -7,26 +7,161 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const addTransferReversedAmount = `-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category
+`
+
+type AddTransferReversedAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfers, error) {
+	row := q.db.QueryRowContext(ctx, addTransferReversedAmount, arg.Amount, arg.ID)
+	var i Transfers
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+	)
+	return i, err
+}
+
+const captureTransfer = `-- name: CaptureTransfer :one
+UPDATE transfers
+SET status = 'captured',
+    captured_amount = $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category
+`
+
+type CaptureTransferParams struct {
+	CapturedAmount int64 `json:"captured_amount"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) CaptureTransfer(ctx context.Context, arg CaptureTransferParams) (Transfers, error) {
+	row := q.db.QueryRowContext(ctx, captureTransfer, arg.CapturedAmount, arg.ID)
+	var i Transfers
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+	)
+	return i, err
+}
+
+const createPendingTransfer = `-- name: CreatePendingTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  status,
+  expires_at,
+  description,
+  reference,
+  category
+) VALUES (
+  $1, $2, $3, 'pending', $4::timestamptz,
+  $5, $6, $7
+) RETURNING id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category
+`
+
+type CreatePendingTransferParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Description   string    `json:"description"`
+	Reference     string    `json:"reference"`
+	Category      string    `json:"category"`
+}
+
+func (q *Queries) CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (Transfers, error) {
+	row := q.db.QueryRowContext(ctx, createPendingTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ExpiresAt,
+		arg.Description,
+		arg.Reference,
+		arg.Category,
+	)
+	var i Transfers
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  captured_amount,
+  description,
+  reference,
+  category
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+  $1, $2, $3, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Description   string `json:"description"`
+	Reference     string `json:"reference"`
+	Category      string `json:"category"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfers, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Description,
+		arg.Reference,
+		arg.Category,
+	)
 	var i Transfers
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +169,56 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
 	)
 	return i, err
 }
 
+const getOutgoingTransferTotals = `-- name: GetOutgoingTransferTotals :one
+SELECT
+  COALESCE(SUM(CASE WHEN transfers.status = 'pending' THEN transfers.amount ELSE transfers.captured_amount END), 0)::bigint AS total,
+  count(*) AS count
+FROM transfers
+JOIN accounts AS from_account ON from_account.id = transfers.from_account_id
+JOIN accounts AS to_account ON to_account.id = transfers.to_account_id
+WHERE transfers.from_account_id = $1
+  AND transfers.created_at > $2::timestamptz
+  AND transfers.status IN ('pending', 'captured')
+  AND to_account.type <> 'system'
+  AND from_account.parent_account_id IS DISTINCT FROM to_account.id
+  AND to_account.parent_account_id IS DISTINCT FROM from_account.id
+  AND NOT EXISTS (SELECT 1 FROM transfer_reversals WHERE transfer_reversals.transfer_id = transfers.id)
+`
+
+type GetOutgoingTransferTotalsParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	Since         time.Time `json:"since"`
+}
+
+type GetOutgoingTransferTotalsRow struct {
+	Total int64 `json:"total"`
+	Count int64 `json:"count"`
+}
+
+// Sums what left the account since the given time: captured transfers count what was captured
+// and pending holds what they hold. Reversals are corrections by the bank, not payments, so they don't count.
+// Neither do payments to the bank's own accounts nor moves between the account and its pockets or parent,
+// which aren't limited either.
+func (q *Queries) GetOutgoingTransferTotals(ctx context.Context, arg GetOutgoingTransferTotalsParams) (GetOutgoingTransferTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getOutgoingTransferTotals, arg.FromAccountID, arg.Since)
+	var i GetOutgoingTransferTotalsRow
+	err := row.Scan(&i.Total, &i.Count)
+	return i, err
+}
+
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +231,144 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfers, error)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfers, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfers
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
 	)
 	return i, err
 }
 
+const listExpiredPendingTransfers = `-- name: ListExpiredPendingTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category FROM transfers
+WHERE status = 'pending' AND expires_at <= $1::timestamptz
+ORDER BY expires_at
+LIMIT $2
+`
+
+type ListExpiredPendingTransfersParams struct {
+	Now     time.Time `json:"now"`
+	MaxRows int32     `json:"max_rows"`
+}
+
+func (q *Queries) ListExpiredPendingTransfers(ctx context.Context, arg ListExpiredPendingTransfersParams) ([]Transfers, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredPendingTransfers, arg.Now, arg.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfers{}
+	for rows.Next() {
+		var i Transfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Status,
+			&i.CapturedAmount,
+			&i.ExpiresAt,
+			&i.ReversedAmount,
+			&i.Description,
+			&i.Reference,
+			&i.Category,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutgoingTransferAmounts = `-- name: ListOutgoingTransferAmounts :many
+SELECT
+  transfers.created_at,
+  (CASE WHEN transfers.status = 'pending' THEN transfers.amount ELSE transfers.captured_amount END)::bigint AS amount
+FROM transfers
+JOIN accounts AS from_account ON from_account.id = transfers.from_account_id
+JOIN accounts AS to_account ON to_account.id = transfers.to_account_id
+WHERE transfers.from_account_id = $1
+  AND transfers.created_at > $2::timestamptz
+  AND transfers.status IN ('pending', 'captured')
+  AND to_account.type <> 'system'
+  AND from_account.parent_account_id IS DISTINCT FROM to_account.id
+  AND to_account.parent_account_id IS DISTINCT FROM from_account.id
+  AND NOT EXISTS (SELECT 1 FROM transfer_reversals WHERE transfer_reversals.transfer_id = transfers.id)
+ORDER BY transfers.created_at, transfers.id
+`
+
+type ListOutgoingTransferAmountsParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	Since         time.Time `json:"since"`
+}
+
+type ListOutgoingTransferAmountsRow struct {
+	CreatedAt time.Time `json:"created_at"`
+	Amount    int64     `json:"amount"`
+}
+
+// Lists what left the account since the given time, oldest first, counted like GetOutgoingTransferTotals.
+func (q *Queries) ListOutgoingTransferAmounts(ctx context.Context, arg ListOutgoingTransferAmountsParams) ([]ListOutgoingTransferAmountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOutgoingTransferAmounts, arg.FromAccountID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOutgoingTransferAmountsRow{}
+	for rows.Next() {
+		var i ListOutgoingTransferAmountsRow
+		if err := rows.Scan(&i.CreatedAt, &i.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category FROM transfers
 WHERE
     from_account_id = $1 OR to_account_id = $2
 ORDER BY id
@@ -92,6 +403,13 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Status,
+			&i.CapturedAmount,
+			&i.ExpiresAt,
+			&i.ReversedAmount,
+			&i.Description,
+			&i.Reference,
+			&i.Category,
 		); err != nil {
 			return nil, err
 		}
@@ -105,3 +423,99 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category FROM transfers
+WHERE (from_account_id = $1 OR to_account_id = $1)
+  AND ($2::varchar IS NULL OR category = $2::varchar)
+  AND ($3::varchar IS NULL
+    OR description ILIKE '%' || $3::varchar || '%'
+    OR reference ILIKE '%' || $3::varchar || '%')
+ORDER BY id
+LIMIT $4
+OFFSET $5
+`
+
+type SearchTransfersParams struct {
+	AccountID int64          `json:"account_id"`
+	Category  sql.NullString `json:"category"`
+	Search    sql.NullString `json:"search"`
+	MaxRows   int32          `json:"max_rows"`
+	SkipRows  int32          `json:"skip_rows"`
+}
+
+// Lists the transfers into or out of the account, optionally only the ones of a category
+// and the ones whose description or reference contain the search text, ignoring case.
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfers, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfers,
+		arg.AccountID,
+		arg.Category,
+		arg.Search,
+		arg.MaxRows,
+		arg.SkipRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfers{}
+	for rows.Next() {
+		var i Transfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Status,
+			&i.CapturedAmount,
+			&i.ExpiresAt,
+			&i.ReversedAmount,
+			&i.Description,
+			&i.Reference,
+			&i.Category,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTransferStatus = `-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, status, captured_amount, expires_at, reversed_amount, description, reference, category
+`
+
+type UpdateTransferStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfers, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferStatus, arg.Status, arg.ID)
+	var i Transfers
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.ExpiresAt,
+		&i.ReversedAmount,
+		&i.Description,
+		&i.Reference,
+		&i.Category,
+	)
+	return i, err
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
@@ -73,3 +74,64 @@ func TestListTransfers(t *testing.T) {
 		}
 	}
 }
+
+func TestTransferTxMemo(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+		Description:   "Dinner, 50% of the bill",
+		Reference:     "INV-2024/0042",
+		Category:      "dining",
+	})
+	require.NoError(t, err)
+
+	for _, memo := range []struct{ description, reference, category string }{
+		{result.Transfer.Description, result.Transfer.Reference, result.Transfer.Category},
+		{result.FromEntry.Description, result.FromEntry.Reference, result.FromEntry.Category},
+		{result.ToEntry.Description, result.ToEntry.Reference, result.ToEntry.Category},
+	} {
+		require.Equal(t, "Dinner, 50% of the bill", memo.description)
+		require.Equal(t, "INV-2024/0042", memo.reference)
+		require.Equal(t, "dining", memo.category)
+	}
+
+	// a plain transfer between the same accounts, which the searches below must leave out
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.NoError(t, err)
+
+	transfers, err := testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: account2.ID,
+		Search:    sql.NullString{String: "inv-2024", Valid: true},
+		MaxRows:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, result.Transfer.ID, transfers[0].ID)
+
+	transfers, err = testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: account1.ID,
+		Category:  sql.NullString{String: "groceries", Valid: true},
+		MaxRows:   5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+
+	entries, err := testQueries.SearchEntries(context.Background(), SearchEntriesParams{
+		AccountID: account1.ID,
+		Category:  sql.NullString{String: "dining", Valid: true},
+		Search:    sql.NullString{String: `50\%`, Valid: true},
+		MaxRows:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, result.FromEntry.ID, entries[0].ID)
+}
@@ -0,0 +1,86 @@
+package db
+
+import "context"
+
+// CreateUserTxParams contains the parameters for the CreateUserTx function.
+type CreateUserTxParams struct {
+	CreateUserParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateUserTxResult contains the result of the CreateUserTx function.
+type CreateUserTxResult struct {
+	User       Users       `json:"user"`
+	AuditEvent AuditEvents `json:"audit_event"`
+}
+
+// CreateUserTx creates a user and records its audit event and user.created outbox event in the same transaction.
+// The user's audit snapshot and event payload leave out the hashed password.
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		snapshot := result.User
+		snapshot.HashedPassword = ""
+		err = writeOutboxEvent(ctx, q, EventTypeUserCreated, OutboxAggregateUser, result.User.Username, snapshot)
+		if err != nil {
+			return err
+		}
+
+		result.AuditEvent, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionUserCreate,
+			TargetType: AuditTargetUser,
+			TargetID:   result.User.Username,
+			After:      snapshot,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateUserTierTxParams contains the parameters for the UpdateUserTierTx function.
+type UpdateUserTierTxParams struct {
+	UpdateUserTierParams
+	Audit AuditMeta `json:"-"`
+}
+
+// UpdateUserTierTx moves a user to another tier and records its audit event, with the user before and after,
+// in the same transaction. It returns sql.ErrNoRows if the user doesn't exist.
+// Like in CreateUserTx, the audit snapshots leave out the hashed password.
+func (store *SQLStore) UpdateUserTierTx(ctx context.Context, arg UpdateUserTierTxParams) (Users, error) {
+	var user Users
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		user, err = q.UpdateUserTier(ctx, arg.UpdateUserTierParams)
+		if err != nil {
+			return err
+		}
+
+		after := user
+		before.HashedPassword, after.HashedPassword = "", ""
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionUserUpdateTier,
+			TargetType: AuditTargetUser,
+			TargetID:   user.Username,
+			Before:     before,
+			After:      after,
+		})
+		return err
+	})
+
+	return user, err
+}
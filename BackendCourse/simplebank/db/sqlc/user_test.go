@@ -26,6 +26,9 @@ func CreateRandomUser(t *testing.T) Users {
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
 	require.Equal(t, arg.Email, user.Email)
+	require.Equal(t, util.DepositorRole, user.Role)
+	require.Equal(t, util.StandardTier, user.Tier)
+	require.False(t, user.IsEmailVerified)
 
 	require.True(t, user.PasswordChangedAt.IsZero())
 	require.NotZero(t, user.CreatedAt)
@@ -45,3 +48,15 @@ func TestGetUser(t *testing.T) {
 
 	require.Equal(t, user1, user2)
 }
+
+func TestVerifyUserEmail(t *testing.T) {
+	user1 := CreateRandomUser(t)
+
+	user2, err := testQueries.VerifyUserEmail(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.True(t, user2.IsEmailVerified)
+
+	user3, err := testQueries.GetUserByEmail(context.Background(), user1.Email)
+	require.NoError(t, err)
+	require.Equal(t, user2, user3)
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Webhook delivery statuses. A delivery is retried while it is pending, until it succeeds
+// or it runs out of attempts and fails; a failed delivery can be replayed.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// ErrWebhookDeliveryNotFound is returned when recording an attempt of a delivery that was deleted
+// with its endpoint while it was being sent.
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+// RecordWebhookAttemptTxParams contains the parameters for the RecordWebhookAttemptTx function.
+// ResponseCode is 0 if the endpoint didn't answer. Status is the status of the delivery after the attempt,
+// and NextAttemptAt is when it is tried again if it is still pending.
+type RecordWebhookAttemptTxParams struct {
+	DeliveryID    int64     `json:"delivery_id"`
+	ResponseCode  int32     `json:"response_code"`
+	Error         string    `json:"error"`
+	Status        string    `json:"status"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// RecordWebhookAttemptTxResult contains the result of the RecordWebhookAttemptTx function.
+type RecordWebhookAttemptTxResult struct {
+	Delivery WebhookDeliveries       `json:"delivery"`
+	Attempt  WebhookDeliveryAttempts `json:"attempt"`
+}
+
+// RecordWebhookAttemptTx adds an attempt to the delivery log and updates the delivery with its outcome,
+// releasing the delivery's claim, in one transaction.
+// It returns ErrWebhookDeliveryNotFound if the delivery was deleted, with its endpoint, in the meantime.
+func (store *SQLStore) RecordWebhookAttemptTx(ctx context.Context, arg RecordWebhookAttemptTxParams) (RecordWebhookAttemptTxResult, error) {
+	var result RecordWebhookAttemptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// the lock keeps the endpoint from being deleted, with its deliveries, until the attempt is recorded
+		_, err := q.GetWebhookDeliveryForUpdate(ctx, arg.DeliveryID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrWebhookDeliveryNotFound
+			}
+			return err
+		}
+
+		result.Attempt, err = q.CreateWebhookDeliveryAttempt(ctx, CreateWebhookDeliveryAttemptParams{
+			DeliveryID:   arg.DeliveryID,
+			ResponseCode: arg.ResponseCode,
+			Error:        arg.Error,
+		})
+		if err != nil {
+			return err
+		}
+
+		update := UpdateWebhookDeliveryAttemptParams{
+			ID:            arg.DeliveryID,
+			Status:        arg.Status,
+			ResponseCode:  arg.ResponseCode,
+			LastError:     arg.Error,
+			NextAttemptAt: arg.NextAttemptAt,
+		}
+		if arg.Status == WebhookDeliverySucceeded {
+			update.DeliveredAt = sql.NullTime{Time: result.Attempt.CreatedAt, Valid: true}
+		}
+
+		result.Delivery, err = q.UpdateWebhookDeliveryAttempt(ctx, update)
+		return err
+	})
+
+	return result, err
+}
+
+// webhookEndpointSnapshot returns the endpoint as it is recorded in the audit log, without its secret.
+func webhookEndpointSnapshot(endpoint WebhookEndpoints) WebhookEndpoints {
+	endpoint.Secret = ""
+	return endpoint
+}
+
+// CreateWebhookEndpointTxParams contains the parameters for the CreateWebhookEndpointTx function.
+type CreateWebhookEndpointTxParams struct {
+	CreateWebhookEndpointParams
+	Audit AuditMeta `json:"-"`
+}
+
+// CreateWebhookEndpointTx registers a webhook endpoint and records its audit event, without the endpoint's secret,
+// in the same transaction.
+func (store *SQLStore) CreateWebhookEndpointTx(ctx context.Context, arg CreateWebhookEndpointTxParams) (WebhookEndpoints, error) {
+	var result WebhookEndpoints
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateWebhookEndpoint(ctx, arg.CreateWebhookEndpointParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionWebhookEndpointCreate,
+			TargetType: AuditTargetWebhookEndpoint,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			After:      webhookEndpointSnapshot(result),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteWebhookEndpointTxParams contains the parameters for the DeleteWebhookEndpointTx function.
+type DeleteWebhookEndpointTxParams struct {
+	ID    int64     `json:"id"`
+	Audit AuditMeta `json:"-"`
+}
+
+// DeleteWebhookEndpointTx deletes a webhook endpoint, with its deliveries, and records its audit event,
+// without the endpoint's secret, in the same transaction. It returns sql.ErrNoRows if the endpoint doesn't exist.
+func (store *SQLStore) DeleteWebhookEndpointTx(ctx context.Context, arg DeleteWebhookEndpointTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetWebhookEndpoint(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteWebhookEndpoint(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = appendAuditEvent(ctx, q, AuditTxParams{
+			AuditMeta:  arg.Audit,
+			Action:     AuditActionWebhookEndpointDelete,
+			TargetType: AuditTargetWebhookEndpoint,
+			TargetID:   strconv.FormatInt(before.ID, 10),
+			Before:     webhookEndpointSnapshot(before),
+		})
+		return err
+	})
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	endpoint, err := testQueries.CreateWebhookEndpoint(context.Background(), CreateWebhookEndpointParams{
+		Owner:      user.Username,
+		Url:        "https://merchant.example/hooks",
+		Secret:     "whsec_test",
+		EventTypes: []string{EventTypeTransferReceived, EventTypeAccountCreated},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{EventTypeTransferReceived, EventTypeAccountCreated}, endpoint.EventTypes)
+
+	// only the endpoints subscribed to an event type are listed for it
+	subscribed, err := testQueries.ListSubscribedWebhookEndpoints(context.Background(), ListSubscribedWebhookEndpointsParams{
+		Owner:     user.Username,
+		EventType: EventTypeTransferReceived,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []WebhookEndpoints{endpoint}, subscribed)
+
+	subscribed, err = testQueries.ListSubscribedWebhookEndpoints(context.Background(), ListSubscribedWebhookEndpointsParams{
+		Owner:     user.Username,
+		EventType: EventTypeTransferSent,
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscribed)
+
+	event, err := testQueries.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		EventType:     EventTypeTransferReceived,
+		AggregateType: OutboxAggregateUser,
+		AggregateID:   user.Username,
+		Payload:       json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	// an event published twice is delivered once
+	arg := CreateWebhookDeliveryParams{
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  event.EventType,
+		Payload:    json.RawMessage(`{"id":1}`),
+	}
+	require.NoError(t, testQueries.CreateWebhookDelivery(context.Background(), arg))
+	require.NoError(t, testQueries.CreateWebhookDelivery(context.Background(), arg))
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		MaxRows:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0]
+	require.Equal(t, WebhookDeliveryPending, delivery.Status)
+
+	result, err := store.RecordWebhookAttemptTx(context.Background(), RecordWebhookAttemptTxParams{
+		DeliveryID:   delivery.ID,
+		ResponseCode: 500,
+		Error:        "endpoint answered 500 Internal Server Error",
+		Status:       WebhookDeliveryFailed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryFailed, result.Delivery.Status)
+	require.Equal(t, int32(1), result.Delivery.Attempts)
+	require.Equal(t, int32(500), result.Delivery.ResponseCode)
+	require.Equal(t, int32(500), result.Attempt.ResponseCode)
+
+	failed, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Status:     sql.NullString{String: WebhookDeliveryFailed, Valid: true},
+		MaxRows:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+
+	replayed, err := testQueries.ReplayWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryPending, replayed.Status)
+	require.Zero(t, replayed.Attempts)
+
+	// only failed deliveries are replayed
+	_, err = testQueries.ReplayWebhookDelivery(context.Background(), delivery.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	result, err = store.RecordWebhookAttemptTx(context.Background(), RecordWebhookAttemptTxParams{
+		DeliveryID:    delivery.ID,
+		ResponseCode:  200,
+		Status:        WebhookDeliverySucceeded,
+		NextAttemptAt: replayed.NextAttemptAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliverySucceeded, result.Delivery.Status)
+	require.Empty(t, result.Delivery.LastError)
+	require.True(t, result.Delivery.DeliveredAt.Valid)
+	require.WithinDuration(t, time.Now(), result.Delivery.DeliveredAt.Time, time.Minute)
+
+	attempts, err := testQueries.ListWebhookDeliveryAttempts(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	require.Equal(t, int32(500), attempts[0].ResponseCode)
+	require.Equal(t, int32(200), attempts[1].ResponseCode)
+
+	// deleting the endpoint deletes its deliveries, so an attempt sent meanwhile can't be recorded
+	require.NoError(t, testQueries.DeleteWebhookEndpoint(context.Background(), endpoint.ID))
+	_, err = store.RecordWebhookAttemptTx(context.Background(), RecordWebhookAttemptTxParams{
+		DeliveryID:   delivery.ID,
+		ResponseCode: 200,
+		Status:       WebhookDeliverySucceeded,
+	})
+	require.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileSink is a Sink that appends events to a file, one JSON object per line.
+// It is meant for tests and local development.
+type FileSink struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileSink creates a new FileSink that appends to the file at path, creating it if needed.
+func NewFileSink(path string) Sink {
+	return &FileSink{path: path}
+}
+
+// Publish appends the event to the file
+func (sink *FileSink) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	file, err := os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,92 @@
+package events
+
+import "sync"
+
+// Hub is an in-process event bus that wakes up the subscribers of an aggregate when it has new events.
+// It doesn't carry the events, subscribers read them from the outbox after the last one they saw,
+// so a wake-up that is missed or comes twice never loses or repeats an event.
+type Hub struct {
+	mutex       sync.Mutex
+	subscribers map[string]map[*Subscription]struct{}
+}
+
+// Subscription wakes up its subscriber through C when any of its aggregates has new events.
+// Wake-ups that come while the subscriber is busy are merged into one.
+type Subscription struct {
+	C    <-chan struct{}
+	c    chan struct{}
+	keys []string
+	hub  *Hub
+}
+
+// NewHub creates an empty Hub
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[string]map[*Subscription]struct{})}
+}
+
+// AggregateKey identifies an aggregate, as in the outbox notifications, e.g. "account:12".
+func AggregateKey(aggregateType string, aggregateID string) string {
+	return aggregateType + ":" + aggregateID
+}
+
+// Subscribe returns a subscription to the aggregates with the given keys.
+// It must be closed when the subscriber is done.
+func (hub *Hub) Subscribe(keys ...string) *Subscription {
+	c := make(chan struct{}, 1)
+	subscription := &Subscription{C: c, c: c, keys: keys, hub: hub}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, key := range keys {
+		if hub.subscribers[key] == nil {
+			hub.subscribers[key] = make(map[*Subscription]struct{})
+		}
+		hub.subscribers[key][subscription] = struct{}{}
+	}
+	return subscription
+}
+
+// Close stops the subscription.
+func (subscription *Subscription) Close() {
+	hub := subscription.hub
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, key := range subscription.keys {
+		delete(hub.subscribers[key], subscription)
+		if len(hub.subscribers[key]) == 0 {
+			delete(hub.subscribers, key)
+		}
+	}
+}
+
+// Notify wakes up the subscribers of the aggregate with the key.
+func (hub *Hub) Notify(key string) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for subscription := range hub.subscribers[key] {
+		subscription.wake()
+	}
+}
+
+// NotifyAll wakes up every subscriber, e.g. when notifications may have been missed.
+func (hub *Hub) NotifyAll() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, subscriptions := range hub.subscribers {
+		for subscription := range subscriptions {
+			subscription.wake()
+		}
+	}
+}
+
+func (subscription *Subscription) wake() {
+	select {
+	case subscription.c <- struct{}{}:
+	default:
+		// a wake-up is already waiting
+	}
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func requireWoken(t *testing.T, subscription *Subscription) {
+	select {
+	case <-subscription.C:
+	case <-time.After(time.Second):
+		t.Fatal("subscription wasn't woken up")
+	}
+}
+
+func requireNotWoken(t *testing.T, subscription *Subscription) {
+	select {
+	case <-subscription.C:
+		t.Fatal("subscription was woken up")
+	default:
+	}
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	account1 := AggregateKey("account", "1")
+	account2 := AggregateKey("account", "2")
+	require.Equal(t, "account:1", account1)
+
+	both := hub.Subscribe(account1, account2)
+	defer both.Close()
+	second := hub.Subscribe(account2)
+
+	hub.Notify(account1)
+	requireWoken(t, both)
+	requireNotWoken(t, second)
+
+	// wake-ups are merged while the subscriber is busy
+	hub.Notify(account2)
+	hub.Notify(account2)
+	requireWoken(t, both)
+	requireNotWoken(t, both)
+	requireWoken(t, second)
+
+	second.Close()
+	hub.NotifyAll()
+	requireWoken(t, both)
+	requireNotWoken(t, second)
+}
+
+func TestForwardNotifications(t *testing.T) {
+	hub := NewHub()
+	subscription := hub.Subscribe(AggregateKey("account", "1"))
+	defer subscription.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	notifications := make(chan *pq.Notification)
+	done := make(chan struct{})
+	go func() {
+		ForwardNotifications(ctx, notifications, hub)
+		close(done)
+	}()
+
+	notifications <- &pq.Notification{Channel: OutboxChannel, Extra: "account:2"}
+	notifications <- &pq.Notification{Channel: OutboxChannel, Extra: "account:1"}
+	requireWoken(t, subscription)
+
+	// a reconnect wakes up everyone
+	notifications <- nil
+	requireWoken(t, subscription)
+
+	cancel()
+	<-done
+}
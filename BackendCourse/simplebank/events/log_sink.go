@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// LogSink is a Sink that writes events to the log.
+// It is used until a real consumer is configured.
+type LogSink struct{}
+
+// NewLogSink creates a new LogSink
+func NewLogSink() Sink {
+	return &LogSink{}
+}
+
+// Publish writes the event to the log
+func (sink *LogSink) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("event: %s", data)
+	return nil
+}
@@ -0,0 +1,25 @@
+package events
+
+import "context"
+
+// MultiSink is a Sink that publishes every event to several sinks, in order.
+// If one of them fails the event is published again to all of them,
+// which at least once delivery allows.
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink creates a new MultiSink of the sinks
+func NewMultiSink(sinks ...Sink) Sink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Publish publishes the event to every sink, stopping at the first that fails
+func (sink *MultiSink) Publish(ctx context.Context, event Event) error {
+	for _, s := range sink.sinks {
+		if err := s.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+// OutboxChannel is the Postgres channel an outbox_events trigger notifies with the key
+// of the aggregate of every new event, when the transaction that wrote it commits.
+const OutboxChannel = "outbox_events"
+
+// ForwardNotifications wakes up the hub's subscribers on every notification of the outbox channel,
+// until ctx is cancelled or the notifications are closed. A nil notification means the listener
+// reconnected and may have missed some, so every subscriber is woken up.
+func ForwardNotifications(ctx context.Context, notifications <-chan *pq.Notification, hub *Hub) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			if notification == nil {
+				hub.NotifyAll()
+				continue
+			}
+			if notification.Channel == OutboxChannel {
+				hub.Notify(notification.Extra)
+			}
+		}
+	}
+}
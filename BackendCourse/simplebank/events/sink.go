@@ -0,0 +1,58 @@
+// Package events publishes the bank's domain events, which the outbox relay reads
+// from the outbox table, to the systems that consume them.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event tells that something happened to an aggregate, e.g. that money was sent from an account.
+// Events are delivered at least once, so consumers should skip the IDs they have already seen.
+// The events of one aggregate are delivered in the order of their IDs.
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Sink is an interface for publishing events.
+// Implementations can post them to a webhook, write them to a file or send them to a broker such as NATS.
+type Sink interface {
+	// Publish delivers the event. An error means it may not have been delivered,
+	// and the relay publishes it again later.
+	Publish(ctx context.Context, event Event) error
+}
+
+// Sink kinds, as configured
+const (
+	SinkLog     = "log"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+)
+
+// NewSink creates the sink of the given kind. The target is the path of the file
+// for a file sink and the URL for a webhook sink; the log sink has none.
+func NewSink(kind string, target string) (Sink, error) {
+	switch kind {
+	case SinkLog, "":
+		return NewLogSink(), nil
+	case SinkFile:
+		if target == "" {
+			return nil, fmt.Errorf("the file sink needs the path of the file")
+		}
+		return NewFileSink(target), nil
+	case SinkWebhook:
+		if target == "" {
+			return nil, fmt.Errorf("the webhook sink needs a URL")
+		}
+		return NewWebhookSink(target), nil
+	default:
+		return nil, fmt.Errorf("unknown event sink %q", kind)
+	}
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomEvent(id int64) Event {
+	return Event{
+		ID:            id,
+		Type:          "transfer.sent",
+		AggregateType: "account",
+		AggregateID:   "1",
+		Payload:       json.RawMessage(`{"amount":10}`),
+		CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink(SinkLog, "")
+	require.NoError(t, err)
+	require.IsType(t, &LogSink{}, sink)
+
+	sink, err = NewSink(SinkFile, "events.jsonl")
+	require.NoError(t, err)
+	require.IsType(t, &FileSink{}, sink)
+
+	sink, err = NewSink(SinkWebhook, "http://localhost/events")
+	require.NoError(t, err)
+	require.IsType(t, &WebhookSink{}, sink)
+
+	_, err = NewSink(SinkWebhook, "")
+	require.Error(t, err)
+
+	_, err = NewSink("kafka", "")
+	require.EqualError(t, err, `unknown event sink "kafka"`)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+
+	events := []Event{randomEvent(1), randomEvent(2)}
+	for _, event := range events {
+		require.NoError(t, sink.Publish(context.Background(), event))
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var written []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		written = append(written, event)
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, events, written)
+}
+
+func TestWebhookSink(t *testing.T) {
+	event := randomEvent(7)
+
+	testCases := []struct {
+		name   string
+		status int
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name:   "OK",
+			status: http.StatusNoContent,
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "ServerError",
+			status: http.StatusServiceUnavailable,
+			check: func(t *testing.T, err error) {
+				require.EqualError(t, err, "webhook answered 503 Service Unavailable")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "7", r.Header.Get(EventIDHeader))
+				require.Equal(t, event.Type, r.Header.Get(EventTypeHeader))
+
+				var received Event
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				require.Equal(t, event, received)
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookSink(server.URL).Publish(context.Background(), event)
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.jsonl")
+	second := filepath.Join(dir, "second.jsonl")
+	event := randomEvent(1)
+
+	err := NewMultiSink(NewFileSink(first), NewFileSink(second)).Publish(context.Background(), event)
+	require.NoError(t, err)
+	for _, path := range []string{first, second} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NotEmpty(t, data)
+	}
+
+	// a sink that fails stops the ones after it
+	third := filepath.Join(dir, "third.jsonl")
+	missing := NewFileSink(filepath.Join(dir, "missing", "events.jsonl"))
+	err = NewMultiSink(missing, NewFileSink(third)).Publish(context.Background(), event)
+	require.Error(t, err)
+	require.NoFileExists(t, third)
+}
@@ -0,0 +1,144 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/statement"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// camt053Namespace is the version of camt.053 the statements are written in.
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+// Balance types of the statement.
+const (
+	openingBookedBalance = "OPBD"
+	closingBookedBalance = "CLBD"
+)
+
+// bookedStatus is the status of entries that are booked on the account.
+const bookedStatus = "BOOK"
+
+type camtBalance struct {
+	Type      string `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    amount `xml:"Amt"`
+	Indicator string `xml:"CdtDbtInd"`
+	Date      string `xml:"Dt>Dt"`
+}
+
+type camtEntry struct {
+	Reference       string `xml:"NtryRef"`
+	Amount          amount `xml:"Amt"`
+	Indicator       string `xml:"CdtDbtInd"`
+	Status          string `xml:"Sts>Cd"`
+	BookingDate     string `xml:"BookgDt>DtTm"`
+	ValueDate       string `xml:"ValDt>Dt"`
+	ServicerRef     string `xml:"AcctSvcrRef"`
+	TransactionCode string `xml:"BkTxCd>Prtry>Cd"`
+	EndToEndID      string `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+	RemittanceInfo  string `xml:"NtryDtls>TxDtls>RmtInf>Ustrd,omitempty"`
+}
+
+type camt053Document struct {
+	XMLName   xml.Name `xml:"Document"`
+	Namespace string   `xml:"xmlns,attr"`
+	Header    struct {
+		MessageID string `xml:"MsgId"`
+		CreatedAt string `xml:"CreDtTm"`
+	} `xml:"BkToCstmrStmt>GrpHdr"`
+	Statement struct {
+		ID        string `xml:"Id"`
+		CreatedAt string `xml:"CreDtTm"`
+		From      string `xml:"FrToDt>FrDtTm"`
+		To        string `xml:"FrToDt>ToDtTm"`
+		Account   struct {
+			accountIdentification
+			Currency string `xml:"Ccy"`
+			Owner    string `xml:"Ownr>Nm"`
+		} `xml:"Acct"`
+		Balances []camtBalance `xml:"Bal"`
+		Entries  []camtEntry   `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// WriteCamt053 renders the statement as a camt.053 bank to customer statement, with its opening
+// and closing booked balances and an entry per statement line.
+func WriteCamt053(w io.Writer, stmt statement.Statement) error {
+	lastDay := stmt.To.AddDate(0, 0, -1)
+
+	var doc camt053Document
+	doc.Namespace = camt053Namespace
+	doc.Header.MessageID = fmt.Sprintf("STMT-%d-%d", stmt.AccountID, stmt.GeneratedAt.Unix())
+	doc.Header.CreatedAt = stmt.GeneratedAt.UTC().Format(time.RFC3339)
+
+	doc.Statement.ID = fmt.Sprintf("%d-%s-%s", stmt.AccountID, stmt.From.Format("20060102"), lastDay.Format("20060102"))
+	doc.Statement.CreatedAt = doc.Header.CreatedAt
+	doc.Statement.From = stmt.From.UTC().Format(time.RFC3339)
+	doc.Statement.To = stmt.To.Add(-time.Second).UTC().Format(time.RFC3339)
+	doc.Statement.Account.Other = strconv.FormatInt(stmt.AccountID, 10)
+	doc.Statement.Account.Currency = stmt.Currency
+	doc.Statement.Account.Owner = stmt.Owner
+
+	doc.Statement.Balances = []camtBalance{
+		camtBalanceOf(openingBookedBalance, stmt.OpeningBalance, stmt.Currency, stmt.From),
+		camtBalanceOf(closingBookedBalance, stmt.ClosingBalance, stmt.Currency, lastDay),
+	}
+
+	doc.Statement.Entries = make([]camtEntry, len(stmt.Lines))
+	for i, line := range stmt.Lines {
+		value, indicator := camtAmount(line.Amount, stmt.Currency)
+		entryID := strconv.FormatInt(line.EntryID, 10)
+
+		transactionCode := line.Category
+		if transactionCode == "" {
+			transactionCode = notProvided
+		}
+		endToEndID := line.Reference
+		if endToEndID == "" {
+			endToEndID = notProvided
+		}
+
+		doc.Statement.Entries[i] = camtEntry{
+			Reference:       entryID,
+			Amount:          amount{Currency: stmt.Currency, Value: value},
+			Indicator:       indicator,
+			Status:          bookedStatus,
+			BookingDate:     line.Time.UTC().Format(time.RFC3339),
+			ValueDate:       line.Time.UTC().Format(time.DateOnly),
+			ServicerRef:     entryID,
+			TransactionCode: transactionCode,
+			EndToEndID:      endToEndID,
+			RemittanceInfo:  line.Description,
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func camtBalanceOf(balanceType string, balance int64, currency string, date time.Time) camtBalance {
+	value, indicator := camtAmount(balance, currency)
+	return camtBalance{
+		Type:      balanceType,
+		Amount:    amount{Currency: currency, Value: value},
+		Indicator: indicator,
+		Date:      date.Format(time.DateOnly),
+	}
+}
+
+// camtAmount splits an amount into its absolute value, in the currency's major units,
+// and whether it is a credit or a debit, since camt amounts are never negative.
+func camtAmount(n int64, currency string) (string, string) {
+	if n < 0 {
+		return util.FormatMajorUnits(-n, currency), debit
+	}
+	return util.FormatMajorUnits(n, currency), credit
+}
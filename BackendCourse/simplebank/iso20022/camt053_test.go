@@ -0,0 +1,54 @@
+package iso20022
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/statement"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCamt053(t *testing.T) {
+	account := db.Accounts{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    util.RandomOwner(),
+		Currency: util.RandomCurrency(),
+		Type:     util.CheckingAccount,
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []db.Entries{
+		{ID: 1, AccountID: account.ID, Amount: 5000, CreatedAt: from.Add(time.Hour), Description: "Salary", Reference: "PAY-03"},
+		{ID: 2, AccountID: account.ID, Amount: -8000, CreatedAt: from.AddDate(0, 0, 2), Category: "rent"},
+	}
+	stmt := statement.New(account, from, from.AddDate(0, 1, 0), 2000, entries)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCamt053(&buf, stmt))
+
+	var doc camt053Document
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, camt053Namespace, doc.XMLName.Space)
+	require.Equal(t, "2026-03-31T23:59:59Z", doc.Statement.To)
+
+	accountID, err := doc.Statement.Account.accountID()
+	require.NoError(t, err)
+	require.Equal(t, account.ID, accountID)
+
+	require.Equal(t, []camtBalance{
+		{Type: openingBookedBalance, Amount: amount{Currency: account.Currency, Value: "20.00"}, Indicator: credit, Date: "2026-03-01"},
+		{Type: closingBookedBalance, Amount: amount{Currency: account.Currency, Value: "10.00"}, Indicator: debit, Date: "2026-03-31"},
+	}, doc.Statement.Balances)
+
+	require.Len(t, doc.Statement.Entries, 2)
+	require.Equal(t, credit, doc.Statement.Entries[0].Indicator)
+	require.Equal(t, "PAY-03", doc.Statement.Entries[0].EndToEndID)
+	require.Equal(t, notProvided, doc.Statement.Entries[0].TransactionCode)
+	require.Equal(t, "80.00", doc.Statement.Entries[1].Amount.Value)
+	require.Equal(t, debit, doc.Statement.Entries[1].Indicator)
+	require.Equal(t, "rent", doc.Statement.Entries[1].TransactionCode)
+	require.Equal(t, notProvided, doc.Statement.Entries[1].EndToEndID)
+}
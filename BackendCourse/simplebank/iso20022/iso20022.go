@@ -0,0 +1,66 @@
+// Package iso20022 reads and writes the ISO 20022 XML messages corporate clients exchange with the bank:
+// camt.053 statements going out and pain.001 credit transfer initiations coming in.
+//
+// Accounts are identified by their simplebank account ID, as a proprietary ("other") identification.
+// Amounts are decimal numbers of the currency's major units, e.g. "100.50" USD,
+// and are converted from and to the minor units simplebank stores them in.
+package iso20022
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// Credit and debit indicators.
+const (
+	credit = "CRDT"
+	debit  = "DBIT"
+)
+
+// notProvided is the ISO 20022 value of identifications the sender doesn't have.
+const notProvided = "NOTPROVIDED"
+
+var (
+	// ErrIBANNotSupported is returned for an account identified by an IBAN instead of its account ID.
+	ErrIBANNotSupported = errors.New("accounts identified by IBAN are not supported, use the account ID")
+	// ErrInvalidAccountID is returned for an account identification that isn't an account ID.
+	ErrInvalidAccountID = errors.New("invalid account ID")
+	// ErrInvalidAmount is returned for an amount that isn't a whole number of minor units of its currency.
+	ErrInvalidAmount = errors.New("amounts must be decimal numbers with at most as many decimals as their currency has")
+)
+
+// accountIdentification is the identification of a cash account.
+type accountIdentification struct {
+	IBAN  string `xml:"Id>IBAN,omitempty"`
+	Other string `xml:"Id>Othr>Id,omitempty"`
+}
+
+// accountID returns the account ID the account is identified by.
+func (account accountIdentification) accountID() (int64, error) {
+	if account.IBAN != "" {
+		return 0, ErrIBANNotSupported
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(account.Other), 10, 64)
+	if err != nil || id < 1 {
+		return 0, ErrInvalidAccountID
+	}
+	return id, nil
+}
+
+// amount is an amount of money in a currency.
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// parseAmount parses a decimal amount of the currency's major units into minor units, e.g. "100.50" USD as 10050.
+func parseAmount(value string, currency string) (int64, error) {
+	n, err := util.ParseMajorUnits(value, currency)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	return n, nil
+}
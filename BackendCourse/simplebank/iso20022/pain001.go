@@ -0,0 +1,125 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// pain001NamespacePrefix is the namespace of every version of pain.001 without the version.
+const pain001NamespacePrefix = "urn:iso:std:iso:20022:tech:xsd:pain.001."
+
+var (
+	// ErrNotPain001 is returned for an XML document that isn't a pain.001 message.
+	ErrNotPain001 = errors.New("the document isn't a pain.001 customer credit transfer initiation")
+	// ErrNoTransfers is returned for a pain.001 message without any credit transfer.
+	ErrNoTransfers = errors.New("the message has no credit transfers")
+)
+
+type painTransaction struct {
+	InstructionID string                `xml:"PmtId>InstrId"`
+	EndToEndID    string                `xml:"PmtId>EndToEndId"`
+	Amount        amount                `xml:"Amt>InstdAmt"`
+	Creditor      string                `xml:"Cdtr>Nm"`
+	CreditorAcct  accountIdentification `xml:"CdtrAcct"`
+	Remittance    string                `xml:"RmtInf>Ustrd"`
+}
+
+type painPaymentInformation struct {
+	ID           string                `xml:"PmtInfId"`
+	DebtorAcct   accountIdentification `xml:"DbtrAcct"`
+	Transactions []painTransaction     `xml:"CdtTrfTxInf"`
+}
+
+type pain001Document struct {
+	XMLName xml.Name `xml:"Document"`
+	Header  struct {
+		MessageID         string `xml:"MsgId"`
+		NumberOfTransfers string `xml:"NbOfTxs"`
+	} `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	Payments []painPaymentInformation `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+// CreditTransfer is one credit transfer instruction of a pain.001 message.
+// Err is set when the instruction can't be read, e.g. for an account identified by IBAN,
+// the other fields are then only as complete as the instruction.
+type CreditTransfer struct {
+	PaymentInformationID string `json:"payment_information_id"`
+	InstructionID        string `json:"instruction_id"`
+	EndToEndID           string `json:"end_to_end_id"`
+	DebtorAccountID      int64  `json:"debtor_account_id"`
+	CreditorAccountID    int64  `json:"creditor_account_id"`
+	CreditorName         string `json:"creditor_name"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	RemittanceInfo       string `json:"remittance_info"`
+	Err                  error  `json:"-"`
+}
+
+// CreditTransferInitiation is a pain.001 message: the credit transfers a customer asks the bank to make.
+type CreditTransferInitiation struct {
+	MessageID string           `json:"message_id"`
+	Transfers []CreditTransfer `json:"transfers"`
+}
+
+// ParsePain001 reads a pain.001 customer credit transfer initiation, of any version.
+// An error is only returned when the message as a whole can't be read or its number of transactions
+// is wrong. Instructions that can't be read are returned with their Err set, so each can be reported.
+func ParsePain001(r io.Reader) (CreditTransferInitiation, error) {
+	var initiation CreditTransferInitiation
+
+	var doc pain001Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return initiation, err
+	}
+	if !strings.HasPrefix(doc.XMLName.Space, pain001NamespacePrefix) {
+		return initiation, ErrNotPain001
+	}
+	initiation.MessageID = doc.Header.MessageID
+
+	for _, payment := range doc.Payments {
+		debtorAccountID, debtorErr := payment.DebtorAcct.accountID()
+
+		for _, transaction := range payment.Transactions {
+			transfer := CreditTransfer{
+				PaymentInformationID: payment.ID,
+				InstructionID:        transaction.InstructionID,
+				EndToEndID:           transaction.EndToEndID,
+				DebtorAccountID:      debtorAccountID,
+				CreditorName:         transaction.Creditor,
+				Currency:             transaction.Amount.Currency,
+				RemittanceInfo:       transaction.Remittance,
+			}
+			if transfer.EndToEndID == notProvided {
+				transfer.EndToEndID = ""
+			}
+
+			var err error
+			transfer.Amount, err = parseAmount(transaction.Amount.Value, transaction.Amount.Currency)
+			if err != nil {
+				transfer.Err = err
+			}
+			transfer.CreditorAccountID, err = transaction.CreditorAcct.accountID()
+			if err != nil {
+				transfer.Err = fmt.Errorf("creditor account: %w", err)
+			}
+			if debtorErr != nil {
+				transfer.Err = fmt.Errorf("debtor account: %w", debtorErr)
+			}
+
+			initiation.Transfers = append(initiation.Transfers, transfer)
+		}
+	}
+
+	if len(initiation.Transfers) == 0 {
+		return initiation, ErrNoTransfers
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(doc.Header.NumberOfTransfers)); err != nil || n != len(initiation.Transfers) {
+		return initiation, fmt.Errorf("the number of transactions is %q but the message has %d", doc.Header.NumberOfTransfers, len(initiation.Transfers))
+	}
+
+	return initiation, nil
+}
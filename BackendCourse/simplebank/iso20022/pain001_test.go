@@ -0,0 +1,112 @@
+package iso20022
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+const testPain001 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>MSG-1</MsgId>
+      <CreDtTm>2026-03-01T10:00:00Z</CreDtTm>
+      <NbOfTxs>3</NbOfTxs>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <DbtrAcct><Id><Othr><Id>12</Id></Othr></Id></DbtrAcct>
+      <CdtTrfTxInf>
+        <PmtId><InstrId>I-1</InstrId><EndToEndId>INV-1001</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="USD">100.50</InstdAmt></Amt>
+        <Cdtr><Nm>Acme</Nm></Cdtr>
+        <CdtrAcct><Id><Othr><Id>34</Id></Othr></Id></CdtrAcct>
+        <RmtInf><Ustrd>Invoice 1001</Ustrd></RmtInf>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>NOTPROVIDED</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="USD">10.505</InstdAmt></Amt>
+        <CdtrAcct><Id><Othr><Id>35</Id></Othr></Id></CdtrAcct>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>INV-1003</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="USD">5</InstdAmt></Amt>
+        <CdtrAcct><Id><IBAN>DE89370400440532013000</IBAN></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`
+
+func TestParsePain001(t *testing.T) {
+	initiation, err := ParsePain001(strings.NewReader(testPain001))
+	require.NoError(t, err)
+	require.Equal(t, "MSG-1", initiation.MessageID)
+	require.Len(t, initiation.Transfers, 3)
+
+	require.Equal(t, CreditTransfer{
+		PaymentInformationID: "PMT-1",
+		InstructionID:        "I-1",
+		EndToEndID:           "INV-1001",
+		DebtorAccountID:      12,
+		CreditorAccountID:    34,
+		CreditorName:         "Acme",
+		Amount:               10050,
+		Currency:             "USD",
+		RemittanceInfo:       "Invoice 1001",
+	}, initiation.Transfers[0])
+
+	require.Empty(t, initiation.Transfers[1].EndToEndID)
+	require.ErrorIs(t, initiation.Transfers[1].Err, ErrInvalidAmount)
+	require.ErrorIs(t, initiation.Transfers[2].Err, ErrIBANNotSupported)
+}
+
+func TestParsePain001Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		document string
+	}{
+		{
+			name:     "NotXML",
+			document: "MsgId,Amount",
+		},
+		{
+			name:     "OtherMessage",
+			document: strings.Replace(testPain001, "pain.001.001.09", "pain.008.001.08", 1),
+		},
+		{
+			name:     "WrongNumberOfTransactions",
+			document: strings.Replace(testPain001, "<NbOfTxs>3</NbOfTxs>", "<NbOfTxs>2</NbOfTxs>", 1),
+		},
+		{
+			name: "NoTransfers",
+			document: `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+				<CstmrCdtTrfInitn><GrpHdr><MsgId>MSG-2</MsgId><NbOfTxs>0</NbOfTxs></GrpHdr></CstmrCdtTrfInitn>
+			</Document>`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePain001(strings.NewReader(tc.document))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for value, want := range map[string]int64{"100": 10000, "100.50": 10050, "1.5": 150, " 7.0 ": 700} {
+		n, err := parseAmount(value, util.USD)
+		require.NoError(t, err)
+		require.Equal(t, want, n)
+	}
+
+	for _, value := range []string{"", "1.005", "1,00", "-1", "ten"} {
+		_, err := parseAmount(value, util.USD)
+		require.ErrorIs(t, err, ErrInvalidAmount)
+	}
+}
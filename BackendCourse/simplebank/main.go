@@ -1,17 +1,29 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"log"
+	"os"
+	"time"
 
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/api"
 	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
 	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/webhook"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/worker"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 func main() {
+	reconcile := flag.Bool("reconcile", false, "reconcile the ledger once, print the report as JSON and exit")
+	flag.Parse()
+
 	config, err := util.LoadConfig(".")
 	if err != nil {
 		log.Fatal("cannot load config:", err)
@@ -22,7 +34,47 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
-	server, err := api.NewServer(config, store)
+
+	if *reconcile {
+		runReconciliation(store)
+		return
+	}
+
+	notifier := notify.NewLogNotifier()
+
+	sink, err := events.NewSink(config.OutboxSink, config.OutboxSinkTarget)
+	if err != nil {
+		log.Fatal("cannot create event sink:", err)
+	}
+	// the events are also delivered to the users' webhook endpoints subscribed to them
+	sink = events.NewMultiSink(sink, webhook.NewDispatcher(store))
+
+	// Start the background jobs
+	scheduler := worker.NewScheduler()
+	scheduler.Every("overdraft_interest", config.OverdraftInterestInterval,
+		worker.OverdraftInterestJob(store, config.OverdraftInterestRateBps))
+	scheduler.Every("hold_expiry", config.HoldExpiryInterval, worker.HoldExpiryJob(store))
+	scheduler.Every("standing_orders", config.StandingOrderInterval, worker.StandingOrderJob(store))
+	scheduler.Every("money_request_expiry", config.MoneyRequestExpiryInterval, worker.MoneyRequestExpiryJob(store, notifier))
+	scheduler.Every("interest_accrual", config.InterestAccrualInterval, worker.InterestAccrualJob(store))
+	scheduler.Every("interest_posting", config.InterestPostingInterval, worker.InterestPostingJob(store))
+	scheduler.Every("loan_repayment", config.LoanRepaymentInterval,
+		worker.LoanRepaymentJob(store, config.LoanLateFee, config.LoanLateFeeGracePeriod))
+	scheduler.Every("reconciliation", config.ReconciliationInterval, worker.ReconciliationJob(store))
+	scheduler.Every("balance_snapshot", config.BalanceSnapshotInterval, worker.BalanceSnapshotJob(store))
+	scheduler.Every("outbox_relay", config.OutboxRelayInterval, worker.OutboxRelayJob(store, sink))
+	scheduler.Every("webhook_delivery", config.WebhookDeliveryInterval, worker.WebhookDeliveryJob(store, webhook.NewClient()))
+	scheduler.Start(context.Background())
+
+	// the account event streams are woken up by the notifications of new outbox events
+	hub := events.NewHub()
+	listener := pq.NewListener(config.DBSource, 10*time.Second, time.Minute, nil)
+	if err := listener.Listen(events.OutboxChannel); err != nil {
+		log.Fatal("cannot listen for outbox events:", err)
+	}
+	go events.ForwardNotifications(context.Background(), listener.Notify, hub)
+
+	server, err := api.NewServer(config, store, notifier, hub)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
@@ -34,3 +86,22 @@ func main() {
 		log.Fatal("cannot start server:", err)
 	}
 }
+
+// runReconciliation prints the reconciliation report to stdout
+// and exits with status 1 if the ledger doesn't reconcile.
+func runReconciliation(store db.Store) {
+	report, err := worker.Reconcile(context.Background(), store)
+	if err != nil {
+		log.Fatal("cannot reconcile the ledger:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("cannot write the reconciliation report:", err)
+	}
+
+	if !report.Consistent() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// LogNotifier is a Notifier that writes notifications to the log.
+// It is used until a real delivery channel is configured.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier
+func NewLogNotifier() Notifier {
+	return &LogNotifier{}
+}
+
+// Notify writes the notification to the log
+func (notifier *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("notification: %s", data)
+	return nil
+}
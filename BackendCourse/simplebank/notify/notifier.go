@@ -0,0 +1,29 @@
+package notify
+
+import "context"
+
+// Notification tells a user about something that happened that concerns them.
+type Notification struct {
+	Username string `json:"username"`
+	// Kind lets channels pick a template, e.g. "money_request.created"
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Data holds the object the notification is about, for channels that can show its details
+	Data any `json:"data,omitempty"`
+}
+
+// Notifier is an interface for delivering notifications to users.
+// Implementations can send them by email, push or any other channel.
+type Notifier interface {
+	// Notify delivers the notification. An error means it wasn't delivered; callers
+	// treat notifications as best effort and don't fail what they were doing because of it.
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// Notification kinds
+const (
+	KindMoneyRequestCreated  = "money_request.created"
+	KindMoneyRequestAccepted = "money_request.accepted"
+	KindMoneyRequestDeclined = "money_request.declined"
+	KindMoneyRequestExpired  = "money_request.expired"
+)
@@ -0,0 +1,50 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// WriteCSV renders the statement as CSV: a row per entry with its running balance,
+// between rows for the opening and closing balances. Amounts are in the currency's major units.
+func (statement Statement) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	amount := func(amount int64) string {
+		return util.FormatMajorUnits(amount, statement.Currency)
+	}
+
+	writer.Write([]string{"time", "entry_id", "description", "reference", "category", "amount", "balance"})
+	writer.Write([]string{statement.From.Format(time.RFC3339), "", "Opening balance", "", "", "",
+		amount(statement.OpeningBalance)})
+	for _, line := range statement.Lines {
+		writer.Write([]string{
+			line.Time.UTC().Format(time.RFC3339),
+			strconv.FormatInt(line.EntryID, 10),
+			csvText(line.Description),
+			csvText(line.Reference),
+			csvText(line.Category),
+			amount(line.Amount),
+			amount(line.Balance),
+		})
+	}
+	writer.Write([]string{statement.To.Format(time.RFC3339), "", "Closing balance", "", "", "",
+		amount(statement.ClosingBalance)})
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText keeps a text cell from being read as a formula by spreadsheets.
+// Descriptions and references come from the counterparty, so a cell that starts like a formula
+// is prefixed with a quote so spreadsheets show it as plain text.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
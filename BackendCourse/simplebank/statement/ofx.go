@@ -0,0 +1,126 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// ofxBankID identifies the bank in OFX files.
+const ofxBankID = "SIMPLEBANK"
+
+// ofxNameLength is the longest payee name OFX allows.
+const ofxNameLength = 32
+
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+	`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FITID  string `xml:"FITID"`
+	Name   string `xml:"NAME,omitempty"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Status   ofxStatus `xml:"SONRS>STATUS"`
+		Server   string    `xml:"SONRS>DTSERVER"`
+		Language string    `xml:"SONRS>LANGUAGE"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		TransactionID string    `xml:"TRNUID"`
+		Status        ofxStatus `xml:"STATUS"`
+		Statement     struct {
+			Currency string `xml:"CURDEF"`
+			Account  struct {
+				BankID string `xml:"BANKID"`
+				ID     string `xml:"ACCTID"`
+				Type   string `xml:"ACCTTYPE"`
+			} `xml:"BANKACCTFROM"`
+			Transactions struct {
+				Start string           `xml:"DTSTART"`
+				End   string           `xml:"DTEND"`
+				List  []ofxTransaction `xml:"STMTTRN"`
+			} `xml:"BANKTRANLIST"`
+			LedgerBalance struct {
+				Amount string `xml:"BALAMT"`
+				AsOf   string `xml:"DTASOF"`
+			} `xml:"LEDGERBAL"`
+		} `xml:"STMTRS"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// WriteOFX renders the statement as an OFX 2.2 bank statement, for import into personal finance tools.
+// Every entry is a transaction identified by its entry ID, so importing overlapping statements doesn't duplicate it.
+// OFX amounts are decimal numbers of the currency's major units, e.g. "-12.50".
+func (statement Statement) WriteOFX(w io.Writer) error {
+	var doc ofxDocument
+	doc.SignOn.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Server = ofxTime(statement.GeneratedAt)
+	doc.SignOn.Language = "ENG"
+
+	doc.Bank.TransactionID = "0"
+	doc.Bank.Status = ofxStatus{Code: 0, Severity: "INFO"}
+
+	stmt := &doc.Bank.Statement
+	stmt.Currency = statement.Currency
+	stmt.Account.BankID = ofxBankID
+	stmt.Account.ID = strconv.FormatInt(statement.AccountID, 10)
+	stmt.Account.Type = "CHECKING"
+	if statement.AccountType == util.SavingsAccount || statement.AccountType == util.PocketAccount {
+		stmt.Account.Type = "SAVINGS"
+	}
+
+	stmt.Transactions.Start = ofxTime(statement.From)
+	stmt.Transactions.End = ofxTime(statement.To)
+	stmt.Transactions.List = make([]ofxTransaction, len(statement.Lines))
+	for i, line := range statement.Lines {
+		transactionType := "CREDIT"
+		if line.Amount < 0 {
+			transactionType = "DEBIT"
+		}
+		stmt.Transactions.List[i] = ofxTransaction{
+			Type:   transactionType,
+			Posted: ofxTime(line.Time),
+			Amount: util.FormatMajorUnits(line.Amount, statement.Currency),
+			FITID:  strconv.FormatInt(line.EntryID, 10),
+			Name:   truncate(line.Description, ofxNameLength),
+			Memo:   line.Reference,
+		}
+	}
+
+	stmt.LedgerBalance.Amount = util.FormatMajorUnits(statement.ClosingBalance, statement.Currency)
+	stmt.LedgerBalance.AsOf = ofxTime(statement.To)
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// truncate shortens s to at most n characters.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+)
+
+// Layout of the PDF pages: A4 in points, Courier at 9 points, which is 5.4 points wide per character.
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 9
+	pdfLeading      = 11
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// Widths of the columns of the PDF statement, in characters.
+const (
+	pdfDateWidth        = 10
+	pdfDescriptionWidth = 32
+	pdfReferenceWidth   = 16
+	pdfAmountWidth      = 14
+)
+
+// WritePDF renders the statement as a PDF document of plain text pages.
+func (statement Statement) WritePDF(w io.Writer) error {
+	return writeTextPDF(w, statement.textLines())
+}
+
+// textLines lays the statement out as lines of fixed-width text, with amounts in the currency's major units.
+func (statement Statement) textLines() []string {
+	row := func(date, description, reference, amount, balance string) string {
+		return fmt.Sprintf("%-*s %-*s %-*s %*s %*s",
+			pdfDateWidth, date,
+			pdfDescriptionWidth, truncate(description, pdfDescriptionWidth),
+			pdfReferenceWidth, truncate(reference, pdfReferenceWidth),
+			pdfAmountWidth, amount,
+			pdfAmountWidth, balance)
+	}
+	amount := func(amount int64) string {
+		return util.FormatMajorUnits(amount, statement.Currency)
+	}
+
+	lines := []string{
+		fmt.Sprintf("Statement of account %d (%s, %s)", statement.AccountID, statement.AccountType, statement.Currency),
+		"Owner: " + statement.Owner,
+		fmt.Sprintf("Period: %s to %s", statement.From.Format(time.DateOnly), statement.lastDay().Format(time.DateOnly)),
+		"",
+		row("Date", "Description", "Reference", "Amount", "Balance"),
+		row(statement.From.Format(time.DateOnly), "Opening balance", "", "", amount(statement.OpeningBalance)),
+	}
+	for _, line := range statement.Lines {
+		description := line.Description
+		if description == "" {
+			description = line.Category
+		}
+		lines = append(lines, row(line.Time.UTC().Format(time.DateOnly), description, line.Reference,
+			amount(line.Amount), amount(line.Balance)))
+	}
+	lines = append(lines,
+		row(statement.lastDay().Format(time.DateOnly), "Closing balance", "", "", amount(statement.ClosingBalance)),
+		"",
+		"Generated at "+statement.GeneratedAt.UTC().Format(time.RFC3339),
+	)
+
+	return lines
+}
+
+// pdfEscaper escapes the characters that are special in PDF strings.
+var pdfEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+
+// pdfText makes a line safe to show with the standard Courier font, which only has Latin characters.
+func pdfText(line string) string {
+	var b strings.Builder
+	for _, r := range line {
+		if r < 0x20 || r > 0x7e {
+			r = '?'
+		}
+		b.WriteRune(r)
+	}
+	return pdfEscaper.Replace(b.String())
+}
+
+// writeTextPDF writes a PDF 1.4 document showing the lines in Courier, as many pages as they take.
+// Objects 1 to 3 are the catalog, the page tree and the font; every page then takes two objects,
+// the page and its content stream.
+func writeTextPDF(w io.Writer, lines []string) error {
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdfText(line))
+		}
+		content.WriteString("ET")
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
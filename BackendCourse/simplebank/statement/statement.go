@@ -0,0 +1,71 @@
+// Package statement builds account statements and renders them for customers and auditors.
+package statement
+
+import (
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// Line is one entry of the statement with the balance right after it.
+type Line struct {
+	EntryID     int64     `json:"entry_id"`
+	Time        time.Time `json:"time"`
+	Description string    `json:"description"`
+	Reference   string    `json:"reference"`
+	Category    string    `json:"category"`
+	Amount      int64     `json:"amount"`
+	Balance     int64     `json:"balance"`
+}
+
+// Statement lists the entries of an account over a period, between its opening and closing balances.
+// The period starts at From and ends just before To.
+type Statement struct {
+	AccountID      int64     `json:"account_id"`
+	Owner          string    `json:"owner"`
+	AccountType    string    `json:"account_type"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance int64     `json:"opening_balance"`
+	ClosingBalance int64     `json:"closing_balance"`
+	Lines          []Line    `json:"lines"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+// New builds the statement of the account from its balance at from and its entries up to to, oldest first.
+func New(account db.Accounts, from, to time.Time, openingBalance int64, entries []db.Entries) Statement {
+	statement := Statement{
+		AccountID:      account.ID,
+		Owner:          account.Owner,
+		AccountType:    account.Type,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: openingBalance,
+		Lines:          make([]Line, len(entries)),
+		GeneratedAt:    time.Now().UTC(),
+	}
+
+	balance := openingBalance
+	for i, entry := range entries {
+		balance += entry.Amount
+		statement.Lines[i] = Line{
+			EntryID:     entry.ID,
+			Time:        entry.CreatedAt,
+			Description: entry.Description,
+			Reference:   entry.Reference,
+			Category:    entry.Category,
+			Amount:      entry.Amount,
+			Balance:     balance,
+		}
+	}
+	statement.ClosingBalance = balance
+
+	return statement
+}
+
+// lastDay is the last day the statement covers.
+func (statement Statement) lastDay() time.Time {
+	return statement.To.AddDate(0, 0, -1)
+}
@@ -0,0 +1,128 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomStatement(entryCount int) Statement {
+	account := db.Accounts{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    util.RandomOwner(),
+		Currency: util.RandomCurrency(),
+		Type:     util.SavingsAccount,
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	entries := make([]db.Entries, entryCount)
+	for i := range entries {
+		entries[i] = db.Entries{
+			ID:          int64(i + 1),
+			AccountID:   account.ID,
+			Amount:      util.RandomInt(-100, 100),
+			CreatedAt:   from.Add(time.Duration(i) * time.Hour),
+			Description: "Payment (" + util.RandomString(6) + ")",
+		}
+	}
+
+	return New(account, from, from.AddDate(0, 1, 0), util.RandomMoney(), entries)
+}
+
+func TestNew(t *testing.T) {
+	statement := randomStatement(5)
+
+	balance := statement.OpeningBalance
+	for _, line := range statement.Lines {
+		balance += line.Amount
+		require.Equal(t, balance, line.Balance)
+	}
+	require.Equal(t, balance, statement.ClosingBalance)
+	require.Equal(t, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), statement.lastDay())
+}
+
+func TestWriteCSV(t *testing.T) {
+	statement := randomStatement(3)
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+	require.Equal(t, util.FormatMajorUnits(statement.OpeningBalance, statement.Currency), records[1][6])
+	require.Equal(t, statement.Lines[0].Description, records[2][2])
+	require.Equal(t, util.FormatMajorUnits(statement.Lines[0].Amount, statement.Currency), records[2][5])
+	require.Equal(t, util.FormatMajorUnits(statement.ClosingBalance, statement.Currency), records[5][6])
+}
+
+func TestWriteCSVFormulaCells(t *testing.T) {
+	statement := randomStatement(4)
+	statement.Lines[0].Description = "=HYPERLINK(\"http://example.com\")"
+	statement.Lines[1].Reference = "+1+1"
+	statement.Lines[2].Description = "@SUM(A1)"
+	statement.Lines[3].Description = "-2+3"
+	statement.Lines[3].Amount = -1250
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "'=HYPERLINK(\"http://example.com\")", records[2][2])
+	require.Equal(t, "'+1+1", records[3][3])
+	require.Equal(t, "'@SUM(A1)", records[4][2])
+	require.Equal(t, "'-2+3", records[5][2])
+	// amounts stay numbers
+	require.Equal(t, util.FormatMajorUnits(-1250, statement.Currency), records[5][5])
+}
+
+func TestWriteOFX(t *testing.T) {
+	statement := randomStatement(3)
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteOFX(&buf))
+	require.True(t, strings.HasPrefix(buf.String(), "<?xml"))
+
+	var doc ofxDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	stmt := doc.Bank.Statement
+	require.Equal(t, statement.Currency, stmt.Currency)
+	require.Equal(t, fmt.Sprint(statement.AccountID), stmt.Account.ID)
+	require.Equal(t, "SAVINGS", stmt.Account.Type)
+	require.Equal(t, "20260301000000[0:GMT]", stmt.Transactions.Start)
+	require.Len(t, stmt.Transactions.List, 3)
+	require.Equal(t, "1", stmt.Transactions.List[0].FITID)
+	require.Equal(t, util.FormatMajorUnits(statement.Lines[0].Amount, statement.Currency), stmt.Transactions.List[0].Amount)
+	require.Equal(t, util.FormatMajorUnits(statement.ClosingBalance, statement.Currency), stmt.LedgerBalance.Amount)
+}
+
+func TestWritePDF(t *testing.T) {
+	// enough entries for more than one page
+	statement := randomStatement(pdfLinesPerPage)
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.WritePDF(&buf))
+
+	pdf := buf.String()
+	require.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	require.Contains(t, pdf, "/Count 2")
+	require.Contains(t, pdf, `Payment \(`)
+	require.Contains(t, pdf, util.FormatMajorUnits(statement.ClosingBalance, statement.Currency))
+
+	// the xref table points at every object
+	var xref int
+	_, err := fmt.Sscanf(pdf[strings.LastIndex(pdf, "startxref\n"):], "startxref\n%d", &xref)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(pdf[xref:], "xref\n0 8\n"))
+}
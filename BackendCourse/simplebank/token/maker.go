@@ -3,8 +3,8 @@ package token
 import "time"
 
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, error)
+	// CreateToken creates a new token for a specific username, role and duration
+	CreateToken(username string, role string, duration time.Duration) (string, error)
 	// VerifyToken checks if the token is valid and returns the username if it is
 	VerifyToken(token string) (*Payload, error)
 }
@@ -14,14 +14,15 @@ var ErrInvalidToken = errors.New("token is invalid")
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-// NewPayload creates a new token payload with a unique ID, the specified username,
+// NewPayload creates a new token payload with a unique ID, the specified username and role,
 // and sets the creation and expiration times based on the provided duration.
 // Returns the created Payload or an error if the token ID could not be generated.
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -29,6 +30,7 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
 		CreatedAt: time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -0,0 +1,11 @@
+package util
+
+// Account types. Savings accounts earn interest at the rates set for them.
+// Pockets are sub-accounts set aside inside a checking or savings account.
+// System accounts belong to the bank itself, e.g. the accounts collecting fees.
+const (
+	CheckingAccount = "checking"
+	SavingsAccount  = "savings"
+	PocketAccount   = "pocket"
+	SystemAccount   = "system"
+)
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"math"
+	"time"
+)
+
+// Installment is one monthly repayment of a loan.
+type Installment struct {
+	DueDate   time.Time
+	Principal int64
+	Interest  int64
+}
+
+// AmortizationSchedule splits a loan of principal at the given annual rate, in basis points,
+// into months equal monthly installments, the first one due a month after start.
+// Each installment pays the month's interest on the outstanding principal, rounded half up,
+// and the rest of the payment goes to the principal. The last installment pays off whatever
+// principal is left, so it can differ from the others by rounding.
+func AmortizationSchedule(principal int64, annualRateBps int64, months int, start time.Time) []Installment {
+	// monthly rate as a fraction is annualRateBps / (10000 * 12)
+	const denominator = 10000 * 12
+
+	var payment int64
+	if annualRateBps == 0 {
+		payment = principal / int64(months)
+	} else {
+		rate := float64(annualRateBps) / denominator
+		payment = int64(math.Round(float64(principal) * rate / (1 - math.Pow(1+rate, -float64(months)))))
+	}
+
+	schedule := make([]Installment, months)
+	balance := principal
+	for i := range schedule {
+		interest := (balance*annualRateBps + denominator/2) / denominator
+		paid := payment - interest
+		if i == months-1 || paid > balance {
+			paid = balance
+		}
+		if paid < 0 {
+			paid = 0
+		}
+		balance -= paid
+
+		schedule[i] = Installment{
+			DueDate:   addMonths(start, i+1),
+			Principal: paid,
+			Interest:  interest,
+		}
+	}
+	return schedule
+}
+
+// addMonths returns the same day months later, or the last day of that month if it is shorter,
+// so a loan taken on January 31st is due on February 28th rather than in March.
+func addMonths(t time.Time, months int) time.Time {
+	lastDay := time.Date(t.Year(), t.Month()+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(t.Year(), t.Month()+time.Month(months), day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmortizationSchedule(t *testing.T) {
+	start := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	// 100000 at 12% a year over 12 months is a monthly payment of 8885
+	schedule := AmortizationSchedule(100000, 1200, 12, start)
+	require.Len(t, schedule, 12)
+	require.Equal(t, int64(1000), schedule[0].Interest)
+	require.Equal(t, int64(7885), schedule[0].Principal)
+
+	var principal int64
+	for i, installment := range schedule {
+		principal += installment.Principal
+		if i < len(schedule)-1 {
+			require.Equal(t, int64(8885), installment.Principal+installment.Interest)
+		}
+	}
+	require.Equal(t, int64(100000), principal)
+
+	// due dates keep the day of the month where it exists
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), schedule[0].DueDate)
+	require.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), schedule[1].DueDate)
+	require.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), schedule[11].DueDate)
+}
+
+func TestAmortizationScheduleWithoutInterest(t *testing.T) {
+	schedule := AmortizationSchedule(1000, 0, 3, time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC))
+	require.Len(t, schedule, 3)
+	require.Equal(t, int64(333), schedule[0].Principal)
+	require.Equal(t, int64(333), schedule[1].Principal)
+	require.Equal(t, int64(334), schedule[2].Principal)
+	for _, installment := range schedule {
+		require.Zero(t, installment.Interest)
+	}
+}
@@ -13,6 +13,41 @@ type Config struct {
 	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
 	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	// annual overdraft interest rate in basis points, charged daily on negative balances
+	OverdraftInterestRateBps  int64         `mapstructure:"OVERDRAFT_INTEREST_RATE_BPS"`
+	OverdraftInterestInterval time.Duration `mapstructure:"OVERDRAFT_INTEREST_INTERVAL"`
+	// how long an authorized transfer holds the funds before it expires
+	HoldDuration       time.Duration `mapstructure:"HOLD_DURATION"`
+	HoldExpiryInterval time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+	// how often due standing orders are looked for
+	StandingOrderInterval time.Duration `mapstructure:"STANDING_ORDER_INTERVAL"`
+	// for how long after a payee is added only amounts up to the max can be paid to it; 0 disables the cooling-off
+	PayeeCoolingOffPeriod    time.Duration `mapstructure:"PAYEE_COOLING_OFF_PERIOD"`
+	PayeeCoolingOffMaxAmount int64         `mapstructure:"PAYEE_COOLING_OFF_MAX_AMOUNT"`
+	// how long a money request waits for its payer before it expires
+	MoneyRequestDuration       time.Duration `mapstructure:"MONEY_REQUEST_DURATION"`
+	MoneyRequestExpiryInterval time.Duration `mapstructure:"MONEY_REQUEST_EXPIRY_INTERVAL"`
+	// how often interest is accrued and posted; both jobs only act once per day and month
+	InterestAccrualInterval time.Duration `mapstructure:"INTEREST_ACCRUAL_INTERVAL"`
+	InterestPostingInterval time.Duration `mapstructure:"INTEREST_POSTING_INTERVAL"`
+	// how often due loan installments are collected, and the flat fee added to one still unpaid after the grace period
+	LoanRepaymentInterval  time.Duration `mapstructure:"LOAN_REPAYMENT_INTERVAL"`
+	LoanLateFee            int64         `mapstructure:"LOAN_LATE_FEE"`
+	LoanLateFeeGracePeriod time.Duration `mapstructure:"LOAN_LATE_FEE_GRACE_PERIOD"`
+	// how often balances are reconciled with their entries
+	ReconciliationInterval time.Duration `mapstructure:"RECONCILIATION_INTERVAL"`
+	// how often end of day balances are snapshotted; the job only acts once per day
+	BalanceSnapshotInterval time.Duration `mapstructure:"BALANCE_SNAPSHOT_INTERVAL"`
+	// how often the outbox is relayed, and where its events are published: log, file or webhook,
+	// with the target being the path of the file or the URL of the webhook
+	OutboxRelayInterval time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	OutboxSink          string        `mapstructure:"OUTBOX_SINK"`
+	OutboxSinkTarget    string        `mapstructure:"OUTBOX_SINK_TARGET"`
+	// how often due deliveries to the users' webhook endpoints are sent
+	WebhookDeliveryInterval time.Duration `mapstructure:"WEBHOOK_DELIVERY_INTERVAL"`
+	// the comma separated IPs or CIDRs of the proxies whose X-Forwarded-For headers are trusted
+	// for the client IP of a request; by default none is, and the client IP is the remote address
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Supported currencies
+const (
+	USD = "USD"
+	EUR = "EUR"
+	CAD = "CAD"
+)
+
+// ErrInvalidMajorUnits is returned for a decimal amount that isn't a whole number of minor units of its currency.
+var ErrInvalidMajorUnits = errors.New("amount must be a decimal number with at most as many decimals as its currency has")
+
+// minorUnitDigits is the number of decimals of each currency's minor unit, e.g. 2 for the cents of USD.
+// Amounts are always stored in minor units; other currencies are assumed to have cents too.
+var minorUnitDigits = map[string]int{
+	USD: 2,
+	EUR: 2,
+	CAD: 2,
+}
+
+func currencyDigits(currency string) int {
+	if digits, ok := minorUnitDigits[currency]; ok {
+		return digits
+	}
+	return 2
+}
+
+// FormatMajorUnits formats an amount in minor units as a decimal number of the currency's major units,
+// e.g. 10050 USD as "100.50" and -5 USD as "-0.05", for formats that expect amounts in major units.
+func FormatMajorUnits(amount int64, currency string) string {
+	digits := currencyDigits(currency)
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// ParseMajorUnits parses a decimal number of the currency's major units into minor units,
+// e.g. "100.5" USD as 10050. It fails if the number has more decimals than the currency's minor unit,
+// unless they are zeros, or if it has a sign, since amounts in exchanged files are never negative.
+func ParseMajorUnits(value string, currency string) (int64, error) {
+	digits := currencyDigits(currency)
+
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if len(fraction) > digits {
+		if strings.Trim(fraction[digits:], "0") != "" {
+			return 0, ErrInvalidMajorUnits
+		}
+		fraction = fraction[:digits]
+	}
+	if whole == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return 0, ErrInvalidMajorUnits
+	}
+
+	n, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidMajorUnits
+	}
+	return n, nil
+}
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMajorUnits(t *testing.T) {
+	for amount, want := range map[int64]string{10050: "100.50", 100: "1.00", 5: "0.05", 0: "0.00", -5: "-0.05", -123456: "-1234.56"} {
+		require.Equal(t, want, FormatMajorUnits(amount, USD))
+	}
+}
+
+func TestParseMajorUnits(t *testing.T) {
+	for value, want := range map[string]int64{"100": 10000, "100.5": 10050, "100.50": 10050, " 7.000 ": 700, "0.05": 5} {
+		n, err := ParseMajorUnits(value, EUR)
+		require.NoError(t, err)
+		require.Equal(t, want, n)
+	}
+
+	for _, value := range []string{"", ".5", "1.005", "1,00", "-1", "+1", "ten", "1.2.3"} {
+		_, err := ParseMajorUnits(value, EUR)
+		require.ErrorIs(t, err, ErrInvalidMajorUnits)
+	}
+}
@@ -0,0 +1,19 @@
+package util
+
+// DailyInterest returns one day of interest on amount at the given annual rate,
+// expressed in basis points (1 bps = 0.01%), using a 365 day year.
+// The result is rounded half away from zero to the nearest minor unit.
+func DailyInterest(amount int64, annualRateBps int64) int64 {
+	const denominator = 10000 * 365
+
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	interest := (amount*annualRateBps + denominator/2) / denominator
+	if negative {
+		return -interest
+	}
+	return interest
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyInterest(t *testing.T) {
+	// 18.25% a year on 10000 is exactly 5 a day
+	require.Equal(t, int64(5), DailyInterest(10000, 1825))
+	require.Equal(t, int64(-5), DailyInterest(-10000, 1825))
+
+	// rounding half away from zero
+	require.Equal(t, int64(1), DailyInterest(2000, 1825))
+	require.Equal(t, int64(0), DailyInterest(999, 1825))
+	require.Equal(t, int64(-1), DailyInterest(-2000, 1825))
+
+	require.Zero(t, DailyInterest(0, 1825))
+	require.Zero(t, DailyInterest(10000, 0))
+}
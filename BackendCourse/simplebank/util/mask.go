@@ -0,0 +1,18 @@
+package util
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaskName hides most of a person's name, keeping the first letter of each part of it,
+// e.g. "John Smith" becomes "J*** S****". It is enough for a payer to recognize the payee
+// without giving their full name away to anyone who knows their username or email.
+func MaskName(name string) string {
+	parts := strings.Fields(name)
+	for i, part := range parts {
+		first, size := utf8.DecodeRuneInString(part)
+		parts[i] = string(first) + strings.Repeat("*", utf8.RuneCountInString(part[size:]))
+	}
+	return strings.Join(parts, " ")
+}
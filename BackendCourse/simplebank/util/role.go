@@ -0,0 +1,8 @@
+package util
+
+// Roles a user can have. Every new user is a depositor; bankers are
+// bank staff who can manage other customers' accounts.
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+)
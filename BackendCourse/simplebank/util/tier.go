@@ -0,0 +1,8 @@
+package util
+
+// User tiers. A user's tier selects the transfer limits of their accounts;
+// every new user starts in the standard tier.
+const (
+	StandardTier = "standard"
+	PremiumTier  = "premium"
+)
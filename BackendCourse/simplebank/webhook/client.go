@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// Timeout is how long an endpoint has to answer a delivery.
+const Timeout = 10 * time.Second
+
+// Headers sent with every delivery, besides the signature. A delivery that is retried or replayed
+// keeps its ID, so receivers can tell it apart from a new one.
+const (
+	DeliveryIDHeader = "X-Webhook-Delivery-ID"
+	EventIDHeader    = "X-Webhook-Event-ID"
+	EventTypeHeader  = "X-Webhook-Event-Type"
+)
+
+var (
+	// ErrInsecureURL is returned for endpoint URLs that are not https URLs with a host.
+	ErrInsecureURL = errors.New("webhook endpoints must be https URLs")
+	// ErrForbiddenAddress is returned when an endpoint's host is, or resolves to, a loopback, private,
+	// link-local or otherwise special-purpose address, so endpoints can't be used to reach the bank's own network.
+	ErrForbiddenAddress = errors.New("webhook endpoint address is not public")
+)
+
+// ValidateURL returns ErrInsecureURL unless rawURL is an https URL with a host, and ErrForbiddenAddress
+// if the host is an IP address that is not public. Host names are checked when they are resolved,
+// on every delivery, since what they resolve to can change.
+func ValidateURL(rawURL string) error {
+	return validateURL(rawURL, checkAddress)
+}
+
+func validateURL(rawURL string, check func(ip net.IP) error) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrInsecureURL
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		return check(ip)
+	}
+	return nil
+}
+
+// forbiddenPrefixes are the special-purpose ranges of the IANA registries, which aren't public unicast
+// addresses: private and shared networks, loopback, link-local, documentation, benchmarking, multicast
+// and reserved ones, and the IPv6 ranges that embed an IPv4 address, which could be an internal one.
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // this network
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space (CGNAT), includes cloud metadata services
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local, includes cloud metadata services
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, includes broadcast
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments, includes Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("fec0::/10"),       // site-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// checkAddress returns ErrForbiddenAddress if ip is not a public unicast address.
+// IPv4-mapped IPv6 addresses are checked as the IPv4 address they map.
+func checkAddress(ip net.IP) error {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ErrForbiddenAddress
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(addr) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// dialControl returns the Control function of a dialer that checks the resolved address of every
+// connection right before it is made, so a host name can't be pointed at a forbidden address
+// after it was validated.
+func dialControl(check func(ip net.IP) error) func(network string, address string, c syscall.RawConn) error {
+	return func(network string, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return ErrForbiddenAddress
+		}
+		return check(ip)
+	}
+}
+
+// Sender is an interface for sending deliveries to webhook endpoints.
+type Sender interface {
+	// Send sends the delivery to the endpoint. It returns the status the endpoint answered,
+	// 0 if it didn't answer, and an error unless the delivery succeeded.
+	Send(ctx context.Context, endpoint db.WebhookEndpoints, delivery db.WebhookDeliveries) (int32, error)
+}
+
+// Client is a Sender that posts deliveries over HTTP.
+type Client struct {
+	http         *http.Client
+	checkAddress func(ip net.IP) error
+}
+
+// NewClient creates a new Client. It only connects to public addresses and doesn't follow redirects.
+func NewClient() Sender {
+	return newClient(checkAddress, nil)
+}
+
+// newClient creates a Client that checks every address it connects to with check,
+// and whose TLS connections use tlsConfig, or the default config if it is nil.
+func newClient(check func(ip net.IP) error, tlsConfig *tls.Config) *Client {
+	dialer := &net.Dialer{Timeout: Timeout, Control: dialControl(check)}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would connect to the endpoint on our behalf, out of reach of the dialer's check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	transport.TLSClientConfig = tlsConfig
+
+	return &Client{
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   Timeout,
+			// a redirect is the endpoint's answer, and fails the delivery like any other non-2xx status
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		checkAddress: check,
+	}
+}
+
+// Send posts the delivery's payload to the endpoint, signed with the endpoint's secret.
+// Any status other than 2xx is an error, including redirects, which are not followed.
+// It returns ErrInsecureURL or ErrForbiddenAddress without sending anything if the endpoint's URL fails ValidateURL.
+func (client *Client) Send(ctx context.Context, endpoint db.WebhookEndpoints, delivery db.WebhookDeliveries) (int32, error) {
+	if err := validateURL(endpoint.Url, client.checkAddress); err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(endpoint.Secret, time.Now(), delivery.Payload))
+	request.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(EventIDHeader, strconv.FormatInt(delivery.EventID, 10))
+	request.Header.Set(EventTypeHeader, delivery.EventType)
+
+	response, err := client.http.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+
+	code := int32(response.StatusCode)
+	if code < 200 || code > 299 {
+		return code, fmt.Errorf("endpoint answered %s", response.Status)
+	}
+	return code, nil
+}
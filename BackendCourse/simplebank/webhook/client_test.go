@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// anyAddress lets the tests' client connect to their servers, which listen on the loopback address.
+func anyAddress(net.IP) error {
+	return nil
+}
+
+func TestClientSend(t *testing.T) {
+	delivery := db.WebhookDeliveries{
+		ID:        3,
+		EventID:   7,
+		EventType: db.EventTypeTransferReceived,
+		Payload:   json.RawMessage(`{"id":7,"type":"transfer.received"}`),
+	}
+
+	testCases := []struct {
+		name   string
+		status int
+		check  func(t *testing.T, code int32, err error)
+	}{
+		{
+			name:   "OK",
+			status: http.StatusOK,
+			check: func(t *testing.T, code int32, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int32(http.StatusOK), code)
+			},
+		},
+		{
+			name:   "Rejected",
+			status: http.StatusGone,
+			check: func(t *testing.T, code int32, err error) {
+				require.EqualError(t, err, "endpoint answered 410 Gone")
+				require.Equal(t, int32(http.StatusGone), code)
+			},
+		},
+		{
+			name:   "Redirect",
+			status: http.StatusFound,
+			check: func(t *testing.T, code int32, err error) {
+				require.EqualError(t, err, "endpoint answered 302 Found")
+				require.Equal(t, int32(http.StatusFound), code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint := db.WebhookEndpoints{ID: 1, Secret: "whsec_test"}
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, string(delivery.Payload), string(body))
+
+				require.NoError(t, Verify(endpoint.Secret, r.Header.Get(SignatureHeader), body, time.Minute, time.Now()))
+				require.Equal(t, "3", r.Header.Get(DeliveryIDHeader))
+				require.Equal(t, "7", r.Header.Get(EventIDHeader))
+				require.Equal(t, delivery.EventType, r.Header.Get(EventTypeHeader))
+
+				if tc.status == http.StatusFound {
+					w.Header().Set("Location", "http://169.254.169.254/latest/meta-data/")
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+			endpoint.Url = server.URL
+
+			client := newClient(anyAddress, server.Client().Transport.(*http.Transport).TLSClientConfig)
+			code, err := client.Send(context.Background(), endpoint, delivery)
+			tc.check(t, code, err)
+		})
+	}
+
+	// an endpoint that doesn't answer has no status
+	code, err := newClient(anyAddress, nil).Send(context.Background(), db.WebhookEndpoints{Url: "https://127.0.0.1:1"}, delivery)
+	require.Error(t, err)
+	require.Zero(t, code)
+}
+
+func TestClientSendForbiddenAddress(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the delivery reached the server")
+	}))
+	defer server.Close()
+
+	client := newClient(checkAddress, server.Client().Transport.(*http.Transport).TLSClientConfig)
+	delivery := db.WebhookDeliveries{ID: 3, Payload: json.RawMessage(`{}`)}
+
+	// the host name resolves to the loopback address, which is only caught when connecting
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	code, err := client.Send(context.Background(), db.WebhookEndpoints{Url: "https://localhost:" + port}, delivery)
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+	require.Zero(t, code)
+
+	code, err = client.Send(context.Background(), db.WebhookEndpoints{Url: server.URL}, delivery)
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+	require.Zero(t, code)
+
+	code, err = client.Send(context.Background(), db.WebhookEndpoints{Url: "http://example.com/hook"}, delivery)
+	require.ErrorIs(t, err, ErrInsecureURL)
+	require.Zero(t, code)
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"https://example.com/hooks",
+		"https://93.184.216.34:8443/hooks",
+		"https://100.128.0.1/hooks",
+		"https://[2606:2800:220:1:248:1893:25c8:1946]/hooks",
+	} {
+		require.NoError(t, ValidateURL(rawURL), rawURL)
+	}
+
+	for _, rawURL := range []string{"http://example.com/hooks", "ftp://example.com", "https://", "https:///hooks", "example.com"} {
+		require.ErrorIs(t, ValidateURL(rawURL), ErrInsecureURL, rawURL)
+	}
+
+	for _, rawURL := range []string{
+		"https://127.0.0.1/hooks",
+		"https://[::1]/hooks",
+		"https://10.0.0.5/hooks",
+		"https://192.168.1.1/hooks",
+		"https://169.254.169.254/latest/meta-data/",
+		"https://[fe80::1]/hooks",
+		"https://0.0.0.0/hooks",
+		"https://0.1.2.3/hooks",
+		"https://[::ffff:127.0.0.1]/hooks",
+		"https://100.64.0.1/hooks",
+		"https://100.100.100.200/latest/meta-data/",
+		"https://192.0.0.8/hooks",
+		"https://198.18.0.1/hooks",
+		"https://198.19.255.255/hooks",
+		"https://240.0.0.1/hooks",
+		"https://255.255.255.255/hooks",
+		"https://224.0.0.1/hooks",
+		"https://[64:ff9b::a9fe:a9fe]/hooks",
+		"https://[2002:a9fe:a9fe::1]/hooks",
+		"https://[2001:db8::1]/hooks",
+		"https://[fd00::1]/hooks",
+		"https://[ff02::1]/hooks",
+		"https://[::]/hooks",
+	} {
+		require.ErrorIs(t, ValidateURL(rawURL), ErrForbiddenAddress, rawURL)
+	}
+}
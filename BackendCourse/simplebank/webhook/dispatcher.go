@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+)
+
+// Dispatcher is a Sink that turns events into deliveries to the webhook endpoints subscribed to them.
+// An event is delivered to the endpoints of the owner of the account it happened to, or of the user
+// it is about. The deliveries are sent later by the webhook delivery job.
+type Dispatcher struct {
+	store db.Store
+}
+
+// NewDispatcher creates a new Dispatcher
+func NewDispatcher(store db.Store) events.Sink {
+	return &Dispatcher{store: store}
+}
+
+// Publish creates a delivery of the event for every subscribed endpoint. Publishing the same event
+// again doesn't deliver it twice.
+func (dispatcher *Dispatcher) Publish(ctx context.Context, event events.Event) error {
+	owner, err := dispatcher.owner(ctx, event)
+	if err != nil {
+		return err
+	}
+	if owner == "" {
+		return nil
+	}
+
+	endpoints, err := dispatcher.store.ListSubscribedWebhookEndpoints(ctx, db.ListSubscribedWebhookEndpointsParams{
+		Owner:     owner,
+		EventType: event.Type,
+	})
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		err := dispatcher.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+			EventType:  event.Type,
+			Payload:    payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// owner returns the username of the user the event concerns, or "" if it doesn't concern any.
+func (dispatcher *Dispatcher) owner(ctx context.Context, event events.Event) (string, error) {
+	switch event.AggregateType {
+	case db.OutboxAggregateUser:
+		return event.AggregateID, nil
+	case db.OutboxAggregateAccount:
+		accountID, err := strconv.ParseInt(event.AggregateID, 10, 64)
+		if err != nil {
+			return "", err
+		}
+
+		account, err := dispatcher.store.GetAccount(ctx, accountID)
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return account.Owner, err
+	default:
+		return "", nil
+	}
+}
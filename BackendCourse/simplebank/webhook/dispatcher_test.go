@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcherPublish(t *testing.T) {
+	event := events.Event{
+		ID:            7,
+		Type:          db.EventTypeTransferReceived,
+		AggregateType: db.OutboxAggregateAccount,
+		AggregateID:   "12",
+		Payload:       json.RawMessage(`{}`),
+		CreatedAt:     time.Now().UTC(),
+	}
+	account := db.Accounts{ID: 12, Owner: "merchant"}
+	endpoints := []db.WebhookEndpoints{{ID: 1, Owner: "merchant"}, {ID: 2, Owner: "merchant"}}
+
+	testCases := []struct {
+		name       string
+		event      events.Event
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name:  "AccountEvent",
+			event: event,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(12))).Times(1).Return(account, nil)
+				arg := db.ListSubscribedWebhookEndpointsParams{Owner: "merchant", EventType: event.Type}
+				store.EXPECT().ListSubscribedWebhookEndpoints(gomock.Any(), gomock.Eq(arg)).Times(1).Return(endpoints, nil)
+
+				payload, err := json.Marshal(event)
+				require.NoError(t, err)
+				for _, endpoint := range endpoints {
+					delivery := db.CreateWebhookDeliveryParams{
+						EndpointID: endpoint.ID,
+						EventID:    event.ID,
+						EventType:  event.Type,
+						Payload:    payload,
+					}
+					store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Eq(delivery)).Times(1).Return(nil)
+				}
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "UserEvent",
+			event: events.Event{
+				ID:            8,
+				Type:          db.EventTypeUserCreated,
+				AggregateType: db.OutboxAggregateUser,
+				AggregateID:   "merchant",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				arg := db.ListSubscribedWebhookEndpointsParams{Owner: "merchant", EventType: db.EventTypeUserCreated}
+				store.EXPECT().ListSubscribedWebhookEndpoints(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.WebhookEndpoints{}, nil)
+				store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "AccountNotFound",
+			event: event,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(12))).Times(1).Return(db.Accounts{}, sql.ErrNoRows)
+				store.EXPECT().ListSubscribedWebhookEndpoints(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "DBError",
+			event: event,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(12))).Times(1).Return(account, nil)
+				store.EXPECT().ListSubscribedWebhookEndpoints(gomock.Any(), gomock.Any()).Times(1).Return(endpoints, nil)
+				store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			err := NewDispatcher(store).Publish(context.Background(), tc.event)
+			tc.check(t, err)
+		})
+	}
+}
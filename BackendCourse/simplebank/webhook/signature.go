@@ -0,0 +1,85 @@
+// Package webhook delivers the bank's events to the webhook endpoints users register,
+// signing every delivery with the endpoint's secret.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader holds the time a delivery was sent and the signature of that time and the body,
+// e.g. "t=1767225600,v1=5257a869...". The receiver checks both, so an old delivery can't be replayed to it.
+const SignatureHeader = "X-Webhook-Signature"
+
+// secretPrefix marks a string as a webhook secret, e.g. for secret scanners.
+const secretPrefix = "whsec_"
+
+var (
+	// ErrInvalidSignature is returned when a signature header is malformed or doesn't match the body.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrSignatureExpired is returned when a signature matches but was made longer ago than the tolerance.
+	ErrSignatureExpired = errors.New("webhook signature is too old")
+)
+
+// NewSecret returns a new random secret to sign an endpoint's deliveries with.
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(key), nil
+}
+
+// Sign returns the signature header of a body sent at the given time:
+// the unix time and the hex HMAC-SHA256, keyed with the secret, of the unix time, a dot and the body.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + signature(secret, unix, body)
+}
+
+// Verify checks a signature header made by Sign. It is what a receiver does with a delivery,
+// and is here for the receivers written in Go and for tests.
+// The signature must not be older than the tolerance.
+func Verify(secret string, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var unix, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return ErrInvalidSignature
+		}
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			sig = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, unix, body))) {
+		return ErrInvalidSignature
+	}
+
+	if now.Sub(time.Unix(seconds, 0)) > tolerance {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func signature(secret string, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
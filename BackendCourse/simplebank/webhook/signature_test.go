@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, secretPrefix))
+	require.Len(t, secret, len(secretPrefix)+64)
+
+	body := []byte(`{"id":1,"type":"transfer.received"}`)
+	sentAt := time.Unix(1767225600, 0)
+	header := Sign(secret, sentAt, body)
+	require.True(t, strings.HasPrefix(header, "t=1767225600,v1="))
+
+	require.NoError(t, Verify(secret, header, body, 5*time.Minute, sentAt.Add(time.Minute)))
+
+	// a changed body, another secret or a malformed header don't verify
+	require.ErrorIs(t, Verify(secret, header, []byte(`{"id":2}`), 5*time.Minute, sentAt), ErrInvalidSignature)
+	require.ErrorIs(t, Verify("whsec_other", header, body, 5*time.Minute, sentAt), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, "v1=abc", body, 5*time.Minute, sentAt), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, "garbage", body, 5*time.Minute, sentAt), ErrInvalidSignature)
+
+	// nor does one that is too old, even if it matches
+	require.ErrorIs(t, Verify(secret, header, body, 5*time.Minute, sentAt.Add(time.Hour)), ErrSignatureExpired)
+}
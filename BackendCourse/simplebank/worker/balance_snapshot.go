@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// BalanceSnapshotJob returns a job that snapshots the balance every account had at the end of yesterday, UTC,
+// so that historical balances only need to sum the entries made since.
+// Snapshots are keyed by account and date, so running it several times a day snapshots once.
+func BalanceSnapshotJob(store db.Store) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		count, err := store.CreateBalanceSnapshots(ctx, db.CreateBalanceSnapshotsParams{
+			SnapshotDate: today.AddDate(0, 0, -1),
+			DayEnd:       today,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot snapshot balances: %w", err)
+		}
+		if count > 0 {
+			log.Printf("snapshotted the balances of %d accounts", count)
+		}
+		return nil
+	}
+}
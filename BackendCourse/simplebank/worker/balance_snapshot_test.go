@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceSnapshotJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	arg := db.CreateBalanceSnapshotsParams{
+		SnapshotDate: today.AddDate(0, 0, -1),
+		DayEnd:       today,
+	}
+
+	store.EXPECT().
+		CreateBalanceSnapshots(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(int64(3), nil)
+
+	err := BalanceSnapshotJob(store)(context.Background())
+	require.NoError(t, err)
+}
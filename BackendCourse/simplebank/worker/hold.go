@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// holdExpiryBatchSize is how many expired holds are released per run
+const holdExpiryBatchSize = 100
+
+// HoldExpiryJob returns a job that releases pending holds whose expiry has passed.
+// A hold captured or voided between listing and releasing it is skipped.
+func HoldExpiryJob(store db.Store) JobFunc {
+	return func(ctx context.Context) error {
+		transfers, err := store.ListExpiredPendingTransfers(ctx, db.ListExpiredPendingTransfersParams{
+			Now:     time.Now(),
+			MaxRows: holdExpiryBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list expired holds: %w", err)
+		}
+
+		failed := 0
+		for _, transfer := range transfers {
+			_, err := store.ReleaseTransferTx(ctx, db.ReleaseTransferTxParams{
+				TransferID: transfer.ID,
+				Status:     db.TransferStatusExpired,
+			})
+			if errors.Is(err, db.ErrTransferNotPending) {
+				continue
+			}
+			if err != nil {
+				log.Printf("cannot expire hold %d: %v", transfer.ID, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to expire %d of %d holds", failed, len(transfers))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldExpiryJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	transfers := []db.Transfers{
+		{ID: 1, Status: db.TransferStatusPending},
+		{ID: 2, Status: db.TransferStatusPending},
+	}
+
+	store.EXPECT().
+		ListExpiredPendingTransfers(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(transfers, nil)
+
+	// the first hold was captured in the meantime, which is not a failure
+	store.EXPECT().
+		ReleaseTransferTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.ReleaseTransferTxParams) (db.ReleaseTransferTxResult, error) {
+			require.Equal(t, db.TransferStatusExpired, arg.Status)
+			if arg.TransferID == 1 {
+				return db.ReleaseTransferTxResult{}, db.ErrTransferNotPending
+			}
+			return db.ReleaseTransferTxResult{}, nil
+		})
+
+	err := HoldExpiryJob(store)(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// InterestAccrualJob returns a job that accrues one day of interest on the end-of-day balance of yesterday
+// for every account whose product has an interest rate.
+// Accruals are keyed by the UTC date, so running it several times a day accrues once.
+func InterestAccrualJob(store db.Store) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		accrualDate := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+
+		accounts, err := store.ListInterestBearingAccounts(ctx, accrualDate)
+		if err != nil {
+			return fmt.Errorf("cannot list interest bearing accounts: %w", err)
+		}
+
+		// one failing account shouldn't stop the others from accruing
+		failed := 0
+		for _, account := range accounts {
+			_, err := store.AccrueInterestTx(ctx, db.AccrueInterestTxParams{
+				AccountID:   account.ID,
+				AccrualDate: accrualDate,
+			})
+			if err != nil {
+				log.Printf("cannot accrue interest for account %d: %v", account.ID, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("interest accrual failed for %d of %d accounts", failed, len(accounts))
+		}
+		return nil
+	}
+}
+
+// InterestPostingJob returns a job that credits the interest accrued during the previous month.
+// It waits until the second day of the month, so the accrual for the last day of the previous month is in.
+// Postings are keyed by the month, so running it several times posts once.
+func InterestPostingJob(store db.Store) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+		period := time.Date(yesterday.Year(), yesterday.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+
+		accountIDs, err := store.ListUnpostedInterestAccounts(ctx, db.ListUnpostedInterestAccountsParams{
+			FromDate: period,
+			ToDate:   period.AddDate(0, 1, 0),
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list accounts with unposted interest: %w", err)
+		}
+
+		// one failing account shouldn't stop the others from being credited
+		failed := 0
+		for _, accountID := range accountIDs {
+			result, err := store.PostInterestTx(ctx, db.PostInterestTxParams{
+				AccountID: accountID,
+				Period:    period,
+			})
+			if err != nil {
+				log.Printf("cannot post interest to account %d: %v", accountID, err)
+				failed++
+				continue
+			}
+			if result.Posted {
+				log.Printf("posted interest %d to account %d", result.Posting.Amount, accountID)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("interest posting failed for %d of %d accounts", failed, len(accountIDs))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterestAccrualJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	accounts := []db.Accounts{{ID: 1}, {ID: 2}}
+
+	now := time.Now().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+
+	store.EXPECT().
+		ListInterestBearingAccounts(gomock.Any(), gomock.Eq(yesterday)).
+		Times(1).
+		Return(accounts, nil)
+
+	// the first account fails, the second one must still accrue
+	store.EXPECT().
+		AccrueInterestTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.AccrueInterestTxParams) (db.AccrueInterestTxResult, error) {
+			require.Equal(t, yesterday, arg.AccrualDate)
+			if arg.AccountID == 1 {
+				return db.AccrueInterestTxResult{}, sql.ErrConnDone
+			}
+			return db.AccrueInterestTxResult{Accrued: true}, nil
+		})
+
+	err := InterestAccrualJob(store)(context.Background())
+	require.Error(t, err)
+}
+
+func TestInterestPostingJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.ListUnpostedInterestAccountsParams) ([]int64, error) {
+			// a whole month, which is over by at least a day
+			require.Equal(t, 1, arg.FromDate.Day())
+			require.Equal(t, arg.FromDate.AddDate(0, 1, 0), arg.ToDate)
+			require.False(t, arg.ToDate.After(time.Now().AddDate(0, 0, -1)))
+			return []int64{1, 2}, nil
+		})
+
+	store.EXPECT().
+		PostInterestTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.PostInterestTxParams) (db.PostInterestTxResult, error) {
+			require.Equal(t, 1, arg.Period.Day())
+			return db.PostInterestTxResult{Posted: true}, nil
+		})
+
+	err := InterestPostingJob(store)(context.Background())
+	require.NoError(t, err)
+}
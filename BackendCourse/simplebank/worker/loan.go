@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// loanRepaymentBatchSize is how many due installments are listed at a time
+const loanRepaymentBatchSize = 100
+
+// LoanRepaymentJob returns a job that collects the loan installments due by today from the borrowers' accounts.
+// An installment the account can't pay stays due and is tried again on the next run. Once it is still unpaid
+// gracePeriod after its due date, the late fee is added to it, once; a zero late fee disables them.
+// Every run pages through all the due installments, so the ones that can't be paid don't hold up the others.
+func LoanRepaymentJob(store db.Store, lateFee int64, gracePeriod time.Duration) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		// one failing installment shouldn't stop the others from being collected
+		failed, total := 0, 0
+		var afterID int64
+		for {
+			installments, err := store.ListDueLoanInstallments(ctx, db.ListDueLoanInstallmentsParams{
+				OnDate:  today,
+				AfterID: afterID,
+				MaxRows: loanRepaymentBatchSize,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot list due loan installments: %w", err)
+			}
+
+			for _, installment := range installments {
+				afterID = installment.ID
+				total++
+
+				result, err := store.CollectLoanInstallmentTx(ctx, installment.ID)
+				switch {
+				case err == nil:
+					log.Printf("collected installment %d of loan %d", installment.Number, installment.LoanID)
+					if result.Loan.Status == db.LoanRepaid {
+						log.Printf("loan %d is repaid", installment.LoanID)
+					}
+				case errors.Is(err, db.ErrInsufficientFunds):
+					log.Printf("cannot collect installment %d of loan %d yet: %v", installment.Number, installment.LoanID, err)
+				case errors.Is(err, db.ErrLoanInstallmentNotDue):
+					// collected in the meantime
+				default:
+					log.Printf("cannot collect installment %d of loan %d: %v", installment.Number, installment.LoanID, err)
+					failed++
+				}
+			}
+
+			if len(installments) < loanRepaymentBatchSize {
+				break
+			}
+		}
+
+		if lateFee > 0 {
+			charged, err := store.ChargeLoanLateFeesTx(ctx, db.ChargeLoanLateFeesParams{
+				LateFee: lateFee,
+				Cutoff:  today.Add(-gracePeriod),
+			})
+			if err != nil {
+				return fmt.Errorf("cannot charge loan late fees: %w", err)
+			}
+			for _, installment := range charged {
+				log.Printf("charged a late fee on installment %d of loan %d", installment.Number, installment.LoanID)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("loan repayment failed for %d of %d installments", failed, total)
+		}
+		return nil
+	}
+}
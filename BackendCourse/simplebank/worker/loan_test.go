@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoanRepaymentJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	installments := []db.LoanInstallments{
+		{ID: 1, LoanID: 1, Number: 3},
+		{ID: 2, LoanID: 2, Number: 1},
+		{ID: 3, LoanID: 3, Number: 5},
+	}
+
+	store.EXPECT().
+		ListDueLoanInstallments(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(installments, nil)
+
+	// an account that can't pay isn't a failure of the job, it is tried again later
+	store.EXPECT().
+		CollectLoanInstallmentTx(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(ctx context.Context, installmentID int64) (db.CollectLoanInstallmentTxResult, error) {
+			switch installmentID {
+			case 1:
+				return db.CollectLoanInstallmentTxResult{}, db.ErrInsufficientFunds
+			case 2:
+				return db.CollectLoanInstallmentTxResult{}, sql.ErrConnDone
+			}
+			return db.CollectLoanInstallmentTxResult{Loan: db.Loans{Status: db.LoanRepaid}}, nil
+		})
+
+	now := time.Now().UTC()
+	store.EXPECT().
+		ChargeLoanLateFeesTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.ChargeLoanLateFeesParams) ([]db.LoanInstallments, error) {
+			require.Equal(t, int64(2500), arg.LateFee)
+			require.Equal(t, time.Date(now.Year(), now.Month(), now.Day()-3, 0, 0, 0, 0, time.UTC), arg.Cutoff)
+			return installments[:1], nil
+		})
+
+	err := LoanRepaymentJob(store, 2500, 72*time.Hour)(context.Background())
+	require.Error(t, err)
+}
+
+func TestLoanRepaymentJobPagesPastUnpaidInstallments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	// a full page of installments that can't be paid doesn't hold up the ones after them
+	unpaid := make([]db.LoanInstallments, loanRepaymentBatchSize)
+	for i := range unpaid {
+		unpaid[i] = db.LoanInstallments{ID: int64(i + 1), LoanID: int64(i + 1), Number: 1}
+	}
+	next := db.LoanInstallments{ID: loanRepaymentBatchSize + 1, LoanID: loanRepaymentBatchSize + 1, Number: 1}
+
+	gomock.InOrder(
+		store.EXPECT().
+			ListDueLoanInstallments(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.ListDueLoanInstallmentsParams) ([]db.LoanInstallments, error) {
+				require.Zero(t, arg.AfterID)
+				return unpaid, nil
+			}),
+		store.EXPECT().
+			ListDueLoanInstallments(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.ListDueLoanInstallmentsParams) ([]db.LoanInstallments, error) {
+				require.Equal(t, int64(loanRepaymentBatchSize), arg.AfterID)
+				return []db.LoanInstallments{next}, nil
+			}),
+	)
+
+	store.EXPECT().
+		CollectLoanInstallmentTx(gomock.Any(), gomock.Any()).
+		Times(loanRepaymentBatchSize).
+		Return(db.CollectLoanInstallmentTxResult{}, db.ErrInsufficientFunds)
+	store.EXPECT().
+		CollectLoanInstallmentTx(gomock.Any(), gomock.Eq(next.ID)).
+		Times(1).
+		Return(db.CollectLoanInstallmentTxResult{Loan: db.Loans{Status: db.LoanActive}}, nil)
+
+	err := LoanRepaymentJob(store, 0, 72*time.Hour)(context.Background())
+	require.NoError(t, err)
+}
+
+func TestLoanRepaymentJobWithoutLateFees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1)
+	store.EXPECT().ChargeLoanLateFeesTx(gomock.Any(), gomock.Any()).Times(0)
+
+	err := LoanRepaymentJob(store, 0, 72*time.Hour)(context.Background())
+	require.NoError(t, err)
+}
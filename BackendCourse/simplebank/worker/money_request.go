@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
+)
+
+// moneyRequestExpiryBatchSize is how many money requests are expired per run
+const moneyRequestExpiryBatchSize = 100
+
+// MoneyRequestExpiryJob returns a job that expires pending money requests whose expiry has passed,
+// and tells both the requester and the payer.
+func MoneyRequestExpiryJob(store db.Store, notifier notify.Notifier) JobFunc {
+	return func(ctx context.Context) error {
+		requests, err := store.ExpireMoneyRequests(ctx, db.ExpireMoneyRequestsParams{
+			Now:     time.Now(),
+			MaxRows: moneyRequestExpiryBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot expire money requests: %w", err)
+		}
+
+		for _, request := range requests {
+			message := fmt.Sprintf("The request of %d %s from %s to %s expired",
+				request.Amount, request.Currency, request.Requester, request.Payer)
+			for _, username := range []string{request.Requester, request.Payer} {
+				err := notifier.Notify(ctx, notify.Notification{
+					Username: username,
+					Kind:     notify.KindMoneyRequestExpired,
+					Message:  message,
+					Data:     request,
+				})
+				if err != nil {
+					log.Printf("cannot notify %s of money request %d expiry: %v", username, request.ID, err)
+				}
+			}
+		}
+
+		return nil
+	}
+}
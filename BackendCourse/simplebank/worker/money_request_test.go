@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/notify"
+	mocknotify "github.com/ofer-sin/Courses/BackendCourse/simplebank/notify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyRequestExpiryJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	notifier := mocknotify.NewMockNotifier(ctrl)
+	requests := []db.MoneyRequests{
+		{ID: 1, Requester: "alice", Payer: "bob", Status: db.MoneyRequestExpired},
+		{ID: 2, Requester: "carol", Payer: "dave", Status: db.MoneyRequestExpired},
+	}
+
+	store.EXPECT().
+		ExpireMoneyRequests(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(requests, nil)
+
+	// both parties are told, and a notification that can't be delivered doesn't stop the others
+	var notified []string
+	notifier.EXPECT().
+		Notify(gomock.Any(), gomock.Any()).
+		Times(4).
+		DoAndReturn(func(ctx context.Context, notification notify.Notification) error {
+			require.Equal(t, notify.KindMoneyRequestExpired, notification.Kind)
+			notified = append(notified, notification.Username)
+			if notification.Username == "alice" {
+				return errors.New("unreachable")
+			}
+			return nil
+		})
+
+	err := MoneyRequestExpiryJob(store, notifier)(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"alice", "bob", "carol", "dave"}, notified)
+}
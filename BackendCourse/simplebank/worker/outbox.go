@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+)
+
+const (
+	// outboxRelayBatchSize is how many outbox events are claimed at a time
+	outboxRelayBatchSize = 20
+	// outboxRelayMaxRounds is how many batches are published per run of the job
+	outboxRelayMaxRounds = 10
+	// outboxClaimDuration is how long a relay has to publish the events it claimed before another
+	// relay may claim them again, long enough for a whole batch to wait for a slow webhook
+	outboxClaimDuration = 5 * time.Minute
+	// the delay before the first retry of an event; it doubles with every failed attempt up to the max
+	outboxRetryBaseDelay = 5 * time.Second
+	outboxRetryMaxDelay  = time.Hour
+)
+
+// OutboxRelayJob returns a job that publishes the events written to the outbox to the sink.
+// Only the oldest unpublished event of each aggregate is claimed at a time, so the events of
+// an account are published in order, and a failed event holds back the ones after it until
+// it is published on a later attempt. An event is marked as published only after the sink
+// accepted it, so it is delivered at least once, and more than once if the relay stops in between.
+func OutboxRelayJob(store db.Store, sink events.Sink) JobFunc {
+	return func(ctx context.Context) error {
+		failed := 0
+		for round := 0; round < outboxRelayMaxRounds; round++ {
+			now := time.Now()
+			claimed, err := store.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+				ClaimedUntil: now.Add(outboxClaimDuration),
+				Now:          now,
+				MaxRows:      outboxRelayBatchSize,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot claim outbox events: %w", err)
+			}
+			sort.Slice(claimed, func(i, j int) bool { return claimed[i].ID < claimed[j].ID })
+
+			published := 0
+			for _, event := range claimed {
+				publishErr := sink.Publish(ctx, outboxEvent(event))
+				if publishErr != nil {
+					log.Printf("cannot publish outbox event %d: %v", event.ID, publishErr)
+					failed++
+
+					_, err = store.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+						ID:            event.ID,
+						LastError:     publishErr.Error(),
+						NextAttemptAt: time.Now().Add(retryDelay(outboxRetryBaseDelay, outboxRetryMaxDelay, event.Attempts+1)),
+					})
+					if err != nil {
+						return fmt.Errorf("cannot record the failure of outbox event %d: %w", event.ID, err)
+					}
+					continue
+				}
+
+				_, err = store.MarkOutboxEventPublished(ctx, event.ID)
+				if err != nil {
+					return fmt.Errorf("cannot mark outbox event %d as published: %w", event.ID, err)
+				}
+				published++
+			}
+
+			// the next events of an aggregate can only be claimed once the one before them is published
+			if published == 0 {
+				break
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d outbox events failed to publish", failed)
+		}
+		return nil
+	}
+}
+
+// retryDelay returns how long to wait before trying again after the nth failed attempt:
+// the base delay, doubled for every attempt after the first, up to the max.
+func retryDelay(base time.Duration, max time.Duration, attempts int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+func outboxEvent(event db.OutboxEvents) events.Event {
+	return events.Event{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       event.Payload,
+		CreatedAt:     event.CreatedAt,
+	}
+}
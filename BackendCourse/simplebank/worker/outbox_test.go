@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/events"
+	mockevents "github.com/ofer-sin/Courses/BackendCourse/simplebank/events/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRelayJob(t *testing.T) {
+	// claimed out of order, as UPDATE ... RETURNING doesn't keep it
+	claimed := []db.OutboxEvents{
+		{ID: 2, EventType: db.EventTypeTransferReceived, AggregateType: db.OutboxAggregateAccount, AggregateID: "2", Payload: json.RawMessage(`{}`)},
+		{ID: 1, EventType: db.EventTypeTransferSent, AggregateType: db.OutboxAggregateAccount, AggregateID: "1", Payload: json.RawMessage(`{}`), Attempts: 2},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore, sink *mockevents.MockSink)
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name: "Published",
+			buildStubs: func(store *mockdb.MockStore, sink *mockevents.MockSink) {
+				gomock.InOrder(
+					store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(claimed, nil),
+					sink.EXPECT().Publish(gomock.Any(), gomock.Eq(outboxEvent(claimed[1]))).Times(1).Return(nil),
+					store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(1))).Times(1),
+					sink.EXPECT().Publish(gomock.Any(), gomock.Eq(outboxEvent(claimed[0]))).Times(1).Return(nil),
+					store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(2))).Times(1),
+					store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return([]db.OutboxEvents{}, nil),
+				)
+				store.EXPECT().MarkOutboxEventFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "PublishFailed",
+			buildStubs: func(store *mockdb.MockStore, sink *mockevents.MockSink) {
+				store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(claimed, nil)
+				sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(2).
+					DoAndReturn(func(ctx context.Context, event events.Event) error {
+						if event.ID == 1 {
+							return errors.New("unreachable")
+						}
+						return nil
+					})
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(2))).Times(1)
+				store.EXPECT().MarkOutboxEventFailed(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.MarkOutboxEventFailedParams) (db.OutboxEvents, error) {
+						require.Equal(t, int64(1), arg.ID)
+						require.Equal(t, "unreachable", arg.LastError)
+						// the third attempt failed
+						require.WithinDuration(t, time.Now().Add(4*outboxRetryBaseDelay), arg.NextAttemptAt, time.Second)
+						return db.OutboxEvents{}, nil
+					})
+				// event 2 was published, so the next one of its account is looked for
+				store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return([]db.OutboxEvents{}, nil)
+			},
+			check: func(t *testing.T, err error) {
+				require.EqualError(t, err, "1 outbox events failed to publish")
+			},
+		},
+		{
+			name: "ClaimError",
+			buildStubs: func(store *mockdb.MockStore, sink *mockevents.MockSink) {
+				store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			sink := mockevents.NewMockSink(ctrl)
+			tc.buildStubs(store, sink)
+
+			err := OutboxRelayJob(store, sink)(context.Background())
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, time.Second, retryDelay(time.Second, time.Minute, 1))
+	require.Equal(t, 2*time.Second, retryDelay(time.Second, time.Minute, 2))
+	require.Equal(t, 8*time.Second, retryDelay(time.Second, time.Minute, 4))
+	require.Equal(t, time.Minute, retryDelay(time.Second, time.Minute, 30))
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// OverdraftInterestJob returns a job that charges one day of overdraft interest,
+// at the given annual rate in basis points, to every account with a negative balance.
+// Charges are keyed by the UTC date, so running it several times a day charges once.
+func OverdraftInterestJob(store db.Store, annualRateBps int64) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		chargeDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		accounts, err := store.ListOverdrawnAccounts(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list overdrawn accounts: %w", err)
+		}
+
+		// one failing account shouldn't stop the others from being charged
+		failed := 0
+		for _, account := range accounts {
+			result, err := store.ChargeOverdraftInterestTx(ctx, db.ChargeOverdraftInterestTxParams{
+				AccountID:     account.ID,
+				ChargeDate:    chargeDate,
+				AnnualRateBps: annualRateBps,
+			})
+			if err != nil {
+				log.Printf("cannot charge overdraft interest to account %d: %v", account.ID, err)
+				failed++
+				continue
+			}
+			if result.Charged {
+				log.Printf("charged overdraft interest %d to account %d", result.Charge.Amount, account.ID)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("overdraft interest failed for %d of %d accounts", failed, len(accounts))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverdraftInterestJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	accounts := []db.Accounts{
+		{ID: 1, Balance: -1000},
+		{ID: 2, Balance: -2000},
+	}
+
+	store.EXPECT().
+		ListOverdrawnAccounts(gomock.Any()).
+		Times(1).
+		Return(accounts, nil)
+
+	// the first account fails, the second one must still be charged
+	store.EXPECT().
+		ChargeOverdraftInterestTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.ChargeOverdraftInterestTxParams) (db.ChargeOverdraftInterestTxResult, error) {
+			require.Equal(t, int64(1500), arg.AnnualRateBps)
+			require.Zero(t, arg.ChargeDate.Hour())
+			if arg.AccountID == 1 {
+				return db.ChargeOverdraftInterestTxResult{}, sql.ErrConnDone
+			}
+			return db.ChargeOverdraftInterestTxResult{Charged: true}, nil
+		})
+
+	err := OverdraftInterestJob(store, 1500)(context.Background())
+	require.Error(t, err)
+}
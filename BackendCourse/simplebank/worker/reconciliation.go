@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// ReconciliationReport lists everything in the ledger that doesn't add up.
+// Both lists are empty, never null, when the ledger is consistent.
+type ReconciliationReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	// accounts whose balance isn't the sum of their entries
+	BalanceDrifts []db.ListBalanceDriftsRow `json:"balance_drifts"`
+	// transfers whose entries don't move exactly the captured amount from the from account to the to account
+	TransferMismatches []db.ListTransferEntryMismatchesRow `json:"transfer_mismatches"`
+}
+
+// Consistent reports whether the reconciliation found no drift.
+func (report ReconciliationReport) Consistent() bool {
+	return len(report.BalanceDrifts) == 0 && len(report.TransferMismatches) == 0
+}
+
+// Reconcile checks the ledger: every account's balance must equal the sum of its entries,
+// and every transfer must have the two entries that net to zero matching what it moved.
+// Each check is a single statement, so it sees a consistent snapshot even while transfers run.
+func Reconcile(ctx context.Context, store db.Store) (ReconciliationReport, error) {
+	report := ReconciliationReport{CheckedAt: time.Now().UTC()}
+
+	var err error
+	report.BalanceDrifts, err = store.ListBalanceDrifts(ctx)
+	if err != nil {
+		return report, fmt.Errorf("cannot list balance drifts: %w", err)
+	}
+
+	report.TransferMismatches, err = store.ListTransferEntryMismatches(ctx)
+	if err != nil {
+		return report, fmt.Errorf("cannot list transfer entry mismatches: %w", err)
+	}
+
+	return report, nil
+}
+
+// ReconciliationJob returns a job that reconciles the ledger and, when anything drifted,
+// logs the report as JSON and fails, so the drift shows up as a failed job.
+func ReconciliationJob(store db.Store) JobFunc {
+	return func(ctx context.Context) error {
+		report, err := Reconcile(ctx, store)
+		if err != nil {
+			return err
+		}
+		if report.Consistent() {
+			return nil
+		}
+
+		data, err := json.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("cannot encode reconciliation report: %w", err)
+		}
+		log.Printf("reconciliation report: %s", data)
+
+		return fmt.Errorf("ledger drift: %d accounts and %d transfers don't reconcile",
+			len(report.BalanceDrifts), len(report.TransferMismatches))
+	}
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationJob(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name: "Consistent",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBalanceDrifts(gomock.Any()).Times(1).Return([]db.ListBalanceDriftsRow{}, nil)
+				store.EXPECT().ListTransferEntryMismatches(gomock.Any()).Times(1).Return([]db.ListTransferEntryMismatchesRow{}, nil)
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Drift",
+			buildStubs: func(store *mockdb.MockStore) {
+				drifts := []db.ListBalanceDriftsRow{{ID: 1, Balance: 100, EntriesBalance: 90}}
+				store.EXPECT().ListBalanceDrifts(gomock.Any()).Times(1).Return(drifts, nil)
+				store.EXPECT().ListTransferEntryMismatches(gomock.Any()).Times(1).Return([]db.ListTransferEntryMismatchesRow{}, nil)
+			},
+			check: func(t *testing.T, err error) {
+				require.EqualError(t, err, "ledger drift: 1 accounts and 0 transfers don't reconcile")
+			},
+		},
+		{
+			name: "DBError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBalanceDrifts(gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				store.EXPECT().ListTransferEntryMismatches(gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			err := ReconciliationJob(store)(context.Background())
+			tc.check(t, err)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package worker runs the bank's periodic background jobs inside the server process.
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// JobFunc is the work done by a job on every run.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler runs registered jobs in the background, each on its own fixed interval.
+// Jobs must be safe to run more than once for the same period, since the server
+// may be restarted or run as several replicas.
+type Scheduler struct {
+	jobs []job
+}
+
+// NewScheduler creates an empty Scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job that runs once at startup and then every interval.
+// A non-positive interval disables the job.
+func (scheduler *Scheduler) Every(name string, interval time.Duration, run JobFunc) {
+	if interval <= 0 {
+		log.Printf("job %s is disabled", name)
+		return
+	}
+	scheduler.jobs = append(scheduler.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start runs every registered job in its own goroutine until ctx is cancelled.
+func (scheduler *Scheduler) Start(ctx context.Context) {
+	for _, j := range scheduler.jobs {
+		go j.loop(ctx)
+	}
+}
+
+func (j job) loop(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.run(ctx); err != nil {
+			log.Printf("job %s failed: %v", j.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+)
+
+// standingOrderBatchSize is how many due standing orders are run per run of the job
+const standingOrderBatchSize = 100
+
+// StandingOrderJob returns a job that runs due standing orders through TransferTx.
+// Each order is claimed in its own transaction before its transfer, so several replicas
+// can run the job at the same time without paying an order twice.
+// The outcome of every transfer, including failures such as insufficient funds, is recorded
+// on the order's execution; a failed run is not retried.
+// An order above its from account's approval threshold isn't paid: a transfer request is made
+// instead, which an approver of the account must approve.
+func StandingOrderJob(store db.Store) JobFunc {
+	return func(ctx context.Context) error {
+		failed := 0
+		for i := 0; i < standingOrderBatchSize; i++ {
+			claim, err := store.ClaimStandingOrderTx(ctx, time.Now())
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("cannot claim standing order: %w", err)
+			}
+
+			order := claim.Order
+			arg := db.FinishStandingOrderExecutionParams{ID: claim.Execution.ID}
+			if err := runStandingOrder(ctx, store, order, &arg); err != nil {
+				log.Printf("standing order %d failed: %v", order.ID, err)
+				failed++
+				arg.Status = db.ExecutionStatusFailed
+				arg.Error = sql.NullString{String: err.Error(), Valid: true}
+			}
+
+			_, err = store.FinishStandingOrderExecution(ctx, arg)
+			if err != nil {
+				return fmt.Errorf("cannot record execution %d of standing order %d: %w", claim.Execution.ID, order.ID, err)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d standing orders failed", failed)
+		}
+		return nil
+	}
+}
+
+// runStandingOrder pays the order, or makes a transfer request for it if its amount is above the
+// from account's approval threshold, and sets the outcome on the execution.
+func runStandingOrder(ctx context.Context, store db.Store, order db.StandingOrders, execution *db.FinishStandingOrderExecutionParams) error {
+	fromAccount, err := store.GetAccount(ctx, order.FromAccountID)
+	if err != nil {
+		return err
+	}
+
+	// the threshold may have been lowered since the order was made
+	if db.NeedsApproval(fromAccount, order.Amount) {
+		request, err := store.CreateTransferRequestTx(ctx, db.CreateTransferRequestTxParams{
+			CreateTransferRequestParams: db.CreateTransferRequestParams{
+				FromAccountID: order.FromAccountID,
+				ToAccountID:   order.ToAccountID,
+				Amount:        order.Amount,
+				InitiatedBy:   order.Owner,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		execution.Status = db.ExecutionStatusPendingApproval
+		execution.TransferRequestID = sql.NullInt64{Int64: request.ID, Valid: true}
+		return nil
+	}
+
+	result, err := store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: order.FromAccountID,
+		ToAccountID:   order.ToAccountID,
+		Amount:        order.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	execution.Status = db.ExecutionStatusSucceeded
+	execution.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+	return nil
+}
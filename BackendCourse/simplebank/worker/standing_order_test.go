@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandingOrderJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	claims := []db.ClaimStandingOrderTxResult{
+		{
+			Order:     db.StandingOrders{ID: 1, Owner: "alice", FromAccountID: 10, ToAccountID: 20, Amount: 100},
+			Execution: db.StandingOrderExecutions{ID: 11, StandingOrderID: 1},
+		},
+		{
+			Order:     db.StandingOrders{ID: 2, Owner: "bob", FromAccountID: 30, ToAccountID: 20, Amount: 200},
+			Execution: db.StandingOrderExecutions{ID: 12, StandingOrderID: 2},
+		},
+		{
+			Order:     db.StandingOrders{ID: 3, Owner: "carol", FromAccountID: 40, ToAccountID: 20, Amount: 300},
+			Execution: db.StandingOrderExecutions{ID: 13, StandingOrderID: 3},
+		},
+	}
+
+	gomock.InOrder(
+		store.EXPECT().ClaimStandingOrderTx(gomock.Any(), gomock.Any()).Return(claims[0], nil),
+		store.EXPECT().ClaimStandingOrderTx(gomock.Any(), gomock.Any()).Return(claims[1], nil),
+		store.EXPECT().ClaimStandingOrderTx(gomock.Any(), gomock.Any()).Return(claims[2], nil),
+		store.EXPECT().ClaimStandingOrderTx(gomock.Any(), gomock.Any()).Return(db.ClaimStandingOrderTxResult{}, sql.ErrNoRows),
+	)
+
+	// the third order's account had its approval threshold lowered below the order's amount
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(10))).Times(1).Return(db.Accounts{ID: 10}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(30))).Times(1).Return(db.Accounts{ID: 30}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(40))).Times(1).
+		Return(db.Accounts{ID: 40, ApprovalThreshold: sql.NullInt64{Int64: 250, Valid: true}}, nil)
+
+	// the second order can't be paid
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			if arg.FromAccountID == 30 {
+				return db.TransferTxResult{}, db.ErrInsufficientFunds
+			}
+			require.Equal(t, int64(20), arg.ToAccountID)
+			require.Equal(t, int64(100), arg.Amount)
+			return db.TransferTxResult{Transfer: db.Transfers{ID: 99}}, nil
+		})
+
+	store.EXPECT().
+		CreateTransferRequestTx(gomock.Any(), gomock.Eq(db.CreateTransferRequestTxParams{
+			CreateTransferRequestParams: db.CreateTransferRequestParams{
+				FromAccountID: 40,
+				ToAccountID:   20,
+				Amount:        300,
+				InitiatedBy:   "carol",
+			},
+		})).
+		Times(1).
+		Return(db.TransferRequests{ID: 77}, nil)
+
+	store.EXPECT().
+		FinishStandingOrderExecution(gomock.Any(), gomock.Eq(db.FinishStandingOrderExecutionParams{
+			ID:         11,
+			Status:     db.ExecutionStatusSucceeded,
+			TransferID: sql.NullInt64{Int64: 99, Valid: true},
+		})).
+		Times(1)
+	store.EXPECT().
+		FinishStandingOrderExecution(gomock.Any(), gomock.Eq(db.FinishStandingOrderExecutionParams{
+			ID:     12,
+			Status: db.ExecutionStatusFailed,
+			Error:  sql.NullString{String: db.ErrInsufficientFunds.Error(), Valid: true},
+		})).
+		Times(1)
+	store.EXPECT().
+		FinishStandingOrderExecution(gomock.Any(), gomock.Eq(db.FinishStandingOrderExecutionParams{
+			ID:                13,
+			Status:            db.ExecutionStatusPendingApproval,
+			TransferRequestID: sql.NullInt64{Int64: 77, Valid: true},
+		})).
+		Times(1)
+
+	err := StandingOrderJob(store)(context.Background())
+	require.Error(t, err)
+}
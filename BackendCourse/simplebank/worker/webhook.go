@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	"github.com/ofer-sin/Courses/BackendCourse/simplebank/webhook"
+)
+
+const (
+	// webhookDeliveryBatchSize is how many due webhook deliveries are sent per run of the job
+	webhookDeliveryBatchSize = 20
+	// webhookClaimDuration is how long the job has to send the deliveries it claimed
+	// before another replica may claim them again, long enough for every one of them to time out
+	webhookClaimDuration = 5 * time.Minute
+	// webhookMaxAttempts is how many times a delivery is sent before it fails; it can then be replayed
+	webhookMaxAttempts = 10
+	// the delay before the first retry of a delivery; it doubles with every failed attempt up to the max
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+)
+
+// WebhookDeliveryJob returns a job that sends the due webhook deliveries to their endpoints.
+// Every attempt is added to the delivery log with the status the endpoint answered.
+// A failed attempt is retried with exponential backoff, until the delivery runs out of attempts.
+// Deleting an endpoint deletes its deliveries, so a delivery whose endpoint was deleted after it was claimed,
+// even while it was being sent, is skipped and the job moves on to the next one.
+func WebhookDeliveryJob(store db.Store, sender webhook.Sender) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		deliveries, err := store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+			ClaimedUntil: now.Add(webhookClaimDuration),
+			Now:          now,
+			MaxRows:      webhookDeliveryBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot claim webhook deliveries: %w", err)
+		}
+
+		failed := 0
+		endpoints := make(map[int64]db.WebhookEndpoints)
+		for _, delivery := range deliveries {
+			endpoint, ok := endpoints[delivery.EndpointID]
+			if !ok {
+				endpoint, err = store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+				if errors.Is(err, sql.ErrNoRows) {
+					// the endpoint was deleted after the delivery was claimed, and the delivery with it
+					log.Printf("webhook delivery %d skipped: its endpoint was deleted", delivery.ID)
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("cannot get endpoint of webhook delivery %d: %w", delivery.ID, err)
+				}
+				endpoints[endpoint.ID] = endpoint
+			}
+
+			arg := db.RecordWebhookAttemptTxParams{
+				DeliveryID: delivery.ID,
+				Status:     db.WebhookDeliverySucceeded,
+			}
+
+			arg.ResponseCode, err = sender.Send(ctx, endpoint, delivery)
+			if err != nil {
+				log.Printf("webhook delivery %d failed: %v", delivery.ID, err)
+				failed++
+				arg.Error = err.Error()
+
+				attempts := delivery.Attempts + 1
+				if attempts >= webhookMaxAttempts {
+					arg.Status = db.WebhookDeliveryFailed
+				} else {
+					arg.Status = db.WebhookDeliveryPending
+					arg.NextAttemptAt = time.Now().Add(retryDelay(webhookRetryBaseDelay, webhookRetryMaxDelay, attempts))
+				}
+			}
+			if arg.NextAttemptAt.IsZero() {
+				arg.NextAttemptAt = delivery.NextAttemptAt
+			}
+
+			_, err = store.RecordWebhookAttemptTx(ctx, arg)
+			if errors.Is(err, db.ErrWebhookDeliveryNotFound) {
+				// the endpoint was deleted, and the delivery with it, while it was being sent
+				log.Printf("webhook delivery %d not recorded: its endpoint was deleted", delivery.ID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("cannot record attempt of webhook delivery %d: %w", delivery.ID, err)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d webhook deliveries failed", failed)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/mock"
+	db "github.com/ofer-sin/Courses/BackendCourse/simplebank/db/sqlc"
+	mockwebhook "github.com/ofer-sin/Courses/BackendCourse/simplebank/webhook/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryJob(t *testing.T) {
+	endpoint := db.WebhookEndpoints{ID: 1, Owner: "merchant", Url: "https://merchant.example/hooks"}
+	nextAttemptAt := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name       string
+		attempts   int32
+		buildStubs func(store *mockdb.MockStore, sender *mockwebhook.MockSender, delivery db.WebhookDeliveries)
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name: "Succeeded",
+			buildStubs: func(store *mockdb.MockStore, sender *mockwebhook.MockSender, delivery db.WebhookDeliveries) {
+				sender.EXPECT().Send(gomock.Any(), gomock.Eq(endpoint), gomock.Eq(delivery)).Times(1).Return(int32(http.StatusOK), nil)
+				arg := db.RecordWebhookAttemptTxParams{
+					DeliveryID:    delivery.ID,
+					ResponseCode:  http.StatusOK,
+					Status:        db.WebhookDeliverySucceeded,
+					NextAttemptAt: nextAttemptAt,
+				}
+				store.EXPECT().RecordWebhookAttemptTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "Retried",
+			attempts: 2,
+			buildStubs: func(store *mockdb.MockStore, sender *mockwebhook.MockSender, delivery db.WebhookDeliveries) {
+				sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(int32(http.StatusInternalServerError), errors.New("endpoint answered 500 Internal Server Error"))
+				store.EXPECT().RecordWebhookAttemptTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptTxParams) (db.RecordWebhookAttemptTxResult, error) {
+						require.Equal(t, int32(http.StatusInternalServerError), arg.ResponseCode)
+						require.Equal(t, db.WebhookDeliveryPending, arg.Status)
+						require.Equal(t, "endpoint answered 500 Internal Server Error", arg.Error)
+						// the third attempt failed
+						require.WithinDuration(t, time.Now().Add(4*webhookRetryBaseDelay), arg.NextAttemptAt, time.Second)
+						return db.RecordWebhookAttemptTxResult{}, nil
+					})
+			},
+			check: func(t *testing.T, err error) {
+				require.EqualError(t, err, "1 webhook deliveries failed")
+			},
+		},
+		{
+			name:     "OutOfAttempts",
+			attempts: webhookMaxAttempts - 1,
+			buildStubs: func(store *mockdb.MockStore, sender *mockwebhook.MockSender, delivery db.WebhookDeliveries) {
+				sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(int32(0), errors.New("connection refused"))
+				arg := db.RecordWebhookAttemptTxParams{
+					DeliveryID:    delivery.ID,
+					Error:         "connection refused",
+					Status:        db.WebhookDeliveryFailed,
+					NextAttemptAt: nextAttemptAt,
+				}
+				store.EXPECT().RecordWebhookAttemptTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			check: func(t *testing.T, err error) {
+				require.EqualError(t, err, "1 webhook deliveries failed")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			delivery := db.WebhookDeliveries{
+				ID:            5,
+				EndpointID:    endpoint.ID,
+				EventID:       7,
+				EventType:     db.EventTypeTransferReceived,
+				Status:        db.WebhookDeliveryPending,
+				Attempts:      tc.attempts,
+				NextAttemptAt: nextAttemptAt,
+			}
+
+			store := mockdb.NewMockStore(ctrl)
+			sender := mockwebhook.NewMockSender(ctrl)
+			store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDeliveries{delivery}, nil)
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+			tc.buildStubs(store, sender, delivery)
+
+			err := WebhookDeliveryJob(store, sender)(context.Background())
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestWebhookDeliveryJobDeletedEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	endpoint := db.WebhookEndpoints{ID: 2, Owner: "merchant", Url: "https://merchant.example/hooks"}
+	deleted := db.WebhookDeliveries{ID: 5, EndpointID: 1, EventID: 7, Status: db.WebhookDeliveryPending}
+	next := db.WebhookDeliveries{ID: 6, EndpointID: endpoint.ID, EventID: 7, Status: db.WebhookDeliveryPending}
+
+	store := mockdb.NewMockStore(ctrl)
+	sender := mockwebhook.NewMockSender(ctrl)
+	store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDeliveries{deleted, next}, nil)
+
+	// the first delivery's endpoint is gone, so it is skipped without being sent
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(deleted.EndpointID)).Times(1).Return(db.WebhookEndpoints{}, sql.ErrNoRows)
+	sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Eq(deleted)).Times(0)
+
+	// and the rest of the batch is still sent
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	sender.EXPECT().Send(gomock.Any(), gomock.Eq(endpoint), gomock.Eq(next)).Times(1).Return(int32(http.StatusOK), nil)
+	store.EXPECT().RecordWebhookAttemptTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptTxParams) (db.RecordWebhookAttemptTxResult, error) {
+			require.Equal(t, next.ID, arg.DeliveryID)
+			require.Equal(t, db.WebhookDeliverySucceeded, arg.Status)
+			return db.RecordWebhookAttemptTxResult{}, nil
+		})
+
+	err := WebhookDeliveryJob(store, sender)(context.Background())
+	require.NoError(t, err)
+}
+
+func TestWebhookDeliveryJobEndpointDeletedWhileSending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	endpoint := db.WebhookEndpoints{ID: 1, Owner: "merchant", Url: "https://merchant.example/hooks"}
+	deleted := db.WebhookDeliveries{ID: 5, EndpointID: endpoint.ID, EventID: 7, Status: db.WebhookDeliveryPending}
+	next := db.WebhookDeliveries{ID: 6, EndpointID: endpoint.ID, EventID: 8, Status: db.WebhookDeliveryPending}
+
+	store := mockdb.NewMockStore(ctrl)
+	sender := mockwebhook.NewMockSender(ctrl)
+	store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDeliveries{deleted, next}, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	sender.EXPECT().Send(gomock.Any(), gomock.Eq(endpoint), gomock.Any()).Times(2).Return(int32(http.StatusOK), nil)
+
+	// the endpoint is deleted, with its deliveries, while the first one is being sent,
+	// so its attempt can't be recorded, and the rest of the batch is still recorded
+	gomock.InOrder(
+		store.EXPECT().RecordWebhookAttemptTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptTxParams) (db.RecordWebhookAttemptTxResult, error) {
+				require.Equal(t, deleted.ID, arg.DeliveryID)
+				return db.RecordWebhookAttemptTxResult{}, db.ErrWebhookDeliveryNotFound
+			}),
+		store.EXPECT().RecordWebhookAttemptTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptTxParams) (db.RecordWebhookAttemptTxResult, error) {
+				require.Equal(t, next.ID, arg.DeliveryID)
+				return db.RecordWebhookAttemptTxResult{}, nil
+			}),
+	)
+
+	err := WebhookDeliveryJob(store, sender)(context.Background())
+	require.NoError(t, err)
+}
+
+func TestWebhookDeliveryJobClaimError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sender := mockwebhook.NewMockSender(ctrl)
+	store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+	sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := WebhookDeliveryJob(store, sender)(context.Background())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+}